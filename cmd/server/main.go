@@ -10,9 +10,17 @@ import (
 	"syscall"
 	"time"
 
+	"geoanomaly/internal/achievements"
+	"geoanomaly/internal/collections"
 	"geoanomaly/internal/common"
+	"geoanomaly/internal/crafting"
 	"geoanomaly/internal/game"
+	"geoanomaly/internal/items"
 	"geoanomaly/internal/media"
+	"geoanomaly/internal/quests"
+	"geoanomaly/internal/wallet"
+	"geoanomaly/internal/xp"
+	"geoanomaly/pkg/database"
 	"geoanomaly/pkg/middleware"
 
 	"github.com/joho/godotenv"
@@ -321,7 +329,57 @@ func checkMigrations(db *gorm.DB) error {
 		log.Println("⚠️  No users found - database may need seeding")
 	}
 
-	log.Println("ℹ️  Using existing database schema (no migrations applied)")
+	log.Println("ℹ️  Using existing database schema (core tables unchanged)")
+
+	// ✅ NEW: Feature tables & columns (additive only)
+	if err := database.MigrateFeatureTables(db); err != nil {
+		return fmt.Errorf("feature tables migration failed: %w", err)
+	}
+	log.Println("✅ Feature tables migrated")
+
+	if err := achievements.SeedDefinitions(db); err != nil {
+		return fmt.Errorf("achievement seeding failed: %w", err)
+	}
+
+	if err := quests.SeedTemplates(db); err != nil {
+		return fmt.Errorf("quest template seeding failed: %w", err)
+	}
+
+	if err := collections.SeedSets(db); err != nil {
+		return fmt.Errorf("collection set seeding failed: %w", err)
+	}
+
+	if err := xp.SeedFormula(db); err != nil {
+		return fmt.Errorf("XP formula seeding failed: %w", err)
+	}
+
+	if err := game.SeedAreaYieldConfig(db); err != nil {
+		return fmt.Errorf("area yield config seeding failed: %w", err)
+	}
+
+	if err := items.SeedDefinitions(db); err != nil {
+		return fmt.Errorf("item catalog seeding failed: %w", err)
+	}
+
+	if err := items.BackfillInventory(db); err != nil {
+		return fmt.Errorf("inventory definition backfill failed: %w", err)
+	}
+
+	if err := items.BackfillLoadouts(db); err != nil {
+		return fmt.Errorf("loadout backfill failed: %w", err)
+	}
+
+	if err := items.BackfillCollects(db); err != nil {
+		return fmt.Errorf("collect history backfill failed: %w", err)
+	}
+
+	if err := crafting.SeedRecipes(db); err != nil {
+		return fmt.Errorf("crafting recipe seeding failed: %w", err)
+	}
+
+	if err := wallet.BackfillLedger(db); err != nil {
+		return fmt.Errorf("wallet ledger backfill failed: %w", err)
+	}
 
 	return nil
 }
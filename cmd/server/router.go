@@ -4,13 +4,24 @@ import (
 	"log"
 	"time"
 
+	"geoanomaly/internal/achievements"
 	"geoanomaly/internal/auth"
+	"geoanomaly/internal/collections"
 	"geoanomaly/internal/common"
+	"geoanomaly/internal/crafting"
 	"geoanomaly/internal/game"
 	"geoanomaly/internal/inventory"
+	"geoanomaly/internal/items"
 	"geoanomaly/internal/location"
+	"geoanomaly/internal/market"
 	"geoanomaly/internal/media"
+	"geoanomaly/internal/merchant"
+	"geoanomaly/internal/quests"
+	"geoanomaly/internal/seasons"
+	"geoanomaly/internal/trading"
 	"geoanomaly/internal/user"
+	"geoanomaly/internal/wallet"
+	"geoanomaly/internal/xp"
 	"geoanomaly/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -41,6 +52,22 @@ func setupRoutes(db *gorm.DB, redisClient *redis.Client, r2Client *media.R2Clien
 	gameHandler := game.NewHandler(db, nil)
 	locationHandler := location.NewHandler(db, nil)
 	inventoryHandler := inventory.NewHandler(db)
+	xpHandler := xp.NewHandler(db)
+	achievementHandler := achievements.NewHandler(db)
+	questHandler := quests.NewHandler(db)
+	seasonHandler := seasons.NewHandler(db)
+	collectionHandler := collections.NewHandler(db)
+	itemHandler := items.NewHandler(db)
+	craftingHandler := crafting.NewHandler(db)
+	tradingHandler := trading.NewHandler(db)
+	marketHandler := market.NewHandler(db)
+	walletHandler := wallet.NewHandler(db)
+	merchantHandler := merchant.NewHandler(db)
+
+	// Zdieľaná cache mapových dlaždíc medzi inštanciami
+	if redisClient != nil {
+		game.SetTileCacheRedis(redisClient)
+	}
 
 	// Initialize media service and handler
 	var mediaHandler *media.Handler
@@ -328,6 +355,21 @@ func setupRoutes(db *gorm.DB, redisClient *redis.Client, r2Client *media.R2Clien
 		userRoutes.POST("/location", userHandler.UpdateLocation)
 		userRoutes.GET("/location/history", userHandler.GetLocationHistory)
 		userRoutes.GET("/stats", userHandler.GetUserStats)
+		userRoutes.GET("/xp/history", xpHandler.GetXPHistory)
+		userRoutes.GET("/level/progress", xpHandler.GetLevelProgress)
+		userRoutes.GET("/achievements", achievementHandler.GetPlayerAchievements)
+		userRoutes.GET("/quests", questHandler.GetQuests)
+		userRoutes.POST("/quests/:id/claim", questHandler.ClaimQuest)
+		userRoutes.GET("/season", seasonHandler.GetCurrentSeason)
+		userRoutes.POST("/season/rewards/:id/claim", seasonHandler.ClaimSeasonReward)
+		userRoutes.GET("/collections", collectionHandler.GetCollections)
+		userRoutes.GET("/progression", gameHandler.GetProgression)
+		userRoutes.GET("/effects", itemHandler.GetActiveEffects)
+		userRoutes.GET("/loadout", itemHandler.GetLoadout)
+		userRoutes.PUT("/loadout", itemHandler.EquipLoadoutItem)
+		userRoutes.DELETE("/loadout/:slot", itemHandler.UnequipSlot)
+		userRoutes.GET("/wallet", walletHandler.GetWallet)
+		userRoutes.GET("/wallet/transactions", walletHandler.GetTransactions) // ?currency=&limit=&offset=
 	}
 
 	// ==========================================
@@ -343,6 +385,13 @@ func setupRoutes(db *gorm.DB, redisClient *redis.Client, r2Client *media.R2Clien
 		inventoryRoutes.PUT("/:id/favorite", inventoryHandler.SetFavorite)
 		inventoryRoutes.GET("/items/:id", inventoryHandler.GetItemDetail)
 		inventoryRoutes.PUT("/:id/equip", inventoryHandler.EquipItem)
+		inventoryRoutes.POST("/:id/repair", inventoryHandler.RepairItem)
+		inventoryRoutes.POST("/sell/quote", merchantHandler.QuoteSell)
+		inventoryRoutes.POST("/sell", merchantHandler.Sell) // {"item_ids"} alebo filter {"rarity": "common"}
+		inventoryRoutes.POST("/salvage/quote", merchantHandler.QuoteSalvage)
+		inventoryRoutes.POST("/salvage", merchantHandler.Salvage)
+		inventoryRoutes.GET("/overflow", itemHandler.GetOverflow)
+		inventoryRoutes.POST("/overflow/:id/claim", itemHandler.ClaimOverflow)
 	}
 
 	// ==========================================
@@ -352,6 +401,7 @@ func setupRoutes(db *gorm.DB, redisClient *redis.Client, r2Client *media.R2Clien
 	gameRoutes.Use(middleware.JWTAuth())
 	{
 		gameRoutes.POST("/scan-area", gameHandler.ScanArea)
+		gameRoutes.GET("/tiles/:z/:x/:y", gameHandler.GetMapTile) // /tiles/{z}/{x}/{y}.mvt
 
 		zoneRoutes := gameRoutes.Group("/zones")
 		{
@@ -360,6 +410,8 @@ func setupRoutes(db *gorm.DB, redisClient *redis.Client, r2Client *media.R2Clien
 			zoneRoutes.POST("/:id/enter", gameHandler.EnterZone)
 			zoneRoutes.POST("/:id/exit", gameHandler.ExitZone)
 			zoneRoutes.GET("/:id/scan", gameHandler.ScanZone)
+			zoneRoutes.POST("/:id/detect", gameHandler.DetectArtifacts)  // hidden artifacts - hot/cold signal
+			zoneRoutes.GET("/:id/geometry", gameHandler.GetZoneGeometry) // moving/shrinking zones
 			zoneRoutes.POST("/:id/collect", gameHandler.CollectItem)
 			zoneRoutes.GET("/:id/stats", gameHandler.GetZoneStats)
 		}
@@ -369,10 +421,55 @@ func setupRoutes(db *gorm.DB, redisClient *redis.Client, r2Client *media.R2Clien
 			itemRoutes.GET("/artifacts", gameHandler.GetAvailableArtifacts)
 			itemRoutes.GET("/gear", gameHandler.GetAvailableGear)
 			itemRoutes.POST("/use/:id", gameHandler.UseItem)
+			itemRoutes.GET("/catalog", itemHandler.GetCatalog) // ?category=&slot=
+		}
+
+		craftingRoutes := gameRoutes.Group("/crafting")
+		{
+			craftingRoutes.GET("/recipes", craftingHandler.GetRecipes) // ?craftable=true
+			craftingRoutes.POST("/recipes/:key/craft", craftingHandler.CraftRecipe)
+			craftingRoutes.GET("/jobs", craftingHandler.GetJobs)
+			craftingRoutes.POST("/jobs/:id/claim", craftingHandler.ClaimJob)
 		}
 
 		gameRoutes.GET("/leaderboard", gameHandler.GetLeaderboard)
 		gameRoutes.GET("/stats", gameHandler.GetGameStats)
+		gameRoutes.GET("/xp/rules", xpHandler.GetXPRules)
+		gameRoutes.GET("/achievements/stats", achievementHandler.GetAchievementStats)
+		gameRoutes.GET("/seasons/:id/rankings", seasonHandler.GetSeasonRankings)
+		gameRoutes.GET("/area/yield", gameHandler.GetAreaYield) // ?lat=&lng=
+	}
+
+	// ==========================================
+	// 🤝 TRADE ROUTES (Protected - JWT required)
+	// ==========================================
+	tradeRoutes := v1.Group("/trades")
+	tradeRoutes.Use(middleware.JWTAuth())
+	{
+		tradeRoutes.POST("", tradingHandler.CreateOffer)
+		tradeRoutes.GET("", tradingHandler.GetOffers) // ?direction=incoming|outgoing&status=
+		tradeRoutes.GET("/history", tradingHandler.GetTradeHistory)
+		tradeRoutes.GET("/:id", tradingHandler.GetOffer)
+		tradeRoutes.POST("/:id/accept", tradingHandler.AcceptOffer)
+		tradeRoutes.POST("/:id/decline", tradingHandler.DeclineOffer)
+		tradeRoutes.POST("/:id/cancel", tradingHandler.CancelOffer)
+		tradeRoutes.POST("/:id/counter", tradingHandler.CounterOffer)
+	}
+
+	// ==========================================
+	// 🏪 MARKET ROUTES (Protected - JWT required)
+	// ==========================================
+	marketRoutes := v1.Group("/market")
+	marketRoutes.Use(middleware.JWTAuth())
+	{
+		marketRoutes.GET("/listings", marketHandler.BrowseListings) // ?item_type=&rarity=&biome=&min_price=&max_price=&sort=
+		marketRoutes.POST("/listings", marketHandler.CreateListing)
+		marketRoutes.GET("/listings/:id", marketHandler.GetListing)
+		marketRoutes.DELETE("/listings/:id", marketHandler.CancelListing)
+		marketRoutes.POST("/listings/:id/buy", marketHandler.BuyListing)
+		marketRoutes.POST("/listings/:id/bid", marketHandler.PlaceBid)
+		marketRoutes.GET("/my/listings", marketHandler.GetMyListings)
+		marketRoutes.GET("/prices/:key", marketHandler.GetPriceHistory) // ?days=30
 	}
 
 	// ==========================================
@@ -405,13 +502,41 @@ func setupRoutes(db *gorm.DB, redisClient *redis.Client, r2Client *media.R2Clien
 		adminRoutes.POST("/zones/:id/spawn/gear", gameHandler.SpawnGear)
 		adminRoutes.POST("/zones/cleanup", gameHandler.CleanupExpiredZones)
 		adminRoutes.GET("/zones/expired", gameHandler.GetExpiredZones)
+		adminRoutes.GET("/zones/export", gameHandler.ExportZonesGeoJSON)  // GeoJSON FeatureCollection
+		adminRoutes.POST("/zones/import", gameHandler.ImportZonesGeoJSON) // ?dry_run=true
 		adminRoutes.GET("/users", userHandler.GetAllUsers)
 		adminRoutes.PUT("/users/:id/tier", userHandler.UpdateUserTier)
 		adminRoutes.POST("/users/:id/ban", userHandler.BanUser)
 		adminRoutes.POST("/users/:id/unban", userHandler.UnbanUser)
+		adminRoutes.GET("/users/:id/xp/history", xpHandler.GetUserXPHistory)
+		adminRoutes.POST("/xp/:id/reverse", xpHandler.ReverseXPEntry) // fraud cleanup
+
+		// ✅ NEW: XP formula & events
+		adminRoutes.GET("/xp/formula", xpHandler.GetXPFormula)
+		adminRoutes.PUT("/xp/formula", xpHandler.UpdateXPFormula)
+		adminRoutes.GET("/xp/events", xpHandler.ListXPEvents)
+		adminRoutes.POST("/xp/events", xpHandler.CreateXPEvent)
+		adminRoutes.DELETE("/xp/events/:id", xpHandler.DeleteXPEvent)
+		adminRoutes.POST("/xp/dry-run", xpHandler.DryRunArtifactXP) // balancing
+		adminRoutes.GET("/yield/config", gameHandler.GetAreaYieldConfig)
+		adminRoutes.PUT("/yield/config", gameHandler.UpdateAreaYieldConfig)
+
+		// ✅ NEW: Seasons
+		adminRoutes.GET("/seasons", seasonHandler.ListSeasons)
+		adminRoutes.POST("/seasons", seasonHandler.CreateSeason)
+		adminRoutes.PUT("/seasons/:id/rewards", seasonHandler.SetSeasonRewards)
+		adminRoutes.POST("/seasons/:id/end", seasonHandler.EndSeason)
 		adminRoutes.GET("/analytics/zones", gameHandler.GetZoneAnalytics)
 		adminRoutes.GET("/analytics/players", userHandler.GetPlayerAnalytics)
 		adminRoutes.GET("/analytics/items", gameHandler.GetItemAnalytics)
+		adminRoutes.GET("/trades", tradingHandler.GetFlaggedTrades) // ?review=pending
+		adminRoutes.PUT("/trades/:id/review", tradingHandler.ReviewTrade)
+		adminRoutes.GET("/users/:id/wallet", walletHandler.GetUserWallet)
+		adminRoutes.POST("/users/:id/wallet/grant", walletHandler.GrantCurrency)
+		adminRoutes.GET("/wallet/reconcile", walletHandler.ReconcileLedger)
+		adminRoutes.GET("/market/sales", marketHandler.GetSales) // ?user_id=&rolled_back=true
+		adminRoutes.POST("/market/listings/:id/delist", marketHandler.DelistListing)
+		adminRoutes.POST("/market/sales/:id/rollback", marketHandler.RollbackSale)
 
 		// Security admin endpoints
 		securityRoutes := adminRoutes.Group("/security")
@@ -497,13 +622,98 @@ func setupRoutes(db *gorm.DB, redisClient *redis.Client, r2Client *media.R2Clien
 				"security":  "🛡️ CONNECT attacks blocked",
 				"endpoints": gin.H{
 					"media": mediaEndpoints,
+					"game": gin.H{
+						"GET /game/tiles/{z}/{x}/{y}.mvt":         "🗺️ Vector tile (zones, density, items)",
+						"POST /game/zones/{id}/detect":            "📡 Detector signal for hidden artifacts",
+						"GET /game/zones/{id}/geometry":           "🌀 Current zone center, radius and velocity",
+						"GET /game/xp/rules":                      "📏 XP rules (gear, discovery, distance, streak...)",
+						"GET /game/achievements/stats":            "🏆 Global achievement unlock percentages",
+						"GET /game/seasons/{id}/rankings":         "🥇 Season rankings (final after snapshot)",
+						"GET /game/area/yield":                    "🌾 Area depletion indicator for a location",
+						"GET /game/items/catalog":                 "📦 Item catalog (category, slot, stats, stack size)",
+						"POST /game/items/use/{id}":               "⚡ Use item (same effects as /inventory/{id}/use)",
+						"GET /game/crafting/recipes":              "🛠️ Crafting recipes with ingredients you own",
+						"POST /game/crafting/recipes/{key}/craft": "🛠️ Craft recipe (consumes ingredients)",
+						"GET /game/crafting/jobs":                 "⏳ Crafting in progress and finished",
+						"POST /game/crafting/jobs/{id}/claim":     "📦 Collect finished crafting",
+					},
+					"admin": gin.H{
+						"GET /admin/zones/export":                 "🗺️ Export zones as GeoJSON (bbox, zone_type, biome, tier)",
+						"POST /admin/zones/import":                "📥 Import zones from GeoJSON (dry_run supported)",
+						"GET /admin/users/{id}/xp/history":        "📜 XP ledger of a user",
+						"POST /admin/xp/{id}/reverse":             "↩️ Reverse XP ledger entry",
+						"GET /admin/seasons":                      "🗓️ List seasons",
+						"POST /admin/seasons":                     "🗓️ Create season",
+						"PUT /admin/seasons/{id}/rewards":         "🎁 Define free and premium reward tracks",
+						"POST /admin/seasons/{id}/end":            "🏁 End season and snapshot rankings",
+						"GET /admin/xp/formula":                   "🧮 Artifact XP formula and active events",
+						"PUT /admin/xp/formula":                   "🧮 Update artifact XP formula",
+						"GET /admin/xp/events":                    "🎉 List XP events",
+						"POST /admin/xp/events":                   "🎉 Create XP event (multiplier, optional biome)",
+						"DELETE /admin/xp/events/{id}":            "🗑️ Delete XP event",
+						"POST /admin/xp/dry-run":                  "🧪 Preview XP for a collect (no writes)",
+						"GET /admin/yield/config":                 "🌾 Area depletion (anti-farming) parameters",
+						"PUT /admin/yield/config":                 "🌾 Update area depletion parameters",
+						"GET /admin/trades":                       "🚩 Trades flagged by fraud checks",
+						"PUT /admin/trades/{id}/review":           "🛡️ Clear or confirm flagged trade",
+						"GET /admin/users/{id}/wallet":            "💳 User balances and ledger history",
+						"POST /admin/users/{id}/wallet/grant":     "💳 Grant or revoke currency (ledgered)",
+						"GET /admin/wallet/reconcile":             "🧮 Check ledger invariants",
+						"GET /admin/market/sales":                 "🧾 Market sales (user_id, rolled_back)",
+						"POST /admin/market/listings/{id}/delist": "🚫 Delist market listing (refunds bids)",
+						"POST /admin/market/sales/{id}/rollback":  "↩️ Roll back fraudulent sale",
+					},
+					"trades": gin.H{
+						"POST /trades":              "🤝 Offer items to another player (items go to escrow)",
+						"GET /trades":               "📬 Pending offers (direction=incoming|outgoing)",
+						"GET /trades/history":       "📜 Finished trades",
+						"GET /trades/{id}":          "🔍 Trade offer detail",
+						"POST /trades/{id}/accept":  "✅ Accept offer (atomic item swap)",
+						"POST /trades/{id}/decline": "❌ Decline offer",
+						"POST /trades/{id}/cancel":  "↩️ Cancel own offer",
+						"POST /trades/{id}/counter": "🔁 Counter offer",
+					},
+					"market": gin.H{
+						"GET /market/listings":           "🏪 Browse listings (item_type, rarity, biome, price, sort)",
+						"POST /market/listings":          "🏷️ List item at fixed price or as auction (listing fee)",
+						"GET /market/listings/{id}":      "🔍 Listing detail",
+						"DELETE /market/listings/{id}":   "↩️ Cancel own listing",
+						"POST /market/listings/{id}/buy": "💰 Buy fixed-price listing",
+						"POST /market/listings/{id}/bid": "🔨 Bid on auction",
+						"GET /market/my/listings":        "📋 Own listings",
+						"GET /market/prices/{key}":       "📈 Daily price history of an item type",
+					},
+					"user": gin.H{
+						"GET /user/xp/history":                 "📜 XP history (ledger)",
+						"GET /user/level/progress":             "📈 Progress to next level and upcoming unlocks",
+						"GET /user/achievements":               "🏆 Achievements with tier progress",
+						"GET /user/quests":                     "📜 Daily and weekly quests (player timezone)",
+						"POST /user/quests/{id}/claim":         "🎁 Claim completed quest reward",
+						"GET /user/season":                     "🗓️ Current season track and progress",
+						"POST /user/season/rewards/{id}/claim": "🎁 Claim season reward",
+						"GET /user/collections":                "🧩 Artifact collection sets and passive bonuses",
+						"GET /user/progression":                "🔓 Biome, rarity and gear unlocks by tier or level",
+						"GET /user/effects":                    "🧪 Active item effects (timed buffs)",
+						"GET /user/loadout":                    "🎽 Equipped gear per slot and effective stats",
+						"PUT /user/loadout":                    "🎽 Equip or swap gear (slot from item catalog)",
+						"DELETE /user/loadout/{slot}":          "🎽 Unequip slot",
+						"GET /user/wallet":                     "💳 Credits and crystals balance",
+						"GET /user/wallet/transactions":        "📜 Wallet ledger history",
+					},
 					"inventory": gin.H{
-						"GET /inventory/items":         "🎒 Get user inventory (with images)",
-						"GET /inventory/summary":       "📊 Get inventory summary",
-						"DELETE /inventory/{id}":       "🗑️ Delete inventory item",
-						"POST /inventory/{id}/use":     "⚡ Use inventory item",
-						"PUT /inventory/{id}/favorite": "⭐ Set favorite item",
-						"PUT /inventory/{id}/equip":    "⚔️ Equip item",
+						"GET /inventory/items":                "🎒 Get user inventory (with images)",
+						"GET /inventory/summary":              "📊 Get inventory summary",
+						"DELETE /inventory/{id}":              "🗑️ Delete inventory item",
+						"POST /inventory/{id}/use":            "⚡ Use inventory item",
+						"PUT /inventory/{id}/favorite":        "⭐ Set favorite item",
+						"PUT /inventory/{id}/equip":           "⚔️ Equip item",
+						"POST /inventory/{id}/repair":         "🔧 Repair gear with artifacts or currency",
+						"POST /inventory/sell/quote":          "🏷️ Preview vendor price (confirm token for rare items)",
+						"POST /inventory/sell":                "💰 Sell items or all matching a filter to the vendor",
+						"POST /inventory/salvage/quote":       "🔍 Preview salvage materials",
+						"POST /inventory/salvage":             "🔧 Salvage gear and artifacts into crafting materials",
+						"GET /inventory/overflow":             "📦 Items collected while the inventory was full (expire)",
+						"POST /inventory/overflow/{id}/claim": "📥 Move overflow item into inventory",
 					},
 					"security": gin.H{
 						"GET /security/status":               "🛡️ Security status",
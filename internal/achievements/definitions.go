@@ -0,0 +1,100 @@
+package achievements
+
+import (
+	"log"
+
+	"geoanomaly/internal/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Predvolené achievementy - zapíšu sa do achievement_definitions, ak tam ešte nie sú.
+// Úpravy (ciele, odmeny, nové achievementy) sa robia priamo v DB.
+var defaultDefinitions = []common.AchievementDefinition{
+	{
+		Key:         "forest_collector",
+		Name:        "Forest Collector",
+		Description: "Collect artifacts in forest biomes",
+		Event:       EventCollect,
+		Metric:      MetricCount,
+		Filter:      common.JSONB{"item_type": "artifact", "biome": "forest"},
+		Tiers:       tiers(10, 50, 100, 50, 150, 400),
+	},
+	{
+		Key:         "legendary_finder",
+		Name:        "Legend Hunter",
+		Description: "Find legendary artifacts",
+		Event:       EventCollect,
+		Metric:      MetricCount,
+		Filter:      common.JSONB{"item_type": "artifact", "rarity": "legendary"},
+		Tiers:       tiers(1, 5, 20, 100, 300, 1000),
+	},
+	{
+		Key:         "gear_hoarder",
+		Name:        "Gear Hoarder",
+		Description: "Collect gear",
+		Event:       EventCollect,
+		Metric:      MetricCount,
+		Filter:      common.JSONB{"item_type": "gear"},
+		Tiers:       tiers(10, 50, 150, 50, 150, 400),
+	},
+	{
+		Key:           "radiation_walker",
+		Name:          "Radiation Walker",
+		Description:   "Enter different radioactive zones",
+		Event:         EventEnterZone,
+		Metric:        MetricDistinct,
+		DistinctField: "zone_id",
+		Filter:        common.JSONB{"biome": "radioactive"},
+		Tiers:         tiers(1, 10, 25, 50, 150, 400),
+	},
+	{
+		Key:           "biome_traveler",
+		Name:          "Biome Traveler",
+		Description:   "Visit different biomes",
+		Event:         EventEnterZone,
+		Metric:        MetricDistinct,
+		DistinctField: "biome",
+		Tiers:         tiers(3, 5, 7, 75, 200, 500),
+	},
+	{
+		Key:         "zone_pioneer",
+		Name:        "Zone Pioneer",
+		Description: "Discover new zones",
+		Event:       EventDiscoverZone,
+		Metric:      MetricCount,
+		Tiers:       tiers(10, 50, 200, 50, 200, 600),
+	},
+	{
+		Key:         "seasoned_explorer",
+		Name:        "Seasoned Explorer",
+		Description: "Reach higher levels",
+		Event:       EventLevelUp,
+		Metric:      MetricMax,
+		Tiers:       tiers(5, 10, 20, 100, 300, 1000),
+	},
+}
+
+func tiers(bronze, silver, gold, bronzeXP, silverXP, goldXP int) common.JSONB {
+	return common.JSONB{
+		TierBronze: map[string]interface{}{"target": bronze, "xp": bronzeXP},
+		TierSilver: map[string]interface{}{"target": silver, "xp": silverXP},
+		TierGold:   map[string]interface{}{"target": gold, "xp": goldXP},
+	}
+}
+
+// SeedDefinitions - doplní chýbajúce predvolené achievementy (existujúce nemení)
+func SeedDefinitions(db *gorm.DB) error {
+	for _, def := range defaultDefinitions {
+		def := def
+		result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).Create(&def)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("🏆 Seeded achievement: %s", def.Key)
+		}
+	}
+	return nil
+}
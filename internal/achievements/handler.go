@@ -0,0 +1,148 @@
+package achievements
+
+import (
+	"math"
+	"net/http"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// GetPlayerAchievements - GET /user/achievements
+func (h *Handler) GetPlayerAchievements(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var defs []common.AchievementDefinition
+	if err := h.db.Where("is_active = true").Order("key ASC").Find(&defs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch achievements"})
+		return
+	}
+
+	var progressRows []common.PlayerAchievement
+	h.db.Where("user_id = ?", userID.(uuid.UUID)).Find(&progressRows)
+	progressByKey := make(map[string]common.PlayerAchievement, len(progressRows))
+	for _, row := range progressRows {
+		progressByKey[row.AchievementKey] = row
+	}
+
+	views := make([]PlayerAchievementView, 0, len(defs))
+	completed := 0
+	for _, def := range defs {
+		view := buildView(def, progressByKey[def.Key])
+		if view.Completed {
+			completed++
+		}
+		views = append(views, view)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"achievements": views,
+		"total":        len(views),
+		"completed":    completed,
+	})
+}
+
+// GetAchievementStats - GET /game/achievements/stats
+// Percento aktívnych hráčov, ktorí odomkli jednotlivé tiery
+func (h *Handler) GetAchievementStats(c *gin.Context) {
+	var defs []common.AchievementDefinition
+	if err := h.db.Where("is_active = true").Order("key ASC").Find(&defs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch achievements"})
+		return
+	}
+
+	var totalPlayers int64
+	h.db.Model(&common.User{}).Where("is_active = true").Count(&totalPlayers)
+
+	var rows []struct {
+		AchievementKey string
+		Bronze         int64
+		Silver         int64
+		Gold           int64
+	}
+	h.db.Model(&common.PlayerAchievement{}).
+		Select(`achievement_key,
+			COUNT(*) FILTER (WHERE unlocked_tiers->>'bronze' IS NOT NULL) AS bronze,
+			COUNT(*) FILTER (WHERE unlocked_tiers->>'silver' IS NOT NULL) AS silver,
+			COUNT(*) FILTER (WHERE unlocked_tiers->>'gold' IS NOT NULL) AS gold`).
+		Group("achievement_key").
+		Scan(&rows)
+
+	counts := make(map[string]map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.AchievementKey] = map[string]int64{
+			TierBronze: row.Bronze,
+			TierSilver: row.Silver,
+			TierGold:   row.Gold,
+		}
+	}
+
+	stats := make([]AchievementStats, 0, len(defs))
+	for _, def := range defs {
+		entry := AchievementStats{
+			Key:        def.Key,
+			Name:       def.Name,
+			Unlocked:   map[string]int64{},
+			Percentage: map[string]float64{},
+		}
+		for _, tier := range parseTiers(def.Tiers) {
+			unlocked := counts[def.Key][tier.Name]
+			entry.Unlocked[tier.Name] = unlocked
+			entry.Percentage[tier.Name] = unlockPercentage(unlocked, totalPlayers)
+		}
+		stats = append(stats, entry)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"achievements":  stats,
+		"total_players": totalPlayers,
+	})
+}
+
+func buildView(def common.AchievementDefinition, progress common.PlayerAchievement) PlayerAchievementView {
+	view := PlayerAchievementView{
+		Key:           def.Key,
+		Name:          def.Name,
+		Description:   def.Description,
+		Progress:      progress.Progress,
+		Tier:          progress.Tier,
+		Tiers:         parseTiers(def.Tiers),
+		UnlockedTiers: map[string]interface{}{},
+	}
+	for tier, unlockedAt := range progress.UnlockedTiers {
+		view.UnlockedTiers[tier] = unlockedAt
+	}
+
+	for _, tier := range view.Tiers {
+		if _, done := view.UnlockedTiers[tier.Name]; !done {
+			view.NextTier = tier.Name
+			view.NextTarget = tier.Target
+			return view
+		}
+	}
+
+	view.Completed = len(view.Tiers) > 0
+	return view
+}
+
+func unlockPercentage(unlocked, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(unlocked)/float64(total)*1000) / 10
+}
@@ -0,0 +1,260 @@
+package achievements
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/xp"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Track - započíta udalosť do všetkých aktívnych achievementov daného typu
+// a udelí odmeny za novo odomknuté tiery
+func (h *Handler) Track(userID uuid.UUID, event Event) []Unlock {
+	var defs []common.AchievementDefinition
+	if err := h.db.Where("event = ? AND is_active = true", event.Type).Find(&defs).Error; err != nil {
+		log.Printf("❌ Failed to load achievements: %v", err)
+		return nil
+	}
+
+	var unlocks []Unlock
+	for _, def := range defs {
+		if !matchesFilter(def.Filter, event) {
+			continue
+		}
+
+		newTiers, err := h.advance(userID, def, event)
+		if err != nil {
+			log.Printf("❌ Failed to update achievement %s: %v", def.Key, err)
+			continue
+		}
+
+		for _, tier := range newTiers {
+			unlocks = append(unlocks, h.rewardTier(userID, def, tier))
+		}
+	}
+
+	return unlocks
+}
+
+// TrackXPResults - level-up z ľubovoľného XP grantu je udalosť pre level achievementy
+func (h *Handler) TrackXPResults(userID uuid.UUID, results ...*xp.XPResult) []Unlock {
+	var unlocks []Unlock
+	for _, result := range results {
+		if result != nil && result.LevelUp {
+			unlocks = append(unlocks, h.Track(userID, Event{Type: EventLevelUp, Level: result.CurrentLevel})...)
+		}
+	}
+	return unlocks
+}
+
+// advance - posunie progress (so zámkom riadku) a vráti tiery odomknuté touto udalosťou
+func (h *Handler) advance(userID uuid.UUID, def common.AchievementDefinition, event Event) ([]TierSpec, error) {
+	tierSpecs := parseTiers(def.Tiers)
+	var newTiers []TierSpec
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		initial := common.PlayerAchievement{
+			UserID:         userID,
+			AchievementKey: def.Key,
+			Seen:           common.JSONB{},
+			UnlockedTiers:  common.JSONB{},
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&initial).Error; err != nil {
+			return err
+		}
+
+		var progress common.PlayerAchievement
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND achievement_key = ?", userID, def.Key).
+			First(&progress).Error; err != nil {
+			return err
+		}
+		if progress.Seen == nil {
+			progress.Seen = common.JSONB{}
+		}
+		if progress.UnlockedTiers == nil {
+			progress.UnlockedTiers = common.JSONB{}
+		}
+
+		if !applyMetric(def, &progress, event) {
+			return nil
+		}
+
+		for _, tier := range tierSpecs {
+			if _, done := progress.UnlockedTiers[tier.Name]; done || progress.Progress < tier.Target {
+				continue
+			}
+			progress.UnlockedTiers[tier.Name] = time.Now().Unix()
+			progress.Tier = tier.Name
+			newTiers = append(newTiers, tier)
+
+			if tier.Item != nil {
+				if err := tx.Create(achievementRewardItem(userID, def, tier)).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Model(&progress).Updates(map[string]interface{}{
+			"progress":       progress.Progress,
+			"seen":           progress.Seen,
+			"tier":           progress.Tier,
+			"unlocked_tiers": progress.UnlockedTiers,
+		}).Error
+	})
+
+	return newTiers, err
+}
+
+// applyMetric - vráti false, ak sa progress nezmenil
+func applyMetric(def common.AchievementDefinition, progress *common.PlayerAchievement, event Event) bool {
+	switch def.Metric {
+	case MetricMax:
+		if event.Level <= progress.Progress {
+			return false
+		}
+		progress.Progress = event.Level
+	case MetricDistinct:
+		value := eventField(event, def.DistinctField)
+		if value == "" {
+			return false
+		}
+		if _, seen := progress.Seen[value]; seen {
+			return false
+		}
+		progress.Seen[value] = true
+		progress.Progress = len(progress.Seen)
+	default:
+		progress.Progress++
+	}
+	return true
+}
+
+// XP odmena cez ledger (idempotentná podľa achievement:tier)
+func (h *Handler) rewardTier(userID uuid.UUID, def common.AchievementDefinition, tier TierSpec) Unlock {
+	unlock := Unlock{Key: def.Key, Name: def.Name, Tier: tier.Name}
+	if name, ok := tier.Item["name"].(string); ok {
+		unlock.Item = name
+	}
+
+	log.Printf("🏆 Achievement unlocked: user %s - %s (%s)", userID, def.Name, tier.Name)
+
+	if tier.XP <= 0 {
+		return unlock
+	}
+
+	result, err := xp.NewHandler(h.db).Grant(xp.XPGrant{
+		UserID:     userID,
+		SourceType: xpSourceAchievement,
+		SourceID:   fmt.Sprintf("%s:%s", def.Key, tier.Name),
+		Amount:     tier.XP,
+		Reason:     fmt.Sprintf("Achievement %s (%s)", def.Name, tier.Name),
+	})
+	if err != nil {
+		log.Printf("❌ Failed to grant achievement XP: %v", err)
+		return unlock
+	}
+	if !result.Duplicate {
+		unlock.XPReward = result.XPGained
+	}
+
+	// XP odmena môže priniesť level-up
+	h.TrackXPResults(userID, result)
+
+	return unlock
+}
+
+func achievementRewardItem(userID uuid.UUID, def common.AchievementDefinition, tier TierSpec) *common.InventoryItem {
+	itemType, _ := tier.Item["item_type"].(string)
+	if itemType == "" {
+		itemType = "artifact"
+	}
+
+	properties := common.JSONB{}
+	for key, value := range tier.Item {
+		if key != "item_type" {
+			properties[key] = value
+		}
+	}
+	properties["achievement_reward"] = fmt.Sprintf("%s:%s", def.Key, tier.Name)
+	properties["acquired_at"] = time.Now().Unix()
+
+	return &common.InventoryItem{
+		UserID:        userID,
+		ItemType:      itemType,
+		ItemID:        uuid.New(),
+		Quantity:      1,
+		Properties:    properties,
+		DefinitionKey: common.ItemDefinitionKey(itemType, properties),
+	}
+}
+
+func matchesFilter(filter common.JSONB, event Event) bool {
+	for key, expected := range filter {
+		switch key {
+		case "min_zone_tier":
+			if minTier, ok := expected.(float64); ok && event.ZoneTier < int(minTier) {
+				return false
+			}
+		default:
+			value, ok := expected.(string)
+			if !ok || !strings.EqualFold(eventField(event, key), value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func eventField(event Event, field string) string {
+	switch field {
+	case "item_type":
+		return event.ItemType
+	case "biome":
+		return event.Biome
+	case "rarity":
+		return event.Rarity
+	case "zone_id":
+		return event.ZoneID
+	default:
+		return ""
+	}
+}
+
+// parseTiers - tiery v poradí bronze, silver, gold (chýbajúce sa vynechajú)
+func parseTiers(raw common.JSONB) []TierSpec {
+	var specs []TierSpec
+	for _, name := range tierOrder {
+		data, ok := raw[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		spec := TierSpec{Name: name, Target: toInt(data["target"]), XP: toInt(data["xp"])}
+		if item, ok := data["item"].(map[string]interface{}); ok {
+			spec.Item = item
+		}
+		if spec.Target > 0 {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+func toInt(value interface{}) int {
+	switch v := value.(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,68 @@
+package achievements
+
+import (
+	"testing"
+
+	"geoanomaly/internal/common"
+)
+
+func TestMatchesFilter(t *testing.T) {
+	filter := common.JSONB{"item_type": "artifact", "biome": "forest", "min_zone_tier": float64(2)}
+
+	if !matchesFilter(filter, Event{ItemType: "artifact", Biome: "Forest", ZoneTier: 2}) {
+		t.Error("expected matching event to pass filter")
+	}
+	if matchesFilter(filter, Event{ItemType: "gear", Biome: "forest", ZoneTier: 2}) {
+		t.Error("expected gear to be rejected by artifact filter")
+	}
+	if matchesFilter(filter, Event{ItemType: "artifact", Biome: "forest", ZoneTier: 1}) {
+		t.Error("expected low tier zone to be rejected")
+	}
+	if !matchesFilter(nil, Event{Type: EventDiscoverZone}) {
+		t.Error("empty filter should match everything")
+	}
+}
+
+func TestApplyMetric(t *testing.T) {
+	distinct := common.AchievementDefinition{Metric: MetricDistinct, DistinctField: "biome"}
+	progress := &common.PlayerAchievement{Seen: common.JSONB{}}
+	for _, biome := range []string{"forest", "urban", "forest"} {
+		applyMetric(distinct, progress, Event{Biome: biome})
+	}
+	if progress.Progress != 2 {
+		t.Errorf("distinct progress = %d; want 2", progress.Progress)
+	}
+
+	zones := common.AchievementDefinition{Metric: MetricDistinct, DistinctField: "zone_id"}
+	progress = &common.PlayerAchievement{Seen: common.JSONB{}}
+	for _, zoneID := range []string{"zone-a", "zone-a", "zone-b", "zone-a"} {
+		applyMetric(zones, progress, Event{Type: EventEnterZone, ZoneID: zoneID, Biome: "radioactive"})
+	}
+	if progress.Progress != 2 {
+		t.Errorf("re-entering the same zone must not count, progress = %d; want 2", progress.Progress)
+	}
+
+	max := common.AchievementDefinition{Metric: MetricMax}
+	progress = &common.PlayerAchievement{Progress: 7}
+	if applyMetric(max, progress, Event{Level: 5}) || progress.Progress != 7 {
+		t.Errorf("lower level must not reduce max progress, got %d", progress.Progress)
+	}
+	if !applyMetric(max, progress, Event{Level: 9}) || progress.Progress != 9 {
+		t.Errorf("max progress = %d; want 9", progress.Progress)
+	}
+}
+
+func TestParseTiersOrder(t *testing.T) {
+	specs := parseTiers(common.JSONB{
+		TierGold:   map[string]interface{}{"target": float64(100), "xp": float64(400)},
+		TierBronze: map[string]interface{}{"target": float64(10), "xp": float64(50)},
+		TierSilver: map[string]interface{}{"target": float64(0)},
+	})
+
+	if len(specs) != 2 || specs[0].Name != TierBronze || specs[1].Name != TierGold {
+		t.Fatalf("unexpected tiers: %+v", specs)
+	}
+	if specs[1].Target != 100 || specs[1].XP != 400 {
+		t.Errorf("unexpected gold tier: %+v", specs[1])
+	}
+}
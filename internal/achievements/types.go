@@ -0,0 +1,78 @@
+package achievements
+
+// Herné udalosti, z ktorých sa počíta postup
+const (
+	EventCollect      = "collect"
+	EventEnterZone    = "enter_zone"
+	EventDiscoverZone = "discover_zone"
+	EventLevelUp      = "level_up"
+)
+
+// Ako sa z udalostí počíta progress
+const (
+	MetricCount    = "count"    // +1 za každú udalosť
+	MetricMax      = "max"      // najvyššia hodnota (level)
+	MetricDistinct = "distinct" // počet rôznych hodnôt DistinctField
+)
+
+const (
+	TierBronze = "bronze"
+	TierSilver = "silver"
+	TierGold   = "gold"
+)
+
+// Poradie tierov od najnižšieho
+var tierOrder = []string{TierBronze, TierSilver, TierGold}
+
+// XP source type pre odmeny za achievementy (xp_ledger.source_type)
+const xpSourceAchievement = "achievement"
+
+// Event - jedna herná udalosť
+type Event struct {
+	Type     string
+	ZoneID   string
+	ItemType string
+	Biome    string
+	Rarity   string
+	ZoneTier int
+	Level    int
+}
+
+// TierSpec - rozparsovaný tier z AchievementDefinition.Tiers
+type TierSpec struct {
+	Name   string                 `json:"name"`
+	Target int                    `json:"target"`
+	XP     int                    `json:"xp,omitempty"`
+	Item   map[string]interface{} `json:"item,omitempty"`
+}
+
+// Unlock - tier odomknutý touto udalosťou (vracia sa klientovi)
+type Unlock struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Tier     string `json:"tier"`
+	XPReward int    `json:"xp_reward,omitempty"`
+	Item     string `json:"item_reward,omitempty"`
+}
+
+// PlayerAchievementView - GET /user/achievements
+type PlayerAchievementView struct {
+	Key           string                 `json:"key"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description,omitempty"`
+	Progress      int                    `json:"progress"`
+	Tier          string                 `json:"tier,omitempty"`
+	NextTier      string                 `json:"next_tier,omitempty"`
+	NextTarget    int                    `json:"next_target,omitempty"`
+	Tiers         []TierSpec             `json:"tiers"`
+	UnlockedTiers map[string]interface{} `json:"unlocked_tiers"`
+	Completed     bool                   `json:"completed"`
+}
+
+// AchievementStats - GET /game/achievements/stats
+type AchievementStats struct {
+	Key        string             `json:"key"`
+	Name       string             `json:"name"`
+	Unlocked   map[string]int64   `json:"unlocked"`
+	Percentage map[string]float64 `json:"percentage"`
+}
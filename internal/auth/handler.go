@@ -3,8 +3,11 @@ package auth
 import (
 	"context"
 	"fmt"
+	"geoanomaly/internal/achievements"
 	"geoanomaly/internal/common"
+	"geoanomaly/internal/xp"
 	"geoanomaly/pkg/middleware"
+	"log"
 	"net/http"
 	"time"
 
@@ -153,9 +156,14 @@ func (h *Handler) Login(c *gin.Context) {
 	now := time.Now()
 	h.db.Model(&user).Updates(map[string]interface{}{
 		"updated_at": now,
-		// "last_login": now, // Uncomment if you add this field to User model
 	})
 
+	// ✅ NEW: Last login + denný login streak XP
+	loginXP, streak, err := xp.NewHandler(h.db).RecordLogin(user.ID, now)
+	if err != nil {
+		log.Printf("❌ Failed to record login streak: %v", err)
+	}
+
 	// Generate JWT token
 	token, err := middleware.GenerateJWT(user.ID, user.Username, user.Tier)
 	if err != nil {
@@ -166,13 +174,22 @@ func (h *Handler) Login(c *gin.Context) {
 	// Remove password hash from response
 	user.PasswordHash = ""
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":   "Login successful",
-		"token":     token,
-		"user":      user,
-		"expires":   time.Now().Add(24 * time.Hour).Unix(),
-		"timestamp": time.Now().Format(time.RFC3339),
-	})
+	response := gin.H{
+		"message":      "Login successful",
+		"token":        token,
+		"user":         user,
+		"expires":      time.Now().Add(24 * time.Hour).Unix(),
+		"timestamp":    time.Now().Format(time.RFC3339),
+		"login_streak": streak,
+	}
+	if loginXP != nil && !loginXP.Duplicate {
+		response["daily_login_xp"] = loginXP
+		if unlocked := achievements.NewHandler(h.db).TrackXPResults(user.ID, loginXP); len(unlocked) > 0 {
+			response["achievements_unlocked"] = unlocked
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 func (h *Handler) RefreshToken(c *gin.Context) {
@@ -0,0 +1,109 @@
+package collections
+
+import (
+	"log"
+
+	"geoanomaly/internal/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Predvolené zbierky - prirodzená sada každého biomu (AllowedArtifacts z biome templates).
+// Zapíšu sa do collection_sets, ak tam ešte nie sú; úpravy a nové sady sa robia priamo v DB.
+var defaultSets = []common.CollectionSet{
+	{
+		Key:                 "forest_set",
+		Name:                "Forest Naturalist",
+		Description:         "Collect every natural artifact of the forest",
+		Biome:               "forest",
+		Pieces:              pieces("mushroom_sample", "tree_resin", "animal_bones", "herbal_extract", "old_coin"),
+		RewardXP:            150,
+		BonusXPMultiplier:   0.02,
+		BonusDetectionRange: 0.05,
+	},
+	{
+		Key:                 "mountain_set",
+		Name:                "Mountain Geologist",
+		Description:         "Collect every natural artifact of the mountains",
+		Biome:               "mountain",
+		Pieces:              pieces("mineral_ore", "crystal_shard", "stone_tablet", "mountain_herb", "ice_crystal"),
+		RewardXP:            250,
+		BonusXPMultiplier:   0.03,
+		BonusDetectionRange: 0.05,
+	},
+	{
+		Key:                 "urban_set",
+		Name:                "Urban Scavenger",
+		Description:         "Collect every natural artifact of the city",
+		Biome:               "urban",
+		Pieces:              pieces("old_documents", "medical_supplies", "electronics", "urban_artifact", "cash_register"),
+		RewardXP:            250,
+		BonusXPMultiplier:   0.03,
+		BonusDetectionRange: 0.05,
+	},
+	{
+		Key:                 "water_set",
+		Name:                "Wetland Researcher",
+		Description:         "Collect every natural artifact of the waters",
+		Biome:               "water",
+		Pieces:              pieces("water_sample", "aquatic_plant", "filtered_water", "swamp_gas", "algae_biomass"),
+		RewardXP:            250,
+		BonusXPMultiplier:   0.03,
+		BonusDetectionRange: 0.05,
+	},
+	{
+		Key:                 "industrial_set",
+		Name:                "Industrial Salvager",
+		Description:         "Collect every natural artifact of industrial zones",
+		Biome:               "industrial",
+		Pieces:              pieces("steel_ingot", "chemical_sample", "machinery_parts", "electronic_component", "toxic_waste"),
+		RewardXP:            400,
+		BonusXPMultiplier:   0.05,
+		BonusDetectionRange: 0.1,
+	},
+	{
+		Key:                 "radioactive_set",
+		Name:                "Isotope Hunter",
+		Description:         "Collect every natural artifact of radioactive zones",
+		Biome:               "radioactive",
+		Pieces:              pieces("uranium_ore", "radiation_detector", "contaminated_soil", "atomic_battery", "nuclear_fuel"),
+		RewardXP:            600,
+		BonusXPMultiplier:   0.05,
+		BonusDetectionRange: 0.1,
+	},
+	{
+		Key:                 "chemical_set",
+		Name:                "Hazmat Chemist",
+		Description:         "Collect every natural artifact of chemical zones",
+		Biome:               "chemical",
+		Pieces:              pieces("chemical_compound", "lab_equipment", "toxic_sample", "hazmat_suit", "catalyst"),
+		RewardXP:            800,
+		BonusXPMultiplier:   0.05,
+		BonusDetectionRange: 0.15,
+	},
+}
+
+// pieces - každý typ po jednom kuse
+func pieces(types ...string) common.JSONB {
+	result := common.JSONB{}
+	for _, t := range types {
+		result[t] = 1
+	}
+	return result
+}
+
+// SeedSets - doplní chýbajúce predvolené zbierky (existujúce nemení)
+func SeedSets(db *gorm.DB) error {
+	for _, set := range defaultSets {
+		set := set
+		result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).Create(&set)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("🧩 Seeded collection set: %s", set.Key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package collections
+
+import (
+	"net/http"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// GetCollections - GET /user/collections
+// Zbierky s vlastnenými kusmi; dokončí aj sady skompletizované mimo zberu (trade, odmeny)
+func (h *Handler) GetCollections(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	uid := userID.(uuid.UUID)
+
+	completions := h.CheckCompletion(uid, "")
+
+	var sets []common.CollectionSet
+	if err := h.db.Where("is_active = true AND deleted_at IS NULL").Order("key ASC").Find(&sets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collections"})
+		return
+	}
+
+	var records []common.PlayerCollection
+	h.db.Where("user_id = ?", uid).Find(&records)
+	completedAt := make(map[string]int64, len(records))
+	for _, record := range records {
+		completedAt[record.SetKey] = record.CompletedAt.Unix()
+	}
+
+	owned := h.ownedPieces(uid)
+
+	views := make([]CollectionView, 0, len(sets))
+	completed := 0
+	for _, set := range sets {
+		status, _ := pieceStatus(set.Pieces, owned)
+		view := CollectionView{
+			CollectionSet: set,
+			PieceStatus:   status,
+			Total:         len(status),
+		}
+		for _, piece := range status {
+			if piece.Complete {
+				view.Owned++
+			}
+		}
+		if at, ok := completedAt[set.Key]; ok {
+			view.Completed = true
+			view.CompletedAt = &at
+			completed++
+		}
+		views = append(views, view)
+	}
+
+	response := gin.H{
+		"collections":   views,
+		"total":         len(views),
+		"completed":     completed,
+		"passive_bonus": PassiveBonuses(h.db, uid),
+	}
+	if len(completions) > 0 {
+		response["collections_completed"] = completions
+	}
+
+	c.JSON(http.StatusOK, response)
+}
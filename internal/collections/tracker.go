@@ -0,0 +1,204 @@
+package collections
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/xp"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CheckCompletion - porovná artefakty v inventári so zbierkami a odmení novo dokončené
+// artifactType obmedzí kontrolu na zbierky s daným typom ("" = všetky)
+func (h *Handler) CheckCompletion(userID uuid.UUID, artifactType string) []Completion {
+	var sets []common.CollectionSet
+	query := h.db.Where("is_active = true AND deleted_at IS NULL")
+	if artifactType != "" {
+		query = query.Where("pieces ->> ? IS NOT NULL", artifactType)
+	}
+	if err := query.Find(&sets).Error; err != nil {
+		log.Printf("❌ Failed to load collection sets: %v", err)
+		return nil
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	var completedKeys []string
+	h.db.Model(&common.PlayerCollection{}).Where("user_id = ?", userID).Pluck("set_key", &completedKeys)
+	done := make(map[string]bool, len(completedKeys))
+	for _, key := range completedKeys {
+		done[key] = true
+	}
+
+	owned := h.ownedPieces(userID)
+
+	var completions []Completion
+	for _, set := range sets {
+		if done[set.Key] {
+			continue
+		}
+		if _, complete := pieceStatus(set.Pieces, owned); !complete {
+			continue
+		}
+
+		completion, err := h.complete(userID, set)
+		if err != nil {
+			log.Printf("❌ Failed to complete collection %s: %v", set.Key, err)
+			continue
+		}
+		if completion != nil {
+			completions = append(completions, *completion)
+		}
+	}
+
+	return completions
+}
+
+// complete - zápis dokončenia + item odmena v transakcii, XP cez ledger (idempotentné podľa kľúča zbierky)
+func (h *Handler) complete(userID uuid.UUID, set common.CollectionSet) (*Completion, error) {
+	inserted := false
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		record := common.PlayerCollection{
+			UserID:      userID,
+			SetKey:      set.Key,
+			CompletedAt: time.Now(),
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil // súbežný request už zbierku dokončil
+		}
+		inserted = true
+
+		if len(set.RewardItem) > 0 {
+			return tx.Create(collectionRewardItem(userID, set)).Error
+		}
+		return nil
+	})
+	if err != nil || !inserted {
+		return nil, err
+	}
+
+	completion := &Completion{
+		Key:                 set.Key,
+		Name:                set.Name,
+		BonusXPMultiplier:   set.BonusXPMultiplier,
+		BonusDetectionRange: set.BonusDetectionRange,
+	}
+	if name, ok := set.RewardItem["name"].(string); ok {
+		completion.Item = name
+	}
+
+	log.Printf("🧩 Collection completed: user %s - %s", userID, set.Name)
+
+	if set.RewardXP > 0 {
+		result, err := xp.NewHandler(h.db).Grant(xp.XPGrant{
+			UserID:     userID,
+			SourceType: xpSourceCollection,
+			SourceID:   set.Key,
+			Amount:     set.RewardXP,
+			Reason:     fmt.Sprintf("Collection %s", set.Name),
+		})
+		if err != nil {
+			log.Printf("❌ Failed to grant collection XP: %v", err)
+		} else if !result.Duplicate {
+			completion.XPReward = result.XPGained
+		}
+	}
+
+	return completion, nil
+}
+
+// ownedPieces - počet kusov artefaktov v inventári podľa properties.type
+func (h *Handler) ownedPieces(userID uuid.UUID) map[string]int {
+	var rows []struct {
+		Type  string
+		Total int
+	}
+	h.db.Model(&common.InventoryItem{}).
+		Select("properties->>'type' AS type, COALESCE(SUM(quantity), 0) AS total").
+		Where("user_id = ? AND item_type = 'artifact' AND deleted_at IS NULL", userID).
+		Group("properties->>'type'").
+		Scan(&rows)
+
+	owned := make(map[string]int, len(rows))
+	for _, row := range rows {
+		if row.Type != "" {
+			owned[row.Type] = row.Total
+		}
+	}
+	return owned
+}
+
+// pieceStatus - stav jednotlivých kusov (zoradené podľa typu) a či je zbierka kompletná
+func pieceStatus(pieces common.JSONB, owned map[string]int) ([]PieceView, bool) {
+	views := make([]PieceView, 0, len(pieces))
+	complete := len(pieces) > 0
+	for pieceType, value := range pieces {
+		required := 1
+		if n, ok := value.(float64); ok && n > 0 {
+			required = int(n)
+		} else if n, ok := value.(int); ok && n > 0 {
+			required = n
+		}
+
+		view := PieceView{
+			Type:     pieceType,
+			Required: required,
+			Owned:    owned[pieceType],
+			Complete: owned[pieceType] >= required,
+		}
+		if !view.Complete {
+			complete = false
+		}
+		views = append(views, view)
+	}
+
+	sort.Slice(views, func(i, j int) bool { return views[i].Type < views[j].Type })
+	return views, complete
+}
+
+// PassiveBonuses - trvalé bonusy z dokončených zbierok (detektor, prehľad)
+func PassiveBonuses(db *gorm.DB, userID uuid.UUID) PassiveBonus {
+	var bonus PassiveBonus
+	db.Table("player_collections").
+		Select("COALESCE(SUM(collection_sets.bonus_xp_multiplier), 0) AS xp_multiplier, COALESCE(SUM(collection_sets.bonus_detection_range), 0) AS detection_range").
+		Joins("JOIN collection_sets ON collection_sets.key = player_collections.set_key AND collection_sets.deleted_at IS NULL").
+		Where("player_collections.user_id = ?", userID).
+		Scan(&bonus)
+	return bonus
+}
+
+func collectionRewardItem(userID uuid.UUID, set common.CollectionSet) *common.InventoryItem {
+	itemType, _ := set.RewardItem["item_type"].(string)
+	if itemType == "" {
+		itemType = "artifact"
+	}
+
+	properties := common.JSONB{}
+	for key, value := range set.RewardItem {
+		if key != "item_type" {
+			properties[key] = value
+		}
+	}
+	properties["collection_reward"] = set.Key
+	properties["acquired_at"] = time.Now().Unix()
+
+	return &common.InventoryItem{
+		UserID:        userID,
+		ItemType:      itemType,
+		ItemID:        uuid.New(),
+		Quantity:      1,
+		Properties:    properties,
+		DefinitionKey: common.ItemDefinitionKey(itemType, properties),
+	}
+}
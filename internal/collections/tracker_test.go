@@ -0,0 +1,30 @@
+package collections
+
+import (
+	"testing"
+
+	"geoanomaly/internal/common"
+)
+
+func TestPieceStatus(t *testing.T) {
+	set := common.JSONB{"tree_resin": float64(1), "old_coin": float64(2), "mushroom_sample": 1}
+
+	status, complete := pieceStatus(set, map[string]int{"tree_resin": 3, "old_coin": 1, "mushroom_sample": 1})
+	if complete {
+		t.Fatal("set with a missing old_coin must not be complete")
+	}
+	if len(status) != 3 || status[0].Type != "mushroom_sample" || status[1].Type != "old_coin" {
+		t.Fatalf("expected pieces sorted by type, got %+v", status)
+	}
+	if status[1].Required != 2 || status[1].Owned != 1 || status[1].Complete {
+		t.Errorf("unexpected old_coin status: %+v", status[1])
+	}
+
+	if _, complete := pieceStatus(set, map[string]int{"tree_resin": 1, "old_coin": 2, "mushroom_sample": 1}); !complete {
+		t.Error("expected set to be complete")
+	}
+
+	if _, complete := pieceStatus(common.JSONB{}, nil); complete {
+		t.Error("empty set must never be complete")
+	}
+}
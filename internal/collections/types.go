@@ -0,0 +1,40 @@
+package collections
+
+import "geoanomaly/internal/common"
+
+// XP source type pre jednorazovú odmenu za zbierku (xp_ledger.source_type)
+const xpSourceCollection = "collection_set"
+
+// PieceView - jeden kus zbierky a koľko ho hráč vlastní
+type PieceView struct {
+	Type     string `json:"type"`
+	Required int    `json:"required"`
+	Owned    int    `json:"owned"`
+	Complete bool   `json:"complete"`
+}
+
+// CollectionView - zbierka so stavom pre hráča
+type CollectionView struct {
+	common.CollectionSet
+	PieceStatus []PieceView `json:"piece_status"`
+	Owned       int         `json:"owned"`
+	Total       int         `json:"total"`
+	Completed   bool        `json:"completed"`
+	CompletedAt *int64      `json:"completed_at,omitempty"`
+}
+
+// Completion - zbierka dokončená práve teraz
+type Completion struct {
+	Key                 string  `json:"key"`
+	Name                string  `json:"name"`
+	XPReward            int     `json:"xp_reward,omitempty"`
+	Item                string  `json:"item,omitempty"`
+	BonusXPMultiplier   float64 `json:"bonus_xp_multiplier,omitempty"`
+	BonusDetectionRange float64 `json:"bonus_detection_range,omitempty"`
+}
+
+// PassiveBonus - súčet trvalých bonusov zo všetkých dokončených zbierok
+type PassiveBonus struct {
+	XPMultiplier   float64 `json:"xp_multiplier"`
+	DetectionRange float64 `json:"detection_range"`
+}
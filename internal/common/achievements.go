@@ -0,0 +1,43 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Definícia achievementu - dáta v DB, nie v kóde
+type AchievementDefinition struct {
+	BaseModel
+	Key           string `json:"key" gorm:"uniqueIndex;not null;size:100"`
+	Name          string `json:"name" gorm:"not null;size:100"`
+	Description   string `json:"description,omitempty" gorm:"type:text"`
+	Event         string `json:"event" gorm:"not null;size:50;index"`                    // collect, enter_zone, discover_zone, level_up
+	Metric        string `json:"metric" gorm:"not null;size:20;default:'count'"`         // count, max, distinct
+	DistinctField string `json:"distinct_field,omitempty" gorm:"size:50"`                // pri metric=distinct (napr. biome)
+	Filter        JSONB  `json:"filter,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"` // item_type, biome, rarity, min_zone_tier
+	Tiers         JSONB  `json:"tiers" gorm:"type:jsonb;default:'{}'::jsonb"`            // {"bronze": {"target": 10, "xp": 50, "item": {...}}, ...}
+	IsActive      bool   `json:"is_active" gorm:"default:true"`
+}
+
+func (AchievementDefinition) TableName() string {
+	return "achievement_definitions"
+}
+
+// ✅ NEW: Postup hráča v achievemente
+type PlayerAchievement struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	UserID         uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_player_achievement"`
+	AchievementKey string    `json:"achievement_key" gorm:"not null;size:100;uniqueIndex:idx_player_achievement;index"`
+	Progress       int       `json:"progress" gorm:"default:0"`
+	Seen           JSONB     `json:"-" gorm:"type:jsonb;default:'{}'::jsonb"`              // metric=distinct
+	Tier           string    `json:"tier,omitempty" gorm:"size:20"`                        // najvyšší odomknutý tier
+	UnlockedTiers  JSONB     `json:"unlocked_tiers" gorm:"type:jsonb;default:'{}'::jsonb"` // {"bronze": <unix>}
+}
+
+func (PlayerAchievement) TableName() string {
+	return "player_achievements"
+}
@@ -0,0 +1,40 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Zbierka - sada typov artefaktov (properties.type), dáta v DB
+type CollectionSet struct {
+	BaseModel
+	Key                 string  `json:"key" gorm:"uniqueIndex;not null;size:100"`
+	Name                string  `json:"name" gorm:"not null;size:100"`
+	Description         string  `json:"description,omitempty" gorm:"type:text"`
+	Biome               string  `json:"biome,omitempty" gorm:"size:50;index"`
+	Pieces              JSONB   `json:"pieces" gorm:"type:jsonb;default:'{}'::jsonb"` // {"<artifact type>": <počet kusov>}
+	RewardXP            int     `json:"reward_xp" gorm:"default:0"`                   // jednorazová odmena
+	RewardItem          JSONB   `json:"reward_item,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
+	BonusXPMultiplier   float64 `json:"bonus_xp_multiplier" gorm:"default:0"`   // trvalý bonus, napr. 0.05 = +5% XP
+	BonusDetectionRange float64 `json:"bonus_detection_range" gorm:"default:0"` // trvalý bonus, napr. 0.1 = +10% dosah detektora
+	IsActive            bool    `json:"is_active" gorm:"default:true"`
+}
+
+func (CollectionSet) TableName() string {
+	return "collection_sets"
+}
+
+// ✅ NEW: Dokončená zbierka hráča - bonus platí aj keď sa kusy neskôr predajú/použijú
+type PlayerCollection struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_player_collection"`
+	SetKey      string    `json:"set_key" gorm:"not null;size:100;uniqueIndex:idx_player_collection;index"`
+	CompletedAt time.Time `json:"completed_at" gorm:"not null"`
+}
+
+func (PlayerCollection) TableName() string {
+	return "player_collections"
+}
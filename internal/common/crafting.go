@@ -0,0 +1,63 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Stav craftovania
+const (
+	CraftingStatusInProgress = "in_progress" // čaká na CraftSeconds
+	CraftingStatusCompleted  = "completed"   // výstupy sú v inventári
+)
+
+// ✅ NEW: Recept - vstupy a výstupy sú kľúče item_definitions, dáta v DB
+type CraftingRecipe struct {
+	BaseModel
+	Key               string `json:"key" gorm:"uniqueIndex;not null;size:100"`
+	Name              string `json:"name" gorm:"not null;size:100"`
+	Description       string `json:"description,omitempty" gorm:"type:text"`
+	Inputs            JSONB  `json:"inputs" gorm:"type:jsonb;default:'{}'::jsonb"`  // {"artifact:steel_ingot": 2}
+	Outputs           JSONB  `json:"outputs" gorm:"type:jsonb;default:'{}'::jsonb"` // {"gear:crowbar": 1}
+	RequiredLevel     int    `json:"required_level" gorm:"default:0"`
+	RequiredTier      int    `json:"required_tier" gorm:"default:0"`
+	CraftSeconds      int    `json:"craft_seconds" gorm:"default:0"`          // 0 = výstupy hneď
+	RequiresDiscovery bool   `json:"requires_discovery" gorm:"default:false"` // viditeľný až po objavení
+	IsActive          bool   `json:"is_active" gorm:"default:true"`
+}
+
+func (CraftingRecipe) TableName() string {
+	return "crafting_recipes"
+}
+
+// ✅ NEW: Objavený recept hráča (recepty s RequiresDiscovery)
+type RecipeDiscovery struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_recipe_discovery"`
+	RecipeKey string    `json:"recipe_key" gorm:"not null;size:100;uniqueIndex:idx_recipe_discovery"`
+}
+
+func (RecipeDiscovery) TableName() string {
+	return "recipe_discoveries"
+}
+
+// ✅ NEW: Craftovanie hráča - vstupy sa spotrebujú hneď, výstupy po ReadyAt
+type CraftingJob struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index:idx_crafting_job_user"`
+	RecipeKey   string     `json:"recipe_key" gorm:"not null;size:100"`
+	Status      string     `json:"status" gorm:"not null;size:20;index:idx_crafting_job_user"`
+	Inputs      JSONB      `json:"inputs" gorm:"type:jsonb;default:'{}'::jsonb"`  // spotrebované množstvá
+	Outputs     JSONB      `json:"outputs" gorm:"type:jsonb;default:'{}'::jsonb"` // množstvá na vytvorenie
+	ReadyAt     time.Time  `json:"ready_at" gorm:"not null"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func (CraftingJob) TableName() string {
+	return "crafting_jobs"
+}
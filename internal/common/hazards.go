@@ -0,0 +1,25 @@
+package common
+
+import (
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Anomálne pole (hazard) vo vnútri zóny
+type ZoneHazard struct {
+	BaseModel
+	ZoneID       uuid.UUID `json:"zone_id" gorm:"type:uuid;not null;index"`
+	Type         string    `json:"type" gorm:"not null;size:50"`   // toxic_gas, radiation_high, ... (z EnvironmentalEffects)
+	Effect       string    `json:"effect" gorm:"not null;size:20"` // health, durability, drop_item
+	Location     Location  `json:"location" gorm:"embedded;embeddedPrefix:location_"`
+	RadiusMeters int       `json:"radius_meters" gorm:"not null"`
+	Damage       int       `json:"damage" gorm:"default:0"`
+	IsActive     bool      `json:"is_active" gorm:"default:true"`
+	Properties   JSONB     `json:"properties,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
+
+	// Relationships
+	Zone *Zone `json:"zone,omitempty" gorm:"foreignKey:ZoneID"`
+}
+
+func (ZoneHazard) TableName() string {
+	return "zone_hazards"
+}
@@ -0,0 +1,194 @@
+package common
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kategórie itemov v katalógu (ItemType v inventári ostáva artifact/gear)
+const (
+	ItemCategoryArtifact   = "artifact"
+	ItemCategoryGear       = "gear"
+	ItemCategoryConsumable = "consumable"
+)
+
+// Sloty, do ktorých sa dá vybaviť gear
+const (
+	SlotHead     = "head"
+	SlotBody     = "body"
+	SlotHands    = "hands"
+	SlotFeet     = "feet"
+	SlotTool     = "tool"
+	SlotDetector = "detector"
+)
+
+// LoadoutSlots - všetky sloty loadoutu v poradí pre klienta
+var LoadoutSlots = []string{SlotHead, SlotBody, SlotHands, SlotFeet, SlotTool, SlotDetector}
+
+// IsValidSlot - slot existuje v loadoute
+func IsValidSlot(slot string) bool {
+	for _, s := range LoadoutSlots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// Typ itemu bez properties.type (napr. odmena bez typu)
+const ItemTypeMisc = "misc"
+
+// ✅ NEW: Efekty použitia itemu (ItemDefinition.Effects)
+const (
+	EffectHeal           = "heal"            // okamžite: +value zdravia
+	EffectHazardResist   = "hazard_resist"   // dočasne: -value poškodenia z hazardov (properties.hazards, prázdne = všetky)
+	EffectDetectionBoost = "detection_boost" // dočasne: +value dosahu detektora (0.5 = +50%)
+	EffectReveal         = "reveal"          // dočasne: skryté artefakty sa odhalia do value metrov
+)
+
+// ✅ NEW: Definícia itemu - typované dáta, na ktoré odkazuje InventoryItem.DefinitionKey
+type ItemDefinition struct {
+	BaseModel
+	Key         string  `json:"key" gorm:"uniqueIndex;not null;size:100"` // "<item_type>:<type>", napr. gear:gas_mask
+	ItemType    string  `json:"item_type" gorm:"not null;size:50;index"`  // artifact, gear (ako InventoryItem.ItemType)
+	Type        string  `json:"type" gorm:"not null;size:50"`             // properties.type
+	Category    string  `json:"category" gorm:"not null;size:20;index"`   // artifact, gear, consumable
+	Slot        string  `json:"slot,omitempty" gorm:"size:20"`            // len pre vybaviteľný gear
+	Name        string  `json:"name" gorm:"not null;size:100"`
+	Description string  `json:"description,omitempty" gorm:"type:text"`
+	Stats       JSONB   `json:"stats" gorm:"type:jsonb;default:'{}'::jsonb"` // {"armor": 5, "scan_range": 0.1, "detection_range": 0.2, "collect_radius": 3, "xp_bonus": 0.05, "inventory_slots": 5, "resist": {"<hazard>": 0.5}}
+	StackSize   int     `json:"stack_size" gorm:"default:1"`
+	Weight      float64 `json:"weight" gorm:"default:0"` // ✅ NEW: váha kusu, 0 = bez váhy
+	IconKey     string  `json:"icon_key" gorm:"size:100"`
+	IsActive    bool    `json:"is_active" gorm:"default:true"`
+
+	// ✅ NEW: Použitie itemu
+	Effects         JSONB `json:"effects,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"` // {"<effect>": {"value": 30, "duration_seconds": 600, "hazards": [...]}}
+	CooldownSeconds int   `json:"cooldown_seconds" gorm:"default:0"`
+	KeepOnUse       bool  `json:"keep_on_use" gorm:"default:false"` // nástroje sa použitím nespotrebujú
+}
+
+func (ItemDefinition) TableName() string {
+	return "item_definitions"
+}
+
+// Usable - item má aspoň jeden efekt
+func (d ItemDefinition) Usable() bool {
+	return len(d.Effects) > 0
+}
+
+// Equippable - gear so slotom
+func (d ItemDefinition) Equippable() bool {
+	return d.Category == ItemCategoryGear && d.Slot != ""
+}
+
+// ✅ NEW: Stav opotrebenia gearu
+const (
+	DurabilityIntact = "intact"
+	DurabilityWorn   = "worn"   // <= 25 % - blíži sa k rozbitiu
+	DurabilityBroken = "broken" // 0 - gear nedáva žiadne bonusy
+)
+
+// ItemDurability - durability vybaviteľného gearu pre klienta
+type ItemDurability struct {
+	Current int    `json:"current"`
+	Max     int    `json:"max"`
+	State   string `json:"state"`
+	Broken  bool   `json:"broken"`
+}
+
+// ItemDefinitionKey - kľúč definície pre item daného typu (properties.type)
+func ItemDefinitionKey(itemType string, properties JSONB) string {
+	itemType = strings.ToLower(strings.TrimSpace(itemType))
+	typ, _ := properties["type"].(string)
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	if typ == "" {
+		typ = ItemTypeMisc
+	}
+	return itemType + ":" + typ
+}
+
+// ✅ NEW: Aktívny dočasný efekt hráča (z použitia itemu)
+type PlayerBuff struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index:idx_player_buff_active"`
+	Effect     string    `json:"effect" gorm:"not null;size:50"`
+	Value      float64   `json:"value" gorm:"not null"`
+	Properties JSONB     `json:"properties,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
+	SourceKey  string    `json:"source_key" gorm:"size:100"` // ItemDefinition.Key
+	ExpiresAt  time.Time `json:"expires_at" gorm:"not null;index:idx_player_buff_active"`
+}
+
+func (PlayerBuff) TableName() string {
+	return "player_buffs"
+}
+
+// ✅ NEW: Záznam použitia itemu - z posledného použitia sa počíta cooldown
+type ItemUse struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_item_use_cooldown"`
+
+	UserID        uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index:idx_item_use_cooldown"`
+	DefinitionKey string     `json:"definition_key" gorm:"not null;size:100;index:idx_item_use_cooldown"`
+	ItemID        uuid.UUID  `json:"item_id" gorm:"type:uuid;not null"`
+	ZoneID        *uuid.UUID `json:"zone_id,omitempty" gorm:"type:uuid"`
+	Effects       JSONB      `json:"effects" gorm:"type:jsonb;default:'{}'::jsonb"`
+}
+
+func (ItemUse) TableName() string {
+	return "item_uses"
+}
+
+// ✅ NEW: Vybavený gear hráča - jeden item na slot
+type PlayerLoadout struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_player_loadout_slot"`
+	Slot       string    `json:"slot" gorm:"not null;size:20;uniqueIndex:idx_player_loadout_slot"`
+	ItemID     uuid.UUID `json:"item_id" gorm:"type:uuid;not null;uniqueIndex"` // InventoryItem.ID
+	EquippedAt time.Time `json:"equipped_at" gorm:"not null"`
+}
+
+func (PlayerLoadout) TableName() string {
+	return "player_loadouts"
+}
+
+// ✅ NEW: Item, ktorý sa pri zbere nezmestil do plného inventára - čaká, kým si ho hráč vyzdvihne, potom expiruje
+type OverflowItem struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+
+	UserID        uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	ItemType      string    `json:"item_type" gorm:"not null;size:50"`
+	ItemID        uuid.UUID `json:"item_id" gorm:"type:uuid;not null"`
+	DefinitionKey string    `json:"definition_key" gorm:"size:100"`
+	Quantity      int       `json:"quantity" gorm:"default:1"`
+	Properties    JSONB     `json:"properties,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
+}
+
+func (OverflowItem) TableName() string {
+	return "inventory_overflow"
+}
+
+// ✅ NEW: História zberu - jeden riadok na zber (stackovaný zber nevytvorí nový riadok v inventári),
+// z nej sa počíta klesajúci výnos opakovaného zberu v zóne
+type ItemCollect struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_item_collect_zone"`
+
+	UserID        uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index:idx_item_collect_zone"`
+	ZoneID        uuid.UUID `json:"zone_id" gorm:"type:uuid;not null;index:idx_item_collect_zone"`
+	ItemType      string    `json:"item_type" gorm:"not null;size:50"`
+	ItemID        uuid.UUID `json:"item_id" gorm:"type:uuid;not null"` // Artifact.ID / Gear.ID
+	DefinitionKey string    `json:"definition_key" gorm:"size:100"`
+}
+
+func (ItemCollect) TableName() string {
+	return "item_collects"
+}
@@ -0,0 +1,103 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Typ ponuky na trhu
+const (
+	ListingKindFixed   = "fixed"   // okamžitý nákup za Price
+	ListingKindAuction = "auction" // najvyššia ponuka pri ExpiresAt
+)
+
+// ✅ NEW: Stav ponuky na trhu
+const (
+	ListingStatusActive     = "active"
+	ListingStatusSold       = "sold"
+	ListingStatusExpired    = "expired"     // bez kupca, item sa vrátil predajcovi
+	ListingStatusCancelled  = "cancelled"   // stiahnutá predajcom
+	ListingStatusDelisted   = "delisted"    // stiahnutá adminom
+	ListingStatusRolledBack = "rolled_back" // predaj zrušený adminom
+)
+
+// ✅ NEW: Ponuka na trhu - item je v escrow, kým je ponuka aktívna
+type MarketListing struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	SellerID  uuid.UUID `json:"seller_id" gorm:"type:uuid;not null;index"`
+	ItemID    uuid.UUID `json:"item_id" gorm:"type:uuid;not null;index"` // InventoryItem.ID
+	Kind      string    `json:"kind" gorm:"not null;size:20"`
+	Status    string    `json:"status" gorm:"not null;size:20;index"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+
+	// Cena: fixed = predajná cena, auction = aktuálna najvyššia (alebo vyvolávacia) ponuka
+	Price         int64      `json:"price" gorm:"not null;index"`
+	StartingPrice int64      `json:"starting_price" gorm:"not null"`
+	BidCount      int        `json:"bid_count" gorm:"default:0"`
+	HighBidderID  *uuid.UUID `json:"high_bidder_id,omitempty" gorm:"type:uuid"`
+	ListingFee    int64      `json:"listing_fee" gorm:"default:0"` // zaplatené pri vystavení, nevracia sa
+
+	// Snapshot itemu pre filtre a zobrazenie
+	DefinitionKey string `json:"definition_key" gorm:"size:100;index"`
+	ItemType      string `json:"item_type" gorm:"size:50;index"`
+	Name          string `json:"name" gorm:"size:100"`
+	Rarity        string `json:"rarity,omitempty" gorm:"size:20;index"`
+	Biome         string `json:"biome,omitempty" gorm:"size:50;index"`
+	Quantity      int    `json:"quantity" gorm:"default:1"`
+
+	// Uzavretie
+	BuyerID     *uuid.UUID `json:"buyer_id,omitempty" gorm:"type:uuid;index"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+	ClosedBy    *uuid.UUID `json:"closed_by,omitempty" gorm:"type:uuid"` // admin pri delist/rollback
+	CloseReason string     `json:"close_reason,omitempty" gorm:"type:text"`
+}
+
+func (MarketListing) TableName() string {
+	return "market_listings"
+}
+
+// ✅ NEW: Príhoz v aukcii - mena je stiahnutá hneď, prebitý hráč ju dostane späť
+type MarketBid struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	ListingID uuid.UUID `json:"listing_id" gorm:"type:uuid;not null;index"`
+	BidderID  uuid.UUID `json:"bidder_id" gorm:"type:uuid;not null;index"`
+	Amount    int64     `json:"amount" gorm:"not null"`
+	Refunded  bool      `json:"refunded" gorm:"default:false"`
+}
+
+func (MarketBid) TableName() string {
+	return "market_bids"
+}
+
+// ✅ NEW: Uskutočnený predaj - zdroj histórie cien podľa typu itemu
+type MarketSale struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+
+	ListingID     uuid.UUID `json:"listing_id" gorm:"type:uuid;not null;uniqueIndex"`
+	ItemID        uuid.UUID `json:"item_id" gorm:"type:uuid;not null"`
+	SellerID      uuid.UUID `json:"seller_id" gorm:"type:uuid;not null;index"`
+	BuyerID       uuid.UUID `json:"buyer_id" gorm:"type:uuid;not null;index"`
+	Kind          string    `json:"kind" gorm:"not null;size:20"`
+	DefinitionKey string    `json:"definition_key" gorm:"size:100;index"`
+	Rarity        string    `json:"rarity,omitempty" gorm:"size:20"`
+	Biome         string    `json:"biome,omitempty" gorm:"size:50"`
+	Quantity      int       `json:"quantity" gorm:"default:1"`
+	Price         int64     `json:"price" gorm:"not null"` // zaplatené kupcom
+	Tax           int64     `json:"tax" gorm:"not null"`   // stiahnuté z trhu, predajca dostal Price - Tax
+
+	RolledBackAt *time.Time `json:"rolled_back_at,omitempty" gorm:"index"`
+	RolledBackBy *uuid.UUID `json:"rolled_back_by,omitempty" gorm:"type:uuid"`
+	// Časť výnosu, ktorú predajca pri rollbacku už nemal - kupcovi sa nevrátila
+	RollbackShortfall int64 `json:"rollback_shortfall,omitempty" gorm:"default:0"`
+}
+
+func (MarketSale) TableName() string {
+	return "market_sales"
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Akcie u obchodníka
+const (
+	VendorActionSell    = "sell"    // item za kredity
+	VendorActionSalvage = "salvage" // item rozobratý na materiál
+)
+
+// ✅ NEW: Záznam predaja / rozobratia itemu - jeden riadok na item, BatchID spája hromadnú akciu.
+// Z predajov za posledné obdobie sa počíta dopyt (cena klesá, keď typ zaplaví trh).
+type VendorTransaction struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+
+	BatchID uuid.UUID `json:"batch_id" gorm:"type:uuid;not null;index"`
+	UserID  uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	ItemID  uuid.UUID `json:"item_id" gorm:"type:uuid;not null"` // InventoryItem.ID (soft-deleted)
+	Action  string    `json:"action" gorm:"not null;size:20;index"`
+
+	// Snapshot itemu
+	DefinitionKey string `json:"definition_key" gorm:"size:100;index"`
+	Rarity        string `json:"rarity,omitempty" gorm:"size:20"`
+	Biome         string `json:"biome,omitempty" gorm:"size:50"`
+	Quantity      int    `json:"quantity" gorm:"default:1"`
+
+	UnitPrice int64 `json:"unit_price" gorm:"default:0"` // len pri predaji
+	Total     int64 `json:"total" gorm:"default:0"`
+	Outputs   JSONB `json:"outputs,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"` // rozobratie: {"<definition_key>": množstvo}
+}
+
+func (VendorTransaction) TableName() string {
+	return "vendor_transactions"
+}
@@ -22,12 +22,15 @@ func (j *JSONB) Scan(value interface{}) error {
 		return nil
 	}
 
-	bytes, ok := value.([]byte)
-	if !ok {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, j)
+	case string:
+		// ✅ FIX: niektoré drivery vracajú jsonb ako string
+		return json.Unmarshal([]byte(v), j)
+	default:
 		return errors.New("type assertion to []byte failed")
 	}
-
-	return json.Unmarshal(bytes, j)
 }
 
 // Base model
@@ -53,6 +56,12 @@ type LocationWithAccuracy struct {
 	Timestamp time.Time `json:"timestamp" gorm:"autoUpdateTime"`
 }
 
+// ✅ NEW: Maximálne zdravie hráča (User.Health)
+const MaxPlayerHealth = 100
+
+// ✅ NEW: Maximálna durability gearu (InventoryItem.Properties["durability"])
+const MaxGearDurability = 100
+
 // User model - match exact database structure
 type User struct {
 	BaseModel
@@ -67,6 +76,13 @@ type User struct {
 	TotalArtifacts  int        `json:"total_artifacts" gorm:"default:0"`
 	TotalGear       int        `json:"total_gear" gorm:"default:0"`
 	ZonesDiscovered int        `json:"zones_discovered" gorm:"default:0"`
+	Health          int        `json:"health" gorm:"default:100"`                                 // ✅ NEW: hazard damage
+	DistanceWalked  float64    `json:"distance_walked" gorm:"default:0"`                          // ✅ NEW: metre (XP za km)
+	LoginStreak     int        `json:"login_streak" gorm:"default:0"`                             // ✅ NEW: po sebe idúce dni
+	RewardedLevel   int        `json:"-" gorm:"default:1"`                                        // ✅ NEW: najvyšší level, za ktorý už boli odmeny
+	Features        JSONB      `json:"features,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`  // ✅ NEW: odomknuté feature flagy
+	Cosmetics       JSONB      `json:"cosmetics,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"` // ✅ NEW: odomknuté kozmetické predmety
+	Timezone        string     `json:"timezone" gorm:"size:64;default:'UTC'"`                     // ✅ NEW: IANA pásmo (denné/týždenné questy)
 	IsActive        bool       `json:"is_active" gorm:"default:true"`
 	IsBanned        bool       `json:"is_banned" gorm:"default:false"`
 	LastLogin       *time.Time `json:"last_login,omitempty"`
@@ -160,8 +176,13 @@ type InventoryItem struct {
 	Quantity   int       `json:"quantity" gorm:"default:1"`
 	AcquiredAt time.Time `json:"acquired_at" gorm:"autoCreateTime"`
 
+	// ✅ NEW: Odkaz do katalógu item_definitions (ItemDefinitionKey)
+	DefinitionKey string `json:"definition_key,omitempty" gorm:"size:100;index"`
+
 	// Relationships
-	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	User       *User           `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Definition *ItemDefinition `json:"definition,omitempty" gorm:"-"`
+	Durability *ItemDurability `json:"durability,omitempty" gorm:"-"`
 }
 
 // ✅ UPDATED: Artifact model with biome support
@@ -41,3 +41,21 @@ func TestIsValidGPSCoordinate(t *testing.T) {
 		}
 	}
 }
+
+func TestUserProgressionTier(t *testing.T) {
+	cases := []struct {
+		tier, level, want int
+	}{
+		{0, 1, 0},
+		{0, 5, 1},
+		{0, 19, 2},
+		{0, 30, 4},
+		{3, 1, 3},  // predplatné stále platí
+		{2, 25, 3}, // level predbehol tier
+	}
+	for _, tc := range cases {
+		if got := (User{Tier: tc.tier, Level: tc.level}).ProgressionTier(); got != tc.want {
+			t.Errorf("ProgressionTier(tier %d, level %d) = %d, want %d", tc.tier, tc.level, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package common
+
+// ✅ NEW: Level, od ktorého má hráč prístup ako s daným tierom (index = tier)
+// Free hráč sa tak levelovaním dostane až do radioactive/chemical zón
+var LevelTierThresholds = []int{1, 5, 12, 20, 30}
+
+// LevelTier - tier, ktorý hráč dosiahol levelom
+func LevelTier(level int) int {
+	tier := 0
+	for t, minLevel := range LevelTierThresholds {
+		if level >= minLevel {
+			tier = t
+		}
+	}
+	return tier
+}
+
+// RequiredLevelForTier - level, ktorým sa odomkne prístup daného tieru
+func RequiredLevelForTier(tier int) int {
+	if tier < 0 {
+		return LevelTierThresholds[0]
+	}
+	if tier >= len(LevelTierThresholds) {
+		return LevelTierThresholds[len(LevelTierThresholds)-1]
+	}
+	return LevelTierThresholds[tier]
+}
+
+// ProgressionTier - herný prístup (biomy, rarity, gear, obtiažnosť zón) = vyšší z tieru a levelu
+// Počet zón a ďalšie výhody predplatného ostávajú na Tier
+func (u User) ProgressionTier() int {
+	if levelTier := LevelTier(u.Level); levelTier > u.Tier {
+		return levelTier
+	}
+	return u.Tier
+}
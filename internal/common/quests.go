@@ -0,0 +1,53 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Šablóna questu - z nej sa generujú denné/týždenné questy hráčov
+type QuestTemplate struct {
+	BaseModel
+	Key        string `json:"key" gorm:"uniqueIndex;not null;size:100"`
+	Title      string `json:"title" gorm:"not null;size:150"`                         // {biome} sa nahradí biomom v okolí hráča
+	Period     string `json:"period" gorm:"not null;size:10;index"`                   // daily, weekly
+	Event      string `json:"event" gorm:"not null;size:50"`                          // collect, walk, enter_zone, zone_clear
+	Filter     JSONB  `json:"filter,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"` // item_type, rarity, min_zone_tier, biome ("*" = biom v okolí)
+	Target     int    `json:"target" gorm:"not null"`
+	MinTier    int    `json:"min_tier" gorm:"default:0"`
+	RewardXP   int    `json:"reward_xp" gorm:"default:0"`
+	RewardItem JSONB  `json:"reward_item,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
+	Weight     int    `json:"weight" gorm:"default:1"`
+	IsActive   bool   `json:"is_active" gorm:"default:true"`
+}
+
+func (QuestTemplate) TableName() string {
+	return "quest_templates"
+}
+
+// ✅ NEW: Vygenerovaný quest hráča pre konkrétne obdobie
+type PlayerQuest struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_player_quest_period;index"`
+	TemplateKey string     `json:"template_key" gorm:"not null;size:100;uniqueIndex:idx_player_quest_period"`
+	Period      string     `json:"period" gorm:"not null;size:10"`
+	PeriodKey   string     `json:"period_key" gorm:"not null;size:20;uniqueIndex:idx_player_quest_period"` // 2025-07-10, 2025-W28
+	Title       string     `json:"title" gorm:"not null;size:150"`
+	Event       string     `json:"event" gorm:"not null;size:50"`
+	Filter      JSONB      `json:"filter,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
+	Target      int        `json:"target" gorm:"not null"`
+	Progress    int        `json:"progress" gorm:"default:0"`
+	RewardXP    int        `json:"reward_xp" gorm:"default:0"`
+	RewardItem  JSONB      `json:"reward_item,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
+	Status      string     `json:"status" gorm:"not null;size:20;default:'active';index"` // active, completed, claimed
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null;index"`
+	ClaimedAt   *time.Time `json:"claimed_at,omitempty"`
+}
+
+func (PlayerQuest) TableName() string {
+	return "player_quests"
+}
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Sezóna - časovo ohraničená XP dráha oddelená od lifetime User.XP
+type Season struct {
+	BaseModel
+	Name               string     `json:"name" gorm:"not null;size:100"`
+	Description        string     `json:"description,omitempty" gorm:"type:text"`
+	StartsAt           time.Time  `json:"starts_at" gorm:"not null;index"`
+	EndsAt             time.Time  `json:"ends_at" gorm:"not null;index"`
+	XPPerLevel         int        `json:"xp_per_level" gorm:"not null;default:1000"`
+	MaxLevel           int        `json:"max_level" gorm:"not null;default:50"`
+	PremiumTier        int        `json:"premium_tier" gorm:"not null;default:1"`                              // minimálny Tier pre premium dráhu
+	ExclusiveArtifacts JSONB      `json:"exclusive_artifacts,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"` // {"<type>": {"name": "...", "rarity": "epic", "chance": 0.1, "biomes": [...]}}
+	SnapshotAt         *time.Time `json:"snapshot_at,omitempty"`                                               // koniec sezóny spracovaný
+}
+
+func (Season) TableName() string {
+	return "seasons"
+}
+
+// ✅ NEW: Odmena na sezónnej dráhe (free / premium)
+type SeasonReward struct {
+	BaseModel
+	SeasonID uuid.UUID `json:"season_id" gorm:"type:uuid;not null;uniqueIndex:idx_season_reward_slot"`
+	Level    int       `json:"level" gorm:"not null;uniqueIndex:idx_season_reward_slot"`
+	Track    string    `json:"track" gorm:"not null;size:10;uniqueIndex:idx_season_reward_slot"` // free, premium
+	Name     string    `json:"name" gorm:"not null;size:100"`
+	RewardXP int       `json:"reward_xp" gorm:"default:0"`                           // lifetime XP
+	Item     JSONB     `json:"item,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"` // item do inventára
+}
+
+func (SeasonReward) TableName() string {
+	return "season_rewards"
+}
+
+// ✅ NEW: Sezónny postup hráča
+type PlayerSeason struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	SeasonID       uuid.UUID `json:"season_id" gorm:"type:uuid;not null;uniqueIndex:idx_player_season;index"`
+	UserID         uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_player_season"`
+	SeasonXP       int       `json:"season_xp" gorm:"not null;default:0;index"`
+	ClaimedRewards JSONB     `json:"claimed_rewards" gorm:"type:jsonb;default:'{}'::jsonb"` // {"<reward_id>": <unix>}
+}
+
+func (PlayerSeason) TableName() string {
+	return "player_seasons"
+}
+
+// ✅ NEW: Konečné poradie sezóny (snapshot pri ukončení)
+type SeasonRanking struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	SeasonID uuid.UUID `json:"season_id" gorm:"type:uuid;not null;uniqueIndex:idx_season_ranking"`
+	UserID   uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_season_ranking"`
+	Username string    `json:"username" gorm:"size:50"`
+	Rank     int       `json:"rank" gorm:"not null;index"`
+	SeasonXP int       `json:"season_xp" gorm:"not null"`
+	Level    int       `json:"level" gorm:"not null"`
+	Tier     int       `json:"tier"`
+}
+
+func (SeasonRanking) TableName() string {
+	return "season_rankings"
+}
@@ -0,0 +1,90 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Zdroj escrow zámku itemu
+const (
+	EscrowSourceTrade  = "trade"
+	EscrowSourceMarket = "market"
+)
+
+// ✅ NEW: Item zamknutý v escrow - nedá sa použiť, zmazať, vybaviť ani spotrebovať
+type ItemEscrow struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	ItemID      uuid.UUID `json:"item_id" gorm:"type:uuid;not null;uniqueIndex"` // InventoryItem.ID
+	Source      string    `json:"source" gorm:"not null;size:20;index:idx_item_escrow_reference"`
+	ReferenceID uuid.UUID `json:"reference_id" gorm:"type:uuid;not null;index:idx_item_escrow_reference"` // TradeOffer.ID, MarketListing.ID
+}
+
+func (ItemEscrow) TableName() string {
+	return "item_escrows"
+}
+
+// ✅ NEW: Stav ponuky obchodu
+const (
+	TradeStatusPending   = "pending"
+	TradeStatusAccepted  = "accepted"
+	TradeStatusDeclined  = "declined"
+	TradeStatusCountered = "countered" // nahradená protiponukou
+	TradeStatusCancelled = "cancelled"
+	TradeStatusExpired   = "expired"
+)
+
+// ✅ NEW: Stav kontroly podvodu
+const (
+	TradeReviewNone      = ""
+	TradeReviewPending   = "pending"   // fraud hook označil obchod na kontrolu
+	TradeReviewCleared   = "cleared"   // admin obchod schválil
+	TradeReviewConfirmed = "confirmed" // admin potvrdil podvod
+)
+
+// ✅ NEW: Ponuka obchodu medzi hráčmi - itemy navrhovateľa sú v escrow, kým je ponuka pending
+type TradeOffer struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	FromUserID  uuid.UUID  `json:"from_user_id" gorm:"type:uuid;not null;index"`
+	ToUserID    uuid.UUID  `json:"to_user_id" gorm:"type:uuid;not null;index"`
+	Status      string     `json:"status" gorm:"not null;size:20;index"`
+	Message     string     `json:"message,omitempty" gorm:"size:500"`
+	CounterOfID *uuid.UUID `json:"counter_of_id,omitempty" gorm:"type:uuid"` // ponuka, na ktorú táto odpovedá
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null;index"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+
+	// Fraud review
+	FraudFlags   JSONB      `json:"fraud_flags,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"` // {"<hook>": "dôvod"}
+	ReviewStatus string     `json:"review_status,omitempty" gorm:"size:20;index"`
+	ReviewedBy   *uuid.UUID `json:"reviewed_by,omitempty" gorm:"type:uuid"`
+	ReviewNote   string     `json:"review_note,omitempty" gorm:"type:text"`
+
+	Items []TradeItem `json:"items,omitempty" gorm:"foreignKey:OfferID"`
+}
+
+func (TradeOffer) TableName() string {
+	return "trade_offers"
+}
+
+// ✅ NEW: Item v ponuke - snapshot pre históriu (item po výmene mení vlastníka)
+type TradeItem struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	OfferID       uuid.UUID `json:"offer_id" gorm:"type:uuid;not null;index"`
+	ItemID        uuid.UUID `json:"item_id" gorm:"type:uuid;not null;index"` // InventoryItem.ID
+	OwnerID       uuid.UUID `json:"owner_id" gorm:"type:uuid;not null"`      // vlastník pred výmenou
+	DefinitionKey string    `json:"definition_key" gorm:"size:100"`
+	Name          string    `json:"name" gorm:"size:100"`
+	Rarity        string    `json:"rarity,omitempty" gorm:"size:20"`
+	Quantity      int       `json:"quantity" gorm:"default:1"`
+}
+
+func (TradeItem) TableName() string {
+	return "trade_items"
+}
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Meny - soft sa získava hraním, premium za reálne peniaze / admin grant
+const (
+	CurrencyCredits  = "credits"  // soft
+	CurrencyCrystals = "crystals" // premium
+)
+
+// Currencies - všetky meny v poradí pre klienta
+var Currencies = []string{CurrencyCredits, CurrencyCrystals}
+
+// ✅ NEW: Systémové účty ledgeru - protistrana pri vzniku a zániku meny
+const (
+	AccountMint         = "system:mint"          // admin granty, odmeny
+	AccountVendor       = "system:vendor"        // výkup itemov
+	AccountRepair       = "system:repair"        // sink: oprava gearu
+	AccountMarketFees   = "system:market_fees"   // sink: poplatok za vystavenie
+	AccountMarketTax    = "system:market_tax"    // sink: daň z predaja
+	AccountMarketEscrow = "system:market_escrow" // zadržané príhozy v aukciách
+)
+
+// ✅ UPDATED: Peňaženka - zostatky hráča; zhoduje sa so súčtom ledger_entries (kontroluje reconcile)
+type Wallet struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	Credits   int64     `json:"credits" gorm:"not null;default:0"`
+	Crystals  int64     `json:"crystals" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (Wallet) TableName() string {
+	return "wallets"
+}
+
+// ✅ NEW: Transakcia ledgeru - súčet jej záznamov je v každej mene nula
+type LedgerTransaction struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+
+	Currency      string     `json:"currency" gorm:"not null;size:20"`
+	Reason        string     `json:"reason" gorm:"not null;size:50;index"` // market_buy, admin_grant, repair, ...
+	ReferenceType string     `json:"reference_type,omitempty" gorm:"size:50"`
+	ReferenceID   *uuid.UUID `json:"reference_id,omitempty" gorm:"type:uuid;index"`
+	CreatedBy     *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"` // admin pri grante
+	Note          string     `json:"note,omitempty" gorm:"type:text"`
+
+	Entries []LedgerEntry `json:"entries,omitempty" gorm:"foreignKey:TransactionID"`
+}
+
+func (LedgerTransaction) TableName() string {
+	return "ledger_transactions"
+}
+
+// ✅ NEW: Záznam ledgeru - kladná suma pripisuje, záporná sťahuje z účtu
+type LedgerEntry struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	TransactionID uuid.UUID  `json:"transaction_id" gorm:"type:uuid;not null;index"`
+	Account       string     `json:"account" gorm:"not null;size:64;index"` // user:<uuid> alebo system:<name>
+	UserID        *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid;index"`
+	Currency      string     `json:"currency" gorm:"not null;size:20"`
+	Amount        int64      `json:"amount" gorm:"not null"`
+	BalanceAfter  *int64     `json:"balance_after,omitempty"` // len hráčske účty
+}
+
+func (LedgerEntry) TableName() string {
+	return "ledger_entries"
+}
@@ -0,0 +1,82 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: XP ledger - append-only záznam každého pridelenia XP
+// Bez BaseModel (žiadny UpdatedAt/DeletedAt), záznamy sa nikdy neupravujú ani nemažú
+type XPLedgerEntry struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+
+	UserID       uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_xp_ledger_source"`
+	Amount       int        `json:"amount" gorm:"not null"` // záporné pre reversal
+	SourceType   string     `json:"source_type" gorm:"not null;size:50;uniqueIndex:idx_xp_ledger_source"`
+	SourceID     string     `json:"source_id" gorm:"not null;size:100;uniqueIndex:idx_xp_ledger_source"`
+	Breakdown    JSONB      `json:"breakdown,omitempty" gorm:"type:jsonb;default:'{}'::jsonb"`
+	Multiplier   float64    `json:"multiplier" gorm:"default:1"`
+	BalanceAfter int        `json:"balance_after"`
+	ReversalOf   *uuid.UUID `json:"reversal_of,omitempty" gorm:"type:uuid;uniqueIndex"`
+	Reason       string     `json:"reason,omitempty" gorm:"size:255"`
+	CreatedBy    *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`      // admin pri reversaloch
+	SeasonID     *uuid.UUID `json:"season_id,omitempty" gorm:"type:uuid;index"` // sezóna, do ktorej sa XP pripísali
+	SeasonXP     int        `json:"season_xp,omitempty" gorm:"default:0"`
+}
+
+func (XPLedgerEntry) TableName() string {
+	return "xp_ledger"
+}
+
+// ✅ NEW: Level definícia (tabuľka level_definitions zo schémy, neprechádza AutoMigrate)
+type LevelDefinition struct {
+	Level            int       `json:"level" gorm:"primaryKey"`
+	XPRequired       int       `json:"xp_required" gorm:"not null"`
+	LevelName        string    `json:"level_name" gorm:"size:50"`
+	FeaturesUnlocked JSONB     `json:"features_unlocked" gorm:"type:jsonb;default:'{}'::jsonb"`
+	CosmeticUnlocks  JSONB     `json:"cosmetic_unlocks" gorm:"type:jsonb;default:'{}'::jsonb"`
+	ItemRewards      JSONB     `json:"item_rewards" gorm:"type:jsonb;default:'{}'::jsonb"` // {"<name>": {"item_type": "gear", "type": "...", "rarity": "...", "quantity": 1}}
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func (LevelDefinition) TableName() string {
+	return "level_definitions"
+}
+
+// ✅ NEW: Parametre XP vzorca pre zber artefaktov (upravuje admin, jeden riadok na kľúč)
+type XPFormula struct {
+	BaseModel
+	Key              string     `json:"key" gorm:"uniqueIndex;not null;size:50"`
+	BaseXP           int        `json:"base_xp" gorm:"not null"`
+	RarityBonuses    JSONB      `json:"rarity_bonuses" gorm:"type:jsonb;default:'{}'::jsonb"`   // {"rare": 5, ...}
+	BiomeBonuses     JSONB      `json:"biome_bonuses" gorm:"type:jsonb;default:'{}'::jsonb"`    // {"industrial": 10, ...}
+	ZoneTierBonus    int        `json:"zone_tier_bonus" gorm:"not null"`                        // XP za tier zóny
+	TierMultipliers  JSONB      `json:"tier_multipliers" gorm:"type:jsonb;default:'{}'::jsonb"` // tier predplatného → násobok, {"2": 1.1}
+	GearMultipliers  JSONB      `json:"gear_multipliers" gorm:"type:jsonb;default:'{}'::jsonb"` // vybavený gear → +bonus, {"geiger_counter": 0.05}
+	DiminishingFree  int        `json:"diminishing_free" gorm:"not null"`                       // zbery v jednej zóne bez penalizácie
+	DiminishingStep  float64    `json:"diminishing_step" gorm:"not null"`                       // -násobok za každý ďalší zber
+	DiminishingFloor float64    `json:"diminishing_floor" gorm:"not null"`                      // minimálny násobok
+	DiminishingHours int        `json:"diminishing_hours" gorm:"not null"`                      // okno, v ktorom sa zbery v zóne počítajú
+	UpdatedBy        *uuid.UUID `json:"updated_by,omitempty" gorm:"type:uuid"`
+}
+
+func (XPFormula) TableName() string {
+	return "xp_formulas"
+}
+
+// ✅ NEW: Časovo obmedzený XP event (napr. víkend 2x XP), voliteľne len pre jeden biom
+type XPEvent struct {
+	BaseModel
+	Name       string     `json:"name" gorm:"not null;size:100"`
+	Multiplier float64    `json:"multiplier" gorm:"not null"`
+	Biome      string     `json:"biome,omitempty" gorm:"size:50"` // prázdne = všetky biomy
+	StartsAt   time.Time  `json:"starts_at" gorm:"not null;index"`
+	EndsAt     time.Time  `json:"ends_at" gorm:"not null;index"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+}
+
+func (XPEvent) TableName() string {
+	return "xp_events"
+}
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Výnos hráča v bunke mapy (append-only, záznamy staršie ako dlhé okno maže scheduler)
+type PlayerCellYield struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_cell_yield_lookup,priority:3"`
+
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index:idx_cell_yield_lookup,priority:1"`
+	Cell   string    `json:"cell" gorm:"not null;size:30;index:idx_cell_yield_lookup,priority:2"` // "z/x/y" dlaždica
+	Kind   string    `json:"kind" gorm:"not null;size:10"`                                        // scan, collect
+	Units  int       `json:"units" gorm:"not null;default:1"`                                     // spawnuté zóny / zobrané itemy
+}
+
+func (PlayerCellYield) TableName() string {
+	return "player_cell_yields"
+}
+
+// ✅ NEW: Parametre vyčerpania oblasti (upravuje admin, jeden riadok na kľúč)
+type AreaYieldConfig struct {
+	BaseModel
+	Key                  string     `json:"key" gorm:"uniqueIndex;not null;size:50"`
+	CellZoom             int        `json:"cell_zoom" gorm:"not null"`              // veľkosť bunky = dlaždica na tomto zoome
+	ShortWindowMinutes   int        `json:"short_window_minutes" gorm:"not null"`   // krátke okno
+	LongWindowHours      int        `json:"long_window_hours" gorm:"not null"`      // dlhé okno
+	ScanFreeShort        int        `json:"scan_free_short" gorm:"not null"`        // spawnuté zóny bez penalizácie v krátkom okne
+	ScanFreeLong         int        `json:"scan_free_long" gorm:"not null"`         // ... v dlhom okne
+	CollectFreeShort     int        `json:"collect_free_short" gorm:"not null"`     // zobrané itemy bez penalizácie v krátkom okne
+	CollectFreeLong      int        `json:"collect_free_long" gorm:"not null"`      // ... v dlhom okne
+	DecayStep            float64    `json:"decay_step" gorm:"not null"`             // -výnos za každú jednotku nad limit
+	MinYield             float64    `json:"min_yield" gorm:"not null"`              // výnos nikdy neklesne pod
+	RarityDowngradeBelow float64    `json:"rarity_downgrade_below" gorm:"not null"` // pod týmto výnosom sa loot zhorší o stupeň
+	UpdatedBy            *uuid.UUID `json:"updated_by,omitempty" gorm:"type:uuid"`
+}
+
+func (AreaYieldConfig) TableName() string {
+	return "area_yield_configs"
+}
@@ -0,0 +1,286 @@
+package crafting
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Vstupy sa berú len z voľných itemov - obľúbené, vybavené a itemy v obchode ostanú nedotknuté
+const availableItems = "user_id = ? AND deleted_at IS NULL AND COALESCE(properties->>'favorite', 'false') <> 'true' AND id NOT IN (SELECT item_id FROM player_loadouts) AND " + items.NotInEscrow
+
+// quantities - JSONB {"<kľúč definície>": množstvo} (z DB float64, z predvolených receptov int)
+func quantities(raw common.JSONB) map[string]int {
+	result := make(map[string]int, len(raw))
+	for key, value := range raw {
+		var n int
+		switch v := value.(type) {
+		case float64:
+			n = int(v)
+		case int:
+			n = v
+		}
+		if n > 0 {
+			result[key] = n
+		}
+	}
+	return result
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// missingInputs - koľko ktorého vstupu hráčovi chýba (prázdne = môže craftovať)
+func missingInputs(inputs, owned map[string]int) map[string]int {
+	missing := map[string]int{}
+	for key, required := range inputs {
+		if have := owned[key]; have < required {
+			missing[key] = required - have
+		}
+	}
+	return missing
+}
+
+func unlocked(recipe common.CraftingRecipe, user common.User) bool {
+	return user.Level >= recipe.RequiredLevel && user.ProgressionTier() >= recipe.RequiredTier
+}
+
+// ownedInputs - množstvá voľných itemov hráča podľa kľúča definície
+func ownedInputs(db *gorm.DB, userID uuid.UUID) map[string]int {
+	var rows []struct {
+		DefinitionKey string
+		Total         int
+	}
+	db.Model(&common.InventoryItem{}).
+		Select("definition_key, COALESCE(SUM(quantity), 0) AS total").
+		Where(availableItems, userID).
+		Group("definition_key").
+		Scan(&rows)
+
+	owned := make(map[string]int, len(rows))
+	for _, row := range rows {
+		owned[row.DefinitionKey] = row.Total
+	}
+	return owned
+}
+
+func discoveredRecipes(db *gorm.DB, userID uuid.UUID) map[string]bool {
+	var keys []string
+	db.Model(&common.RecipeDiscovery{}).Where("user_id = ?", userID).Pluck("recipe_key", &keys)
+	discovered := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		discovered[key] = true
+	}
+	return discovered
+}
+
+// discover - recept sa objaví, keď má hráč naraz v inventári aspoň kus každého vstupu
+func discover(db *gorm.DB, userID uuid.UUID, recipes []common.CraftingRecipe, owned map[string]int, discovered map[string]bool) []string {
+	var found []string
+	for _, recipe := range recipes {
+		if !recipe.RequiresDiscovery || discovered[recipe.Key] {
+			continue
+		}
+		inputs := quantities(recipe.Inputs)
+		if len(inputs) == 0 {
+			continue
+		}
+		complete := true
+		for key := range inputs {
+			if owned[key] == 0 {
+				complete = false
+				break
+			}
+		}
+		if !complete {
+			continue
+		}
+
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&common.RecipeDiscovery{UserID: userID, RecipeKey: recipe.Key})
+		if result.Error == nil {
+			discovered[recipe.Key] = true
+			if result.RowsAffected > 0 {
+				found = append(found, recipe.Key)
+			}
+		}
+	}
+	return found
+}
+
+// Craft - spotrebuje vstupy a vytvorí výstupy (alebo spustí craftovanie s CraftSeconds) v jednej transakcii
+func Craft(db *gorm.DB, userID uuid.UUID, recipeKey string) (*CraftResult, error) {
+	var result *CraftResult
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var recipe common.CraftingRecipe
+		if err := tx.Where("key = ? AND is_active = true AND deleted_at IS NULL", recipeKey).First(&recipe).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecipeNotFound
+			}
+			return err
+		}
+		inputs, outputs := quantities(recipe.Inputs), quantities(recipe.Outputs)
+		if len(inputs) == 0 || len(outputs) == 0 {
+			return ErrRecipeUndefined
+		}
+
+		var user common.User
+		if err := tx.Select("id", "level", "tier").First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		if !unlocked(recipe, user) {
+			return ErrRecipeLocked
+		}
+		if recipe.RequiresDiscovery && !discoveredRecipes(tx, userID)[recipe.Key] {
+			return ErrRecipeNotFound
+		}
+
+		if recipe.CraftSeconds > 0 {
+			var active int64
+			tx.Model(&common.CraftingJob{}).
+				Where("user_id = ? AND status = ?", userID, common.CraftingStatusInProgress).Count(&active)
+			if active >= MaxActiveJobs {
+				return ErrTooManyJobs
+			}
+		}
+
+		if missing := missingInputs(inputs, ownedInputs(tx, userID)); len(missing) > 0 {
+			result = &CraftResult{Missing: missing}
+			return ErrMissingInputs
+		}
+
+		now := time.Now()
+		for _, key := range sortedKeys(inputs) {
+			if err := consume(tx, userID, key, inputs[key], now); err != nil {
+				return err
+			}
+		}
+
+		job := common.CraftingJob{
+			UserID:    userID,
+			RecipeKey: recipe.Key,
+			Status:    common.CraftingStatusInProgress,
+			Inputs:    recipe.Inputs,
+			Outputs:   recipe.Outputs,
+			ReadyAt:   now.Add(time.Duration(recipe.CraftSeconds) * time.Second),
+		}
+		result = &CraftResult{Consumed: inputs}
+
+		if recipe.CraftSeconds <= 0 {
+			created, err := createOutputs(tx, userID, recipe.Key, outputs, now)
+			if err != nil {
+				return err
+			}
+			job.Status = common.CraftingStatusCompleted
+			job.CompletedAt = &now
+			result.Items = created
+		}
+
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+		result.Job = job
+		result.ReadyIn = readyIn(job)
+		return nil
+	})
+
+	return result, err
+}
+
+// Claim - po uplynutí CraftSeconds vytvorí výstupy craftovania
+func Claim(db *gorm.DB, userID, jobID uuid.UUID) (*CraftResult, error) {
+	var result *CraftResult
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var job common.CraftingJob
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ? AND status = ?", jobID, userID, common.CraftingStatusInProgress).
+			First(&job).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrJobNotFound
+			}
+			return err
+		}
+
+		result = &CraftResult{Job: job, ReadyIn: readyIn(job)}
+		now := time.Now()
+		if job.ReadyAt.After(now) {
+			return ErrJobNotReady
+		}
+
+		created, err := createOutputs(tx, userID, job.RecipeKey, quantities(job.Outputs), now)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&job).Updates(map[string]interface{}{
+			"status":       common.CraftingStatusCompleted,
+			"completed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		job.Status = common.CraftingStatusCompleted
+		job.CompletedAt = &now
+		result.Job = job
+		result.Items = created
+		return nil
+	})
+
+	return result, err
+}
+
+// consume - odoberie množstvo zo zamknutých voľných itemov, najprv z menších stackov
+func consume(tx *gorm.DB, userID uuid.UUID, key string, amount int, now time.Time) error {
+	var rows []common.InventoryItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(availableItems, userID).Where("definition_key = ?", key).
+		Order("quantity ASC, acquired_at ASC").Find(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if amount == 0 {
+			break
+		}
+		take := min(row.Quantity, amount)
+		amount -= take
+
+		if take == row.Quantity {
+			if err := tx.Model(&row).Update("deleted_at", now).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tx.Model(&row).Update("quantity", row.Quantity-take).Error; err != nil {
+			return err
+		}
+	}
+
+	if amount > 0 {
+		return ErrMissingInputs
+	}
+	return nil
+}
+
+// createOutputs - výstupy receptu do inventára
+func createOutputs(tx *gorm.DB, userID uuid.UUID, recipeKey string, outputs map[string]int, now time.Time) ([]common.InventoryItem, error) {
+	created, err := items.Grant(tx, userID, outputs, common.JSONB{"crafted": true, "recipe": recipeKey}, now)
+	if errors.Is(err, items.ErrUnknownDefinition) {
+		return nil, ErrUnknownOutput
+	}
+	return created, err
+}
@@ -0,0 +1,64 @@
+package crafting
+
+import (
+	"strings"
+	"testing"
+
+	"geoanomaly/internal/common"
+)
+
+func TestMissingInputs(t *testing.T) {
+	inputs := quantities(common.JSONB{
+		"artifact:steel_ingot":     float64(2), // z DB
+		"artifact:machinery_parts": 1,          // z predvoleného receptu
+		"artifact:ignored":         0,
+	})
+	if len(inputs) != 2 {
+		t.Fatalf("got %d inputs, want 2", len(inputs))
+	}
+
+	missing := missingInputs(inputs, map[string]int{"artifact:steel_ingot": 1, "artifact:machinery_parts": 3})
+	if len(missing) != 1 || missing["artifact:steel_ingot"] != 1 {
+		t.Errorf("unexpected missing inputs %v", missing)
+	}
+
+	if missing := missingInputs(inputs, map[string]int{"artifact:steel_ingot": 2, "artifact:machinery_parts": 1}); len(missing) != 0 {
+		t.Errorf("exact amounts should be enough, missing %v", missing)
+	}
+}
+
+func TestUnlocked(t *testing.T) {
+	recipe := common.CraftingRecipe{RequiredLevel: 12, RequiredTier: 2}
+
+	if unlocked(recipe, common.User{Level: 5, Tier: 4}) {
+		t.Error("level requirement must apply even with a high tier")
+	}
+	if !unlocked(recipe, common.User{Level: 12}) {
+		t.Error("level 12 reaches tier 2 through progression")
+	}
+}
+
+func TestDefaultRecipes(t *testing.T) {
+	seen := map[string]bool{}
+	for _, recipe := range defaultRecipes {
+		if seen[recipe.Key] {
+			t.Errorf("duplicate recipe %s", recipe.Key)
+		}
+		seen[recipe.Key] = true
+
+		inputs, outputs := quantities(recipe.Inputs), quantities(recipe.Outputs)
+		if len(inputs) == 0 || len(outputs) == 0 {
+			t.Errorf("%s must have inputs and outputs", recipe.Key)
+		}
+		for key := range inputs {
+			if !strings.HasPrefix(key, "artifact:") && !strings.HasPrefix(key, "gear:") {
+				t.Errorf("%s: input %s is not a definition key", recipe.Key, key)
+			}
+		}
+		for key := range outputs {
+			if !strings.HasPrefix(key, "gear:") {
+				t.Errorf("%s: output %s should be gear or a consumable", recipe.Key, key)
+			}
+		}
+	}
+}
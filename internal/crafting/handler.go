@@ -0,0 +1,237 @@
+package crafting
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// GetRecipes - GET /game/crafting/recipes?craftable=true
+// Recepty so stavom vstupov v inventári; neobjavené recepty sa nezobrazia
+func (h *Handler) GetRecipes(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	uid := userID.(uuid.UUID)
+
+	var user common.User
+	if err := h.db.First(&user, "id = ?", uid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var recipes []common.CraftingRecipe
+	if err := h.db.Where("is_active = true AND deleted_at IS NULL").
+		Order("required_level ASC, key ASC").Find(&recipes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recipes"})
+		return
+	}
+
+	owned := ownedInputs(h.db, uid)
+	discovered := discoveredRecipes(h.db, uid)
+	newlyDiscovered := discover(h.db, uid, recipes, owned, discovered)
+	craftableOnly := c.Query("craftable") == "true"
+
+	views := make([]RecipeView, 0, len(recipes))
+	hidden, craftable := 0, 0
+	for _, recipe := range recipes {
+		if recipe.RequiresDiscovery && !discovered[recipe.Key] {
+			hidden++
+			continue
+		}
+		view := h.recipeView(recipe, user, owned)
+		if view.CanCraft {
+			craftable++
+		} else if craftableOnly {
+			continue
+		}
+		views = append(views, view)
+	}
+
+	response := gin.H{
+		"recipes":   views,
+		"total":     len(views),
+		"craftable": craftable,
+		"hidden":    hidden,
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+	if len(newlyDiscovered) > 0 {
+		response["discovered"] = newlyDiscovered
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CraftRecipe - POST /game/crafting/recipes/:key/craft
+func (h *Handler) CraftRecipe(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	result, err := Craft(h.db, userID.(uuid.UUID), c.Param("key"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrRecipeNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		case errors.Is(err, ErrRecipeLocked):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrMissingInputs):
+			response := gin.H{"error": "Missing ingredients"}
+			if result != nil {
+				response["missing"] = result.Missing
+			}
+			c.JSON(http.StatusBadRequest, response)
+		case errors.Is(err, ErrTooManyJobs):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "max_active": MaxActiveJobs})
+		default:
+			log.Printf("❌ Failed to craft %s: %v", c.Param("key"), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to craft recipe"})
+		}
+		return
+	}
+
+	message := "Crafted successfully"
+	if result.Job.Status == common.CraftingStatusInProgress {
+		message = "Crafting started"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   message,
+		"result":    result,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// GetJobs - GET /game/crafting/jobs
+// Bežiace craftovania a posledné dokončené
+func (h *Handler) GetJobs(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var jobs []common.CraftingJob
+	if err := h.db.Where("user_id = ?", userID).
+		Order("CASE WHEN status = 'in_progress' THEN 0 ELSE 1 END, created_at DESC").
+		Limit(50).Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch crafting jobs"})
+		return
+	}
+
+	views := make([]gin.H, 0, len(jobs))
+	ready := 0
+	for _, job := range jobs {
+		remaining := readyIn(job)
+		if job.Status == common.CraftingStatusInProgress && remaining == 0 {
+			ready++
+		}
+		views = append(views, gin.H{"job": job, "ready_in": remaining})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":      views,
+		"ready":     ready,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// ClaimJob - POST /game/crafting/jobs/:id/claim
+func (h *Handler) ClaimJob(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job UUID"})
+		return
+	}
+
+	result, err := Claim(h.db, userID.(uuid.UUID), jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrJobNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Crafting job not found"})
+		case errors.Is(err, ErrJobNotReady):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":    err.Error(),
+				"ready_at": result.Job.ReadyAt,
+				"ready_in": result.ReadyIn,
+			})
+		default:
+			log.Printf("❌ Failed to claim crafting job %s: %v", jobID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim crafting job"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Crafting finished",
+		"result":    result,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+func (h *Handler) recipeView(recipe common.CraftingRecipe, user common.User, owned map[string]int) RecipeView {
+	inputs, outputs := quantities(recipe.Inputs), quantities(recipe.Outputs)
+
+	view := RecipeView{
+		Key:               recipe.Key,
+		Name:              recipe.Name,
+		Description:       recipe.Description,
+		Inputs:            make([]IngredientView, 0, len(inputs)),
+		Outputs:           make([]IngredientView, 0, len(outputs)),
+		RequiredLevel:     recipe.RequiredLevel,
+		RequiredTier:      recipe.RequiredTier,
+		CraftSeconds:      recipe.CraftSeconds,
+		RequiresDiscovery: recipe.RequiresDiscovery,
+		Unlocked:          unlocked(recipe, user),
+	}
+
+	for _, key := range sortedKeys(inputs) {
+		view.Inputs = append(view.Inputs, IngredientView{
+			Key:      key,
+			Name:     h.definitionName(key),
+			Required: inputs[key],
+			Owned:    owned[key],
+			Complete: owned[key] >= inputs[key],
+		})
+	}
+	for _, key := range sortedKeys(outputs) {
+		view.Outputs = append(view.Outputs, IngredientView{Key: key, Name: h.definitionName(key), Required: outputs[key]})
+	}
+
+	view.CanCraft = view.Unlocked && len(inputs) > 0 && len(outputs) > 0 && len(missingInputs(inputs, owned)) == 0
+	return view
+}
+
+func (h *Handler) definitionName(key string) string {
+	if def, ok := items.Lookup(h.db, key); ok {
+		return def.Name
+	}
+	return key
+}
@@ -0,0 +1,116 @@
+package crafting
+
+import (
+	"log"
+
+	"geoanomaly/internal/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Predvolené recepty - z artefaktov, ktoré hráči nazbierajú, vznikne gear a spotrebné predmety.
+// Zapíšu sa do crafting_recipes, ak tam ešte nie sú; úpravy a nové recepty sa robia priamo v DB.
+var defaultRecipes = []common.CraftingRecipe{
+	{
+		Key:         "first_aid_kit",
+		Name:        "Field Medicine",
+		Description: "Herbal extract and hospital supplies make two first aid kits",
+		Inputs:      ingredients("artifact:herbal_extract", 2, "artifact:medical_supplies", 1),
+		Outputs:     ingredients("gear:first_aid_kit", 2),
+	},
+	{
+		Key:           "crowbar",
+		Name:          "Improvised Crowbar",
+		Description:   "Forge a crowbar from salvaged steel",
+		Inputs:        ingredients("artifact:steel_ingot", 2, "artifact:machinery_parts", 1),
+		Outputs:       ingredients("gear:crowbar", 1),
+		RequiredLevel: 3,
+	},
+	{
+		Key:           "hard_hat",
+		Name:          "Reinforced Hard Hat",
+		Description:   "Steel plates riveted with old machine cogs",
+		Inputs:        ingredients("artifact:steel_ingot", 3, "artifact:rusty_gear", 1),
+		Outputs:       ingredients("gear:hard_hat", 1),
+		RequiredLevel: 5,
+		RequiredTier:  1,
+		CraftSeconds:  60,
+	},
+	{
+		Key:           "radiation_pills",
+		Name:          "Iodine Synthesis",
+		Description:   "Alpine herbs and a reactive sample give three doses of pills",
+		Inputs:        ingredients("artifact:herbal_extract", 1, "artifact:mountain_herb", 1, "artifact:chemical_sample", 1),
+		Outputs:       ingredients("gear:radiation_pills", 3),
+		RequiredLevel: 12,
+		RequiredTier:  2,
+	},
+	{
+		Key:           "neutralizer",
+		Name:          "Chemical Neutralizer",
+		Description:   "Dilute an experimental compound with purified water",
+		Inputs:        ingredients("artifact:chemical_compound", 1, "artifact:filtered_water", 1),
+		Outputs:       ingredients("gear:neutralizer", 2),
+		RequiredLevel: 20,
+		RequiredTier:  3,
+	},
+	{
+		Key:               "gas_mask",
+		Name:              "Military Gas Mask",
+		Description:       "A catalyst-coated filter in a lab-grade mask",
+		Inputs:            ingredients("artifact:chemical_compound", 2, "artifact:lab_equipment", 1, "artifact:catalyst", 1),
+		Outputs:           ingredients("gear:gas_mask", 1),
+		RequiredLevel:     20,
+		RequiredTier:      3,
+		CraftSeconds:      300,
+		RequiresDiscovery: true,
+	},
+	{
+		Key:               "geiger_counter",
+		Name:              "Radiation Detector",
+		Description:       "Rebuild a working detector around an uranium reference sample",
+		Inputs:            ingredients("artifact:electronic_component", 2, "artifact:electronics", 1, "artifact:uranium_ore", 1),
+		Outputs:           ingredients("gear:geiger_counter", 1),
+		RequiredLevel:     20,
+		RequiredTier:      3,
+		CraftSeconds:      600,
+		RequiresDiscovery: true,
+	},
+	{
+		Key:               "chemical_suit",
+		Name:              "Chemical Protection Suit",
+		Description:       "Seal a suit with compound-treated steel mesh",
+		Inputs:            ingredients("artifact:chemical_compound", 3, "artifact:catalyst", 1, "artifact:steel_ingot", 2),
+		Outputs:           ingredients("gear:chemical_suit", 1),
+		RequiredLevel:     30,
+		RequiredTier:      4,
+		CraftSeconds:      900,
+		RequiresDiscovery: true,
+	},
+}
+
+// ingredients - páry kľúč definície, množstvo
+func ingredients(pairs ...interface{}) common.JSONB {
+	result := common.JSONB{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		result[pairs[i].(string)] = pairs[i+1]
+	}
+	return result
+}
+
+// SeedRecipes - doplní chýbajúce predvolené recepty (existujúce nemení)
+func SeedRecipes(db *gorm.DB) error {
+	for _, recipe := range defaultRecipes {
+		recipe := recipe
+		recipe.IsActive = true
+		result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).Create(&recipe)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("🛠️ Seeded crafting recipe: %s", recipe.Key)
+		}
+	}
+	return nil
+}
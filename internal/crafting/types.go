@@ -0,0 +1,62 @@
+package crafting
+
+import (
+	"errors"
+	"time"
+
+	"geoanomaly/internal/common"
+)
+
+var (
+	ErrRecipeNotFound  = errors.New("recipe not found")
+	ErrRecipeLocked    = errors.New("recipe requires a higher level or tier")
+	ErrMissingInputs   = errors.New("missing ingredients")
+	ErrTooManyJobs     = errors.New("too many crafts in progress")
+	ErrJobNotFound     = errors.New("crafting job not found")
+	ErrJobNotReady     = errors.New("crafting is not finished yet")
+	ErrUnknownOutput   = errors.New("recipe produces an unknown item")
+	ErrRecipeUndefined = errors.New("recipe has no inputs or outputs")
+)
+
+// Súbežne bežiace craftovania s CraftSeconds
+const MaxActiveJobs = 3
+
+// IngredientView - vstup alebo výstup receptu
+type IngredientView struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Required int    `json:"required"`
+	Owned    int    `json:"owned,omitempty"`
+	Complete bool   `json:"complete,omitempty"`
+}
+
+// RecipeView - recept so stavom pre hráča
+type RecipeView struct {
+	Key               string           `json:"key"`
+	Name              string           `json:"name"`
+	Description       string           `json:"description,omitempty"`
+	Inputs            []IngredientView `json:"inputs"`
+	Outputs           []IngredientView `json:"outputs"`
+	RequiredLevel     int              `json:"required_level"`
+	RequiredTier      int              `json:"required_tier"`
+	CraftSeconds      int              `json:"craft_seconds"`
+	RequiresDiscovery bool             `json:"requires_discovery"`
+	Unlocked          bool             `json:"unlocked"` // level a tier
+	CanCraft          bool             `json:"can_craft"`
+}
+
+// CraftResult - výsledok craftovania alebo vyzdvihnutia
+type CraftResult struct {
+	Job      common.CraftingJob     `json:"job"`
+	Items    []common.InventoryItem `json:"items,omitempty"` // prázdne, kým craft beží
+	Consumed map[string]int         `json:"consumed,omitempty"`
+	Missing  map[string]int         `json:"missing,omitempty"`
+	ReadyIn  int                    `json:"ready_in,omitempty"` // sekundy
+}
+
+func readyIn(job common.CraftingJob) int {
+	if remaining := time.Until(job.ReadyAt); remaining > 0 {
+		return int(remaining.Seconds()) + 1
+	}
+	return 0
+}
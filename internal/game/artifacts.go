@@ -153,19 +153,9 @@ func (h *Handler) getRequiredTierForRarity(rarity string) int {
 	}
 }
 
+// userTier = User.ProgressionTier() (tier alebo level)
 func (h *Handler) canAccessBiome(biome string, userTier int) bool {
-	biomeRequirements := map[string]int{
-		BiomeForest:      0,
-		BiomeMountain:    1,
-		BiomeUrban:       1,
-		BiomeWater:       1,
-		BiomeIndustrial:  2,
-		BiomeRadioactive: 3,
-		BiomeChemical:    4,
-		//BiomeNight:       2, // ← pridané pre nočný biome
-	}
-
-	requiredTier, exists := biomeRequirements[biome]
+	requiredTier, exists := biomeTierRequirements[biome]
 	if !exists {
 		return true // Unknown biome, allow access
 	}
@@ -1,5 +1,7 @@
 package game
 
+import "geoanomaly/internal/common"
+
 // Biome constants
 const (
 	BiomeForest      = "forest"
@@ -82,3 +84,75 @@ const (
 	Tier4MinRadius = 280.0
 	Tier4MaxRadius = 360.0
 )
+
+// Map tile (MVT) constants
+const (
+	MinTileZoom      = 10
+	MaxTileZoom      = 18
+	TileExtent       = 4096
+	TileBuffer       = 64
+	TileCacheSeconds = 120 // 2 min - dlaždice sa navyše invalidujú pri spawne/expirácii zón a zbere itemu
+	DensityGridCells = 16  // počet buniek density vrstvy na šírku dlaždice
+	MaxZoneRadius    = 1000.0
+)
+
+// GeoJSON import/export constants
+const (
+	MinZoneRadius            = 50.0 // zhodné s check_radius v DB
+	MaxGeoJSONExportZones    = 1000
+	MaxGeoJSONImportFeatures = 5000
+)
+
+// ✅ NEW: Sezónne exkluzívne artefakty (ak sezóna neurčí vlastnú šancu)
+const SeasonArtifactDefaultChance = 0.1
+
+// Hidden artifacts & detector constants
+const (
+	HiddenItemsZoneChance   = 0.25 // šanca, že dynamická zóna má skryté artefakty
+	DetectorRevealRadius    = 8.0  // artefakt sa odhalí až keď je hráč bližšie ako 8m
+	DetectorScansPerMinute  = 30
+	DetectorLevelRangeBonus = 0.1  // +10% dosahu za každý level detektora nad 1
+	DetectorGPSTolerance    = 30.0 // nepresnosť GPS pri overovaní polohy detektora
+	DetectorMaxSpeedMPS     = 7.0  // rýchlejší presun od poslednej polohy = podvrhnutá poloha
+	DetectorNoiseMinutes    = 5    // šum smeru sa pre ten istý artefakt mení len raz za okno
+
+	BasicDetectorRange            = 30.0 // bez vybaveného detektora
+	BasicDetectorBearingError     = 90.0
+	GeigerCounterRange            = 80.0
+	GeigerCounterBearingError     = 35.0
+	RadiationDetectorRange        = 150.0
+	RadiationDetectorBearingError = 15.0
+)
+
+// Hazard constants
+const (
+	HazardEffectHealth     = "health"
+	HazardEffectDurability = "durability"
+	HazardEffectDropItem   = "drop_item"
+
+	MaxPlayerHealth       = common.MaxPlayerHealth
+	MaxGearDurability     = common.MaxGearDurability
+	HazardMinRadius       = 10.0
+	HazardMaxRadius       = 30.0
+	HazardHitCooldownSecs = 60 // ten istý hazard zasiahne hráča max raz za minútu
+
+	// ✅ NEW: Opotrebenie vybaveného gearu za minútu pobytu v nebezpečnej zóne
+	ExposureWearHighPerMinute    = 0.5
+	ExposureWearExtremePerMinute = 1.0
+)
+
+// Moving & shrinking zone constants
+const (
+	MovementTypePath       = "path"
+	MovementTypeRandomWalk = "random_walk"
+
+	MovingZoneChance         = 0.15 // šanca, že dynamická zóna driftuje (náhodná prechádzka)
+	ShrinkingZoneChance      = 0.5  // šanca, že sa dynamická zóna pred expiráciou zmenšuje
+	MovementTickSeconds      = 30   // ako často scheduler prepočíta polohy v DB
+	RandomWalkSegmentSeconds = 300  // každých 5 min nový smer
+	RandomWalkSpeedMPS       = 0.5
+	RandomWalkMaxDrift       = 500.0 // max vzdialenosť od pôvodného stredu
+	MaxZoneSpeedMPS          = 5.0
+	ShrinkWindowMinutes      = 60  // zhodné s TTLStatus "expiring"
+	ShrinkMinRatio           = 0.3 // na konci životnosti má zóna 30% pôvodného polomeru
+)
@@ -0,0 +1,352 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"time"
+
+	"geoanomaly/internal/collections"
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DetectArtifacts - POST /game/zones/:id/detect
+// V zónach so skrytými artefaktmi vracia len silu signálu a približný smer k najbližšiemu artefaktu
+func (h *Handler) DetectArtifacts(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	zoneID := c.Param("id")
+	if zoneID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Zone ID required"})
+		return
+	}
+
+	var req DetectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !IsValidGPSCoordinate(req.Latitude, req.Longitude) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid GPS coordinates"})
+		return
+	}
+
+	if !h.checkDetectorRateLimit(fmt.Sprintf("detect:%v", userID)) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Detector is recharging, try again later"})
+		return
+	}
+
+	var user common.User
+	if err := h.db.First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var zone common.Zone
+	if err := h.db.First(&zone, "id = ? AND is_active = true", zoneID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Zone not found"})
+		return
+	}
+
+	if zone.TierRequired > user.ProgressionTier() {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":          "Insufficient tier level",
+			"required_tier":  zone.TierRequired,
+			"required_level": common.RequiredLevelForTier(zone.TierRequired),
+			"your_tier":      user.Tier,
+			"your_level":     user.Level,
+		})
+		return
+	}
+
+	if !isHiddenItemsZone(zone) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Zone has no hidden items",
+			"message": "Use zone scan to see items in this zone",
+		})
+		return
+	}
+
+	var session common.PlayerSession
+	if err := h.db.Where("user_id = ? AND current_zone = ?", userID, zoneID).First(&session).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Not in zone",
+			"message": "You must enter the zone first",
+		})
+		return
+	}
+
+	// Poloha musí byť v zóne a dosiahnuteľná z poslednej známej polohy
+	now := time.Now()
+	if !validDetectorPosition(CurrentZoneGeometry(zone, now), session, req.Latitude, req.Longitude, now) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid position",
+			"message": "Your position does not match the zone or your last known location",
+		})
+		return
+	}
+
+	// Poloha z detektora je zároveň poslednou polohou hráča (CollectItem z nej overuje odhalenie)
+	h.db.Model(&session).Updates(map[string]interface{}{
+		"last_location_latitude":  req.Latitude,
+		"last_location_longitude": req.Longitude,
+		"last_location_timestamp": now,
+	})
+	h.updateZoneActivity(zone.ID)
+
+	detector := h.getDetectorProfile(user.ID)
+
+	var artifacts []common.Artifact
+	h.db.Where("zone_id = ? AND is_active = true", zoneID).Find(&artifacts)
+	artifacts = liveArtifacts(zone, liveZone(zone, now), artifacts)
+	filteredArtifacts := h.filterArtifactsByTier(artifacts, user.ProgressionTier())
+
+	revealRadius := h.revealRadius(user.ID)
+	revealed := revealedArtifacts(filteredArtifacts, req.Latitude, req.Longitude, revealRadius)
+
+	// Najbližší ešte neodhalený artefakt
+	var nearest *common.Artifact
+	nearestDistance := math.MaxFloat64
+	for i := range filteredArtifacts {
+		distance := CalculateDistance(req.Latitude, req.Longitude,
+			filteredArtifacts[i].Location.Latitude, filteredArtifacts[i].Location.Longitude)
+		if distance > revealRadius && distance < nearestDistance {
+			nearest = &filteredArtifacts[i]
+			nearestDistance = distance
+		}
+	}
+
+	var signal *DetectorSignal
+	if nearest != nil && nearestDistance <= detector.RangeMeters {
+		bearing := calculateBearing(req.Latitude, req.Longitude, nearest.Location.Latitude, nearest.Location.Longitude)
+		noise := bearingNoise(userID.(uuid.UUID), nearest.ID, now)
+		signal = buildDetectorSignal(nearestDistance, bearing, detector, noise)
+	}
+
+	response := gin.H{
+		"zone_id":           zone.ID,
+		"zone_name":         zone.Name,
+		"detector":          detector,
+		"signal":            signal,
+		"revealed":          h.addDistanceToItems(revealed, req.Latitude, req.Longitude),
+		"revealed_count":    len(revealed),
+		"hidden_remaining":  len(filteredArtifacts) - len(revealed),
+		"reveal_radius":     revealRadius,
+		"detection_time":    time.Now().Unix(),
+		"ttl_status":        zone.TTLStatus(),
+		"expires_in":        int64(zone.TimeUntilExpiry().Seconds()),
+		"message":           "No signal",
+		"detector_equipped": detector.Type != "basic",
+	}
+	if signal != nil {
+		response["message"] = fmt.Sprintf("Signal %s %s", signal.Level, signal.Direction)
+	}
+	// ✅ NEW: Každá detekcia opotrebí vybavený detektor
+	if detector.Type != "basic" {
+		if worn := items.WearSlot(h.db, user.ID, common.SlotDetector, items.WearPerAction); len(worn) > 0 {
+			response["worn_gear"] = worn
+		}
+	}
+	if len(revealed) > 0 {
+		response["message"] = fmt.Sprintf("%d artifact(s) revealed nearby!", len(revealed))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Detektor v slote detector loadoutu (bez neho základný)
+func (h *Handler) getDetectorProfile(userID uuid.UUID) DetectorProfile {
+	best := detectorProfileFor("basic", 1)
+
+	for _, slot := range items.Loadout(h.db, userID) {
+		// ✅ NEW: Rozbitý detektor funguje ako základný
+		if slot.Slot != common.SlotDetector || slot.Item == nil || items.IsBroken(*slot.Item) {
+			continue
+		}
+		level := 1
+		if l, ok := slot.Item.Properties["level"].(float64); ok {
+			level = int(l)
+		}
+		best = detectorProfileFor(slot.Definition.Type, level)
+	}
+
+	// ✅ NEW: Staty vybaveného gearu (detection_range)
+	if bonus := items.Stats(h.db, userID).DetectionRange; bonus > 0 {
+		best.RangeMeters *= 1 + bonus
+	}
+
+	// ✅ NEW: Trvalý bonus k dosahu z dokončených zbierok
+	if bonus := collections.PassiveBonuses(h.db, userID).DetectionRange; bonus > 0 {
+		best.RangeMeters *= 1 + bonus
+	}
+
+	// ✅ NEW: Dočasné zosilnenie z použitého itemu (napr. geiger_counter)
+	if boost := items.BuffValue(h.db, userID, common.EffectDetectionBoost); boost > 0 {
+		best.RangeMeters *= 1 + boost
+	}
+
+	return best
+}
+
+func detectorProfileFor(detectorType string, level int) DetectorProfile {
+	if level < 1 {
+		level = 1
+	}
+
+	var rangeMeters, bearingAccuracy float64
+	switch detectorType {
+	case "geiger_counter":
+		rangeMeters, bearingAccuracy = GeigerCounterRange, GeigerCounterBearingError
+	case "radiation_detector":
+		rangeMeters, bearingAccuracy = RadiationDetectorRange, RadiationDetectorBearingError
+	default:
+		return DetectorProfile{
+			Type:            "basic",
+			Level:           1,
+			RangeMeters:     BasicDetectorRange,
+			BearingAccuracy: BasicDetectorBearingError,
+		}
+	}
+
+	// Vyšší level = väčší dosah a presnejší smer
+	bonus := float64(level-1) * DetectorLevelRangeBonus
+	return DetectorProfile{
+		Type:            detectorType,
+		Level:           level,
+		RangeMeters:     rangeMeters * (1 + bonus),
+		BearingAccuracy: bearingAccuracy / (1 + bonus),
+	}
+}
+
+// Hot/cold signál - smer je zámerne nepresný podľa kvality detektora
+// Sila je len hrubé pásmo, presná hodnota by umožnila triangulovať artefakt z pár meraní
+// noise (-1..1) určí odchýlku smeru v rámci BearingAccuracy
+func buildDetectorSignal(distance, bearing float64, detector DetectorProfile, noise float64) *DetectorSignal {
+	strength := 1 - distance/detector.RangeMeters
+	if strength < 0 {
+		strength = 0
+	}
+	level := signalLevel(strength)
+
+	noisyBearing := bearing + noise*detector.BearingAccuracy
+	noisyBearing = math.Mod(noisyBearing+360, 360)
+	noisyBearing = math.Round(noisyBearing/5) * 5
+
+	return &DetectorSignal{
+		Strength:       signalBandStrength(level),
+		Level:          level,
+		BearingDegrees: noisyBearing,
+		Direction:      bearingToDirection(noisyBearing),
+	}
+}
+
+// bearingNoise - odchýlka smeru (-1..1) pevná pre hráča, artefakt a časové okno.
+// Náhodný šum pri každom pingu by sa dal spriemerovať späť na presný smer.
+func bearingNoise(userID, artifactID uuid.UUID, now time.Time) float64 {
+	window := now.Unix() / int64(DetectorNoiseMinutes*60)
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%s:%s:%d", userID, artifactID, window)
+	return float64(hash.Sum64()%20001)/10000 - 1
+}
+
+// checkDetectorRateLimit - pevné okno DetectorScansPerMinute; INCR založí kľúč pri prvom pingu
+// a okno začína prvým požiadavkom
+func (h *Handler) checkDetectorRateLimit(key string) bool {
+	if h.redis == nil {
+		return true // Allow if Redis unavailable
+	}
+
+	count, err := h.redis.Incr(context.Background(), key).Result()
+	if err != nil {
+		return true // Allow if Redis error
+	}
+	if count == 1 {
+		h.redis.Expire(context.Background(), key, time.Minute)
+	}
+	return count <= DetectorScansPerMinute
+}
+
+func signalLevel(strength float64) string {
+	switch {
+	case strength >= 0.75:
+		return "hot"
+	case strength >= 0.4:
+		return "warm"
+	default:
+		return "cold"
+	}
+}
+
+func signalBandStrength(level string) float64 {
+	switch level {
+	case "hot":
+		return 0.9
+	case "warm":
+		return 0.6
+	default:
+		return 0.25
+	}
+}
+
+// Poloha v zóne (s toleranciou GPS) a nie rýchlejší presun od poslednej polohy v session než DetectorMaxSpeedMPS
+func validDetectorPosition(geometry ZoneGeometry, session common.PlayerSession, lat, lng float64, now time.Time) bool {
+	if CalculateDistance(geometry.Center.Latitude, geometry.Center.Longitude, lat, lng) > float64(geometry.RadiusMeters)+DetectorGPSTolerance {
+		return false
+	}
+
+	if session.LastLocationTimestamp.IsZero() || (session.LastLocationLatitude == 0 && session.LastLocationLongitude == 0) {
+		return true
+	}
+	elapsed := math.Max(now.Sub(session.LastLocationTimestamp).Seconds(), 0)
+	moved := CalculateDistance(session.LastLocationLatitude, session.LastLocationLongitude, lat, lng)
+	return moved <= elapsed*DetectorMaxSpeedMPS+DetectorGPSTolerance
+}
+
+// Dosah odhalenia hráča - aktívny reveal efekt (napr. flashlight) ho zväčší
+func (h *Handler) revealRadius(userID uuid.UUID) float64 {
+	return math.Max(DetectorRevealRadius, items.BuffValue(h.db, userID, common.EffectReveal))
+}
+
+// Artefakty v dosahu odhalenia
+func revealedArtifacts(artifacts []common.Artifact, lat, lng, radius float64) []common.Artifact {
+	var revealed []common.Artifact
+	for _, artifact := range artifacts {
+		if CalculateDistance(lat, lng, artifact.Location.Latitude, artifact.Location.Longitude) <= radius {
+			revealed = append(revealed, artifact)
+		}
+	}
+	return revealed
+}
+
+func isHiddenItemsZone(zone common.Zone) bool {
+	hidden, _ := zone.Properties["hidden_items"].(bool)
+	return hidden
+}
+
+// Počiatočný azimut (0° = sever, v smere hodinových ručičiek)
+func calculateBearing(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dLon := (lon2 - lon1) * math.Pi / 180
+
+	y := math.Sin(dLon) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLon)
+
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+func bearingToDirection(bearing float64) string {
+	directions := []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+	index := int(math.Round(math.Mod(bearing+360, 360)/45)) % len(directions)
+	return directions[index]
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+)
+
+func TestCalculateBearing(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lng1, lat2, lng2 float64
+		want                   float64
+	}{
+		{"north", 48.0, 17.0, 48.01, 17.0, 0},
+		{"east", 48.0, 17.0, 48.0, 17.01, 90},
+		{"south", 48.0, 17.0, 47.99, 17.0, 180},
+		{"west", 48.0, 17.0, 48.0, 16.99, 270},
+	}
+	for _, tt := range tests {
+		got := calculateBearing(tt.lat1, tt.lng1, tt.lat2, tt.lng2)
+		if math.Abs(got-tt.want) > 0.5 {
+			t.Errorf("%s: calculateBearing() = %v; want ~%v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBearingToDirection(t *testing.T) {
+	tests := map[float64]string{0: "N", 44: "NE", 90: "E", 180: "S", 230: "SW", 350: "N"}
+	for bearing, want := range tests {
+		if got := bearingToDirection(bearing); got != want {
+			t.Errorf("bearingToDirection(%v) = %s; want %s", bearing, got, want)
+		}
+	}
+}
+
+func TestDetectorProfileFor(t *testing.T) {
+	basic := detectorProfileFor("unknown", 5)
+	geiger := detectorProfileFor("geiger_counter", 1)
+	radiation := detectorProfileFor("radiation_detector", 1)
+
+	if basic.Type != "basic" || basic.RangeMeters != BasicDetectorRange {
+		t.Errorf("unknown detector should fall back to basic, got %+v", basic)
+	}
+	if !(basic.RangeMeters < geiger.RangeMeters && geiger.RangeMeters < radiation.RangeMeters) {
+		t.Errorf("detector ranges should increase basic < geiger < radiation, got %v %v %v",
+			basic.RangeMeters, geiger.RangeMeters, radiation.RangeMeters)
+	}
+	if radiation.BearingAccuracy >= geiger.BearingAccuracy {
+		t.Errorf("radiation detector should be more precise than geiger counter")
+	}
+
+	upgraded := detectorProfileFor("geiger_counter", 3)
+	if upgraded.RangeMeters <= geiger.RangeMeters || upgraded.BearingAccuracy >= geiger.BearingAccuracy {
+		t.Errorf("higher level detector should have better range and precision, got %+v", upgraded)
+	}
+}
+
+func TestBuildDetectorSignal(t *testing.T) {
+	detector := detectorProfileFor("radiation_detector", 1)
+
+	near := buildDetectorSignal(10, 90, detector, 1)
+	far := buildDetectorSignal(detector.RangeMeters-1, 90, detector, -1)
+
+	if near.Level != "hot" || far.Level != "cold" {
+		t.Errorf("signal levels = %s/%s; want hot/cold", near.Level, far.Level)
+	}
+	if near.Strength <= far.Strength {
+		t.Errorf("closer artifact should have stronger signal")
+	}
+	if diff := math.Abs(near.BearingDegrees - 90); diff > detector.BearingAccuracy+5 {
+		t.Errorf("bearing %v outside accuracy ±%v", near.BearingDegrees, detector.BearingAccuracy)
+	}
+}
+
+func TestDetectorSignalBands(t *testing.T) {
+	detector := detectorProfileFor("radiation_detector", 1)
+
+	// Susedné vzdialenosti v rovnakom pásme musia vrátiť rovnakú silu
+	a := buildDetectorSignal(detector.RangeMeters*0.7, 0, detector, 0)
+	b := buildDetectorSignal(detector.RangeMeters*0.8, 0, detector, 0)
+	if a.Strength != b.Strength {
+		t.Errorf("strength within one band leaks distance: %v vs %v", a.Strength, b.Strength)
+	}
+}
+
+func TestBearingNoise(t *testing.T) {
+	userID, artifactID := uuid.New(), uuid.New()
+	now := time.Unix(1_700_000_000, 0).Truncate(DetectorNoiseMinutes * time.Minute)
+
+	// Opakované pingy v jednom okne dajú rovnaký šum - priemerovaním sa presný smer nezíska
+	first := bearingNoise(userID, artifactID, now)
+	if again := bearingNoise(userID, artifactID, now.Add(time.Minute)); again != first {
+		t.Errorf("noise changed within one window: %v vs %v", first, again)
+	}
+	if first < -1 || first > 1 {
+		t.Errorf("noise %v outside -1..1", first)
+	}
+
+	changed := false
+	for i := 1; i <= 5 && !changed; i++ {
+		changed = bearingNoise(userID, artifactID, now.Add(time.Duration(i)*DetectorNoiseMinutes*time.Minute)) != first ||
+			bearingNoise(uuid.New(), artifactID, now) != first
+	}
+	if !changed {
+		t.Error("noise should differ across windows and players")
+	}
+}
+
+func TestValidDetectorPosition(t *testing.T) {
+	now := time.Now()
+	geometry := ZoneGeometry{Center: LocationPoint{Latitude: 48.0, Longitude: 17.0}, RadiusMeters: 200}
+	fresh := common.PlayerSession{}
+
+	if !validDetectorPosition(geometry, fresh, 48.001, 17.0, now) {
+		t.Error("position ~111m from center of a 200m zone should be valid")
+	}
+	if validDetectorPosition(geometry, fresh, 48.01, 17.0, now) {
+		t.Error("position ~1.1km from center should be rejected")
+	}
+
+	moving := common.PlayerSession{
+		LastLocationLatitude:  48.0,
+		LastLocationLongitude: 17.0,
+		LastLocationTimestamp: now.Add(-10 * time.Second),
+	}
+	if !validDetectorPosition(geometry, moving, 48.0005, 17.0, now) {
+		t.Error("walking ~55m in 10s should be valid")
+	}
+	if validDetectorPosition(geometry, moving, 48.0015, 17.0, now) {
+		t.Error("jumping ~167m in 10s should be rejected")
+	}
+}
@@ -57,6 +57,7 @@ func (h *Handler) addDistanceToGear(gear []common.Gear, playerLat, playerLng flo
 }
 
 // ✅ Enhanced tier checking with detailed logging
+// userTier = User.ProgressionTier() (vyšší z tieru a levelu)
 func (h *Handler) CheckUserCanCollectItem(userTier int, itemType, itemID string) (bool, string) {
 	switch itemType {
 	case "artifact":
@@ -68,12 +69,13 @@ func (h *Handler) CheckUserCanCollectItem(userTier int, itemType, itemID string)
 		if !h.canCollectArtifact(artifact, userTier) {
 			requiredTier := h.getRequiredTierForRarity(artifact.Rarity)
 			log.Printf("🚫 User tier %d cannot collect %s artifact (requires tier %d)", userTier, artifact.Rarity, requiredTier)
-			return false, fmt.Sprintf("Requires tier %d to collect %s artifacts", requiredTier, artifact.Rarity)
+			return false, fmt.Sprintf("Requires tier %d or level %d to collect %s artifacts",
+				requiredTier, common.RequiredLevelForTier(requiredTier), artifact.Rarity)
 		}
 
 		if !h.canAccessBiome(artifact.Biome, userTier) {
 			log.Printf("🚫 User tier %d cannot access %s biome", userTier, artifact.Biome)
-			return false, fmt.Sprintf("Requires higher tier to access %s biome", artifact.Biome)
+			return false, biomeAccessMessage(artifact.Biome)
 		}
 
 		return true, "OK"
@@ -87,12 +89,12 @@ func (h *Handler) CheckUserCanCollectItem(userTier int, itemType, itemID string)
 		maxLevel := h.getMaxGearLevelForTier(userTier)
 		if gear.Level > maxLevel {
 			log.Printf("🚫 User tier %d cannot collect level %d gear (max level %d)", userTier, gear.Level, maxLevel)
-			return false, fmt.Sprintf("Requires higher tier to collect level %d gear", gear.Level)
+			return false, fmt.Sprintf("Requires higher tier or level to collect level %d gear", gear.Level)
 		}
 
 		if !h.canAccessBiome(gear.Biome, userTier) {
 			log.Printf("🚫 User tier %d cannot access %s biome", userTier, gear.Biome)
-			return false, fmt.Sprintf("Requires higher tier to access %s biome", gear.Biome)
+			return false, biomeAccessMessage(gear.Biome)
 		}
 
 		return true, "OK"
@@ -101,3 +103,8 @@ func (h *Handler) CheckUserCanCollectItem(userTier int, itemType, itemID string)
 		return false, "Invalid item type"
 	}
 }
+
+func biomeAccessMessage(biome string) string {
+	requiredTier := biomeTierRequirements[biome]
+	return fmt.Sprintf("Requires tier %d or level %d to access %s biome", requiredTier, common.RequiredLevelForTier(requiredTier), biome)
+}
@@ -0,0 +1,943 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GeoJSON štruktúry pre export/import mapového obsahu (QGIS)
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+type GeoJSONFeature struct {
+	Type       string           `json:"type"`
+	Geometry   *GeoJSONGeometry `json:"geometry"`
+	Properties json.RawMessage  `json:"properties"`
+}
+
+type GeoJSONGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// GeoJSONProperties - zóny aj itemy sú ploché features, itemy odkazujú na zónu cez zone_id
+type GeoJSONProperties struct {
+	Kind             string      `json:"kind"` // zone, artifact, gear
+	ID               string      `json:"id,omitempty"`
+	ZoneID           string      `json:"zone_id,omitempty"`
+	Name             string      `json:"name,omitempty"`
+	Description      string      `json:"description,omitempty"`
+	TierRequired     *int        `json:"tier_required,omitempty"`
+	RadiusMeters     int         `json:"radius_meters,omitempty"`
+	ZoneType         string      `json:"zone_type,omitempty"`
+	Biome            string      `json:"biome,omitempty"`
+	DangerLevel      string      `json:"danger_level,omitempty"`
+	EventType        string      `json:"event_type,omitempty"`
+	HiddenItems      bool        `json:"hidden_items,omitempty"`
+	MovementType     string      `json:"movement_type,omitempty"` // path, random_walk
+	SpeedMPS         float64     `json:"speed_mps,omitempty"`
+	Path             [][]float64 `json:"path,omitempty"`            // [[lng, lat], ...] waypointy pre movement_type=path
+	MovementOrigin   []float64   `json:"movement_origin,omitempty"` // [lng, lat] štart pohybu; bez neho štartuje v bode zóny
+	MovementStarted  int64       `json:"movement_started_at,omitempty"`
+	MovementSeed     int64       `json:"movement_seed,omitempty"`
+	MaxDriftMeters   float64     `json:"max_drift_meters,omitempty"`
+	Shrinking        bool        `json:"shrinking,omitempty"`
+	ExpiresAt        *time.Time  `json:"expires_at,omitempty"`
+	IsActive         *bool       `json:"is_active,omitempty"`
+	Type             string      `json:"type,omitempty"`
+	Rarity           string      `json:"rarity,omitempty"`
+	Level            int         `json:"level,omitempty"`
+	ExclusiveToBiome bool        `json:"exclusive_to_biome,omitempty"`
+}
+
+type GeoJSONImportError struct {
+	FeatureIndex int    `json:"feature_index"`
+	Kind         string `json:"kind,omitempty"`
+	ID           string `json:"id,omitempty"`
+	Error        string `json:"error"`
+}
+
+type GeoJSONImportSummary struct {
+	ZonesCreated     int `json:"zones_created"`
+	ZonesUpdated     int `json:"zones_updated"`
+	ArtifactsCreated int `json:"artifacts_created"`
+	ArtifactsUpdated int `json:"artifacts_updated"`
+	GearCreated      int `json:"gear_created"`
+	GearUpdated      int `json:"gear_updated"`
+	// Dynamické zóny patria spawneru - export ich obsahuje, import ich aj s itemami preskočí
+	ZonesSkipped     int `json:"zones_skipped"`
+	ArtifactsSkipped int `json:"artifacts_skipped"`
+	GearSkipped      int `json:"gear_skipped"`
+}
+
+// Výsledok validácie - čo sa zapíše do DB
+type geoJSONImportPlan struct {
+	zones     []plannedZone
+	artifacts []plannedArtifact
+	gear      []plannedGear
+	oldZones  []common.Zone // pôvodné polohy upravovaných zón (invalidácia dlaždíc)
+
+	skippedZones     int
+	skippedArtifacts int
+	skippedGear      int
+}
+
+type plannedZone struct {
+	zone   common.Zone
+	exists bool
+}
+
+type plannedArtifact struct {
+	artifact common.Artifact
+	exists   bool
+}
+
+type plannedGear struct {
+	gear   common.Gear
+	exists bool
+}
+
+// ExportZonesGeoJSON - GET /admin/zones/export?bbox=minLng,minLat,maxLng,maxLat&zone_type=&biome=&tier=
+func (h *Handler) ExportZonesGeoJSON(c *gin.Context) {
+	query := h.db.Model(&common.Zone{})
+
+	if c.Query("include_inactive") != "true" {
+		query = query.Where("is_active = true")
+	}
+
+	if bbox := c.Query("bbox"); bbox != "" {
+		minLng, minLat, maxLng, maxLat, err := parseBBox(bbox)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query = query.Where("location_latitude BETWEEN ? AND ? AND location_longitude BETWEEN ? AND ?",
+			minLat, maxLat, minLng, maxLng)
+	}
+
+	if zoneType := c.Query("zone_type"); zoneType != "" {
+		query = query.Where("zone_type = ?", zoneType)
+	}
+	if biome := c.Query("biome"); biome != "" {
+		query = query.Where("biome = ?", biome)
+	}
+	if tierParam := c.Query("tier"); tierParam != "" {
+		tier, err := strconv.Atoi(tierParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tier"})
+			return
+		}
+		query = query.Where("tier_required = ?", tier)
+	}
+
+	var zones []common.Zone
+	if err := query.
+		Preload("Artifacts", "is_active = true").
+		Preload("Gear", "is_active = true").
+		Limit(MaxGeoJSONExportZones).
+		Find(&zones).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export zones"})
+		return
+	}
+
+	collection := GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []GeoJSONFeature{},
+	}
+
+	artifactCount, gearCount := 0, 0
+	for _, zone := range zones {
+		collection.Features = append(collection.Features, zoneToFeature(zone))
+		for _, artifact := range zone.Artifacts {
+			collection.Features = append(collection.Features, artifactToFeature(artifact))
+			artifactCount++
+		}
+		for _, gear := range zone.Gear {
+			collection.Features = append(collection.Features, gearToFeature(gear))
+			gearCount++
+		}
+	}
+
+	log.Printf("🗺️ GeoJSON export: %d zones, %d artifacts, %d gear", len(zones), artifactCount, gearCount)
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=zones-%s.geojson", time.Now().Format("20060102-150405")))
+	c.JSON(http.StatusOK, collection)
+}
+
+// ImportZonesGeoJSON - POST /admin/zones/import?dry_run=true
+// Validuje celú kolekciu a buď zapíše všetko v jednej transakcii, alebo nič
+func (h *Handler) ImportZonesGeoJSON(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+
+	var collection GeoJSONFeatureCollection
+	if err := c.ShouldBindJSON(&collection); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid GeoJSON", "details": err.Error()})
+		return
+	}
+
+	if collection.Type != "FeatureCollection" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Expected GeoJSON FeatureCollection"})
+		return
+	}
+	if len(collection.Features) > MaxGeoJSONImportFeatures {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Too many features (max %d)", MaxGeoJSONImportFeatures),
+		})
+		return
+	}
+
+	plan, importErrors := h.buildImportPlan(collection)
+	summary := plan.summary()
+
+	if len(importErrors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "GeoJSON validation failed",
+			"dry_run": dryRun,
+			"valid":   false,
+			"errors":  importErrors,
+			"summary": summary,
+		})
+		return
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "GeoJSON is valid (dry run, nothing saved)",
+			"dry_run": true,
+			"valid":   true,
+			"summary": summary,
+			"status":  "success",
+		})
+		return
+	}
+
+	if err := h.applyImportPlan(plan); err != nil {
+		log.Printf("❌ GeoJSON import failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import zones", "details": err.Error()})
+		return
+	}
+
+	// Nové zóny dostanú hazardy podľa biómu, invalidate cached map tiles (nové aj pôvodné polohy zón)
+	for _, planned := range plan.zones {
+		if !planned.exists {
+			h.spawnHazardsInZone(planned.zone)
+		}
+		mapTileCache.InvalidateZone(planned.zone)
+	}
+	for _, zone := range plan.oldZones {
+		mapTileCache.InvalidateZone(zone)
+	}
+
+	log.Printf("✅ GeoJSON import: %+v", summary)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "GeoJSON imported successfully",
+		"dry_run": false,
+		"valid":   true,
+		"summary": summary,
+		"status":  "success",
+	})
+}
+
+// buildImportPlan - zóny sa spracujú ako prvé, aby na ne itemy mohli odkazovať cez zone_id
+func (h *Handler) buildImportPlan(collection GeoJSONFeatureCollection) (*geoJSONImportPlan, []GeoJSONImportError) {
+	plan := &geoJSONImportPlan{}
+	var importErrors []GeoJSONImportError
+
+	addError := func(index int, props GeoJSONProperties, err error) {
+		importErrors = append(importErrors, GeoJSONImportError{
+			FeatureIndex: index,
+			Kind:         props.Kind,
+			ID:           props.ID,
+			Error:        err.Error(),
+		})
+	}
+
+	propsByIndex := make([]GeoJSONProperties, len(collection.Features))
+	var zoneIDs, artifactIDs, gearIDs []uuid.UUID
+	for i, feature := range collection.Features {
+		var props GeoJSONProperties
+		if len(feature.Properties) > 0 {
+			if err := json.Unmarshal(feature.Properties, &props); err != nil {
+				addError(i, props, fmt.Errorf("invalid properties: %v", err))
+				continue
+			}
+		}
+		propsByIndex[i] = props
+
+		id, err := uuid.Parse(props.ID)
+		if err != nil {
+			continue
+		}
+		switch props.Kind {
+		case "zone":
+			zoneIDs = append(zoneIDs, id)
+		case "artifact":
+			artifactIDs = append(artifactIDs, id)
+		case "gear":
+			gearIDs = append(gearIDs, id)
+		}
+	}
+
+	// Existujúce záznamy - update namiesto create
+	existingZones := make(map[uuid.UUID]common.Zone)
+	if len(zoneIDs) > 0 {
+		var zones []common.Zone
+		h.db.Where("id IN ?", zoneIDs).Find(&zones)
+		for _, zone := range zones {
+			existingZones[zone.ID] = zone
+		}
+	}
+	existingArtifacts := make(map[uuid.UUID]common.Artifact)
+	if len(artifactIDs) > 0 {
+		var artifacts []common.Artifact
+		h.db.Where("id IN ?", artifactIDs).Find(&artifacts)
+		for _, artifact := range artifacts {
+			existingArtifacts[artifact.ID] = artifact
+		}
+	}
+	existingGear := make(map[uuid.UUID]common.Gear)
+	if len(gearIDs) > 0 {
+		var gear []common.Gear
+		h.db.Where("id IN ?", gearIDs).Find(&gear)
+		for _, g := range gear {
+			existingGear[g.ID] = g
+		}
+	}
+
+	// 1. Zóny
+	zonesByRef := make(map[string]common.Zone)
+	dynamicRefs := make(map[string]bool)
+	for i, feature := range collection.Features {
+		props := propsByIndex[i]
+		if props.Kind != "zone" {
+			continue
+		}
+
+		// Export obsahuje aj dynamické zóny - spätný import ich nechá spawneru
+		if props.ZoneType == "dynamic" {
+			dynamicRefs[props.ID] = true
+			plan.skippedZones++
+			continue
+		}
+
+		zone, err := h.validateImportedZone(feature.Geometry, props)
+		if err != nil {
+			addError(i, props, err)
+			continue
+		}
+
+		ref := props.ID
+		if ref == "" {
+			ref = zone.ID.String()
+		}
+		if _, duplicate := zonesByRef[ref]; duplicate {
+			addError(i, props, fmt.Errorf("duplicate zone id %s", ref))
+			continue
+		}
+
+		exists := false
+		if id, err := uuid.Parse(props.ID); err == nil {
+			zone.ID = id
+			if existing, found := existingZones[id]; found {
+				if existing.ZoneType == "dynamic" {
+					addError(i, props, fmt.Errorf("dynamic zones cannot be updated by import"))
+					continue
+				}
+				exists = true
+				zone.CreatedAt = existing.CreatedAt
+				zone.LastActivity = existing.LastActivity
+				preserveZoneDynamics(&zone, existing)
+				plan.oldZones = append(plan.oldZones, existing)
+			}
+		}
+
+		zonesByRef[ref] = zone
+		plan.zones = append(plan.zones, plannedZone{zone: zone, exists: exists})
+	}
+
+	// 2. Itemy - zóna z kolekcie alebo existujúca zóna v DB
+	for i, feature := range collection.Features {
+		props := propsByIndex[i]
+		if props.Kind == "zone" {
+			continue
+		}
+		if props.Kind != "artifact" && props.Kind != "gear" {
+			addError(i, props, fmt.Errorf("unknown feature kind %q (expected zone, artifact or gear)", props.Kind))
+			continue
+		}
+
+		zone, found := zonesByRef[props.ZoneID]
+		skip := dynamicRefs[props.ZoneID]
+		if !found && !skip {
+			if id, err := uuid.Parse(props.ZoneID); err == nil {
+				var existing common.Zone
+				if err := h.db.First(&existing, "id = ?", id).Error; err == nil {
+					zone, found = existing, true
+					skip = existing.ZoneType == "dynamic"
+				}
+			}
+		}
+		if skip {
+			if props.Kind == "artifact" {
+				plan.skippedArtifacts++
+			} else {
+				plan.skippedGear++
+			}
+			continue
+		}
+		if !found {
+			addError(i, props, fmt.Errorf("zone %q not found in collection or database", props.ZoneID))
+			continue
+		}
+
+		id, idErr := uuid.Parse(props.ID)
+
+		if props.Kind == "artifact" {
+			artifact, err := h.validateImportedArtifact(feature.Geometry, props, zone)
+			if err != nil {
+				addError(i, props, err)
+				continue
+			}
+			exists := false
+			if idErr == nil {
+				artifact.ID = id
+				if existing, ok := existingArtifacts[id]; ok {
+					// Zozbieraný artefakt sa importom neobnoví, pôvod spawnu ostáva
+					exists = true
+					artifact.CreatedAt = existing.CreatedAt
+					artifact.IsActive = existing.IsActive
+					artifact.Properties = existing.Properties
+				}
+			}
+			plan.artifacts = append(plan.artifacts, plannedArtifact{artifact: artifact, exists: exists})
+		} else {
+			gear, err := h.validateImportedGear(feature.Geometry, props, zone)
+			if err != nil {
+				addError(i, props, err)
+				continue
+			}
+			exists := false
+			if idErr == nil {
+				gear.ID = id
+				if existing, ok := existingGear[id]; ok {
+					exists = true
+					gear.CreatedAt = existing.CreatedAt
+					gear.IsActive = existing.IsActive
+					gear.Properties = existing.Properties
+				}
+			}
+			plan.gear = append(plan.gear, plannedGear{gear: gear, exists: exists})
+		}
+	}
+
+	return plan, importErrors
+}
+
+// validateImportedZone - pravidlá biómov a tierov, rovnaké ako pri dynamickom spawne
+func (h *Handler) validateImportedZone(geometry *GeoJSONGeometry, props GeoJSONProperties) (common.Zone, error) {
+	lat, lng, err := pointFromGeometry(geometry)
+	if err != nil {
+		return common.Zone{}, err
+	}
+
+	if strings.TrimSpace(props.Name) == "" {
+		return common.Zone{}, fmt.Errorf("name is required")
+	}
+
+	zoneType := props.ZoneType
+	if zoneType == "" {
+		zoneType = "static"
+	}
+	if zoneType != "static" && zoneType != "event" {
+		return common.Zone{}, fmt.Errorf("zone_type must be static or event, got %q", zoneType)
+	}
+
+	if props.TierRequired == nil {
+		return common.Zone{}, fmt.Errorf("tier_required is required")
+	}
+	tier := *props.TierRequired
+	if tier < 0 || tier > 4 {
+		return common.Zone{}, fmt.Errorf("tier_required must be between 0 and 4")
+	}
+
+	if props.RadiusMeters < int(MinZoneRadius) || props.RadiusMeters > int(MaxZoneRadius) {
+		return common.Zone{}, fmt.Errorf("radius_meters must be between %d and %d", int(MinZoneRadius), int(MaxZoneRadius))
+	}
+
+	if !isKnownBiome(props.Biome) {
+		return common.Zone{}, fmt.Errorf("unknown biome %q", props.Biome)
+	}
+	template := GetZoneTemplate(props.Biome)
+	if tier < template.MinTierRequired {
+		return common.Zone{}, fmt.Errorf("biome %s requires tier_required >= %d", props.Biome, template.MinTierRequired)
+	}
+
+	dangerLevel := props.DangerLevel
+	if dangerLevel == "" {
+		dangerLevel = template.DangerLevel
+	}
+	switch dangerLevel {
+	case DangerLow, DangerMedium, DangerHigh, DangerExtreme:
+	default:
+		return common.Zone{}, fmt.Errorf("invalid danger_level %q", dangerLevel)
+	}
+
+	if props.ExpiresAt != nil && props.ExpiresAt.Before(time.Now()) {
+		return common.Zone{}, fmt.Errorf("expires_at is in the past")
+	}
+
+	isActive := true
+	if props.IsActive != nil {
+		isActive = *props.IsActive
+	}
+
+	properties := common.JSONB{
+		"event_type":   props.EventType,
+		"created_by":   "admin",
+		"permanent":    props.ExpiresAt == nil,
+		"source":       "geojson_import",
+		"hidden_items": props.HiddenItems,
+	}
+
+	// Pohyb zóny - trasa začína aj končí v bode zóny
+	if props.MovementType != "" {
+		movement, err := movementFromImport(props, lat, lng)
+		if err != nil {
+			return common.Zone{}, err
+		}
+		properties["movement"] = movement
+	}
+	if props.Shrinking {
+		if props.ExpiresAt == nil {
+			return common.Zone{}, fmt.Errorf("shrinking zones require expires_at")
+		}
+		properties["shrink"] = ZoneShrink{OriginalRadius: props.RadiusMeters, MinRatio: ShrinkMinRatio}
+	}
+
+	return common.Zone{
+		BaseModel:    common.BaseModel{ID: uuid.New()},
+		Name:         props.Name,
+		Description:  props.Description,
+		TierRequired: tier,
+		Location: common.Location{
+			Latitude:  lat,
+			Longitude: lng,
+			Timestamp: time.Now(),
+		},
+		RadiusMeters: props.RadiusMeters,
+		IsActive:     isActive,
+		ZoneType:     zoneType,
+		Biome:        props.Biome,
+		DangerLevel:  dangerLevel,
+		ExpiresAt:    props.ExpiresAt,
+		LastActivity: time.Now(),
+		AutoCleanup:  props.ExpiresAt != nil,
+		Properties:   properties,
+	}, nil
+}
+
+// preserveZoneDynamics - nezmenený pohyb a zmenšovanie existujúcej zóny pokračujú, nezačínajú odznova.
+// Stred a polomer ostanú z DB (rovnaký rámec ako uložené itemy), inak by spätný import exportu
+// posunul zónu do staršej polohy a itemy zmenšenej zóny by scheduler zmenšil druhýkrát.
+func preserveZoneDynamics(zone *common.Zone, existing common.Zone) {
+	if movement, ok := zoneMovementFromProperties(*zone); ok {
+		if current, moving := zoneMovementFromProperties(existing); moving && sameMovement(movement, current) {
+			zone.Location = existing.Location
+		}
+	}
+
+	if shrink, ok := zoneShrinkFromProperties(*zone); ok {
+		current, shrinking := zoneShrinkFromProperties(existing)
+		if shrinking && shrink.OriginalRadius == current.OriginalRadius {
+			zone.Properties["shrink"] = current
+			zone.RadiusMeters = existing.RadiusMeters
+		}
+	}
+}
+
+func sameMovement(a, b ZoneMovement) bool {
+	if a.Type != b.Type || a.SpeedMPS != b.SpeedMPS || a.StartedAt != b.StartedAt || a.Seed != b.Seed ||
+		a.MaxDriftMeters != b.MaxDriftMeters || a.OriginLat != b.OriginLat || a.OriginLng != b.OriginLng ||
+		len(a.Path) != len(b.Path) {
+		return false
+	}
+	for i := range a.Path {
+		if a.Path[i] != b.Path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func movementFromImport(props GeoJSONProperties, lat, lng float64) (ZoneMovement, error) {
+	if props.SpeedMPS <= 0 || props.SpeedMPS > MaxZoneSpeedMPS {
+		return ZoneMovement{}, fmt.Errorf("speed_mps must be between 0 and %.0f", MaxZoneSpeedMPS)
+	}
+
+	movement := ZoneMovement{
+		Type:      props.MovementType,
+		SpeedMPS:  props.SpeedMPS,
+		StartedAt: time.Now().Unix(),
+		OriginLat: lat,
+		OriginLng: lng,
+	}
+	// Export zapisuje štart pohybu - spätný import v ňom pokračuje namiesto nového štartu v aktuálnom bode
+	if len(props.MovementOrigin) >= 2 {
+		if !IsValidGPSCoordinate(props.MovementOrigin[1], props.MovementOrigin[0]) {
+			return ZoneMovement{}, fmt.Errorf("invalid movement_origin %v", props.MovementOrigin)
+		}
+		movement.OriginLat, movement.OriginLng = props.MovementOrigin[1], props.MovementOrigin[0]
+	}
+	if props.MovementStarted > 0 {
+		movement.StartedAt = props.MovementStarted
+	}
+
+	switch props.MovementType {
+	case MovementTypePath:
+		if len(props.Path) == 0 {
+			return ZoneMovement{}, fmt.Errorf("path movement requires at least one waypoint")
+		}
+		for _, point := range props.Path {
+			if len(point) < 2 || !IsValidGPSCoordinate(point[1], point[0]) {
+				return ZoneMovement{}, fmt.Errorf("invalid path waypoint %v", point)
+			}
+			movement.Path = append(movement.Path, [2]float64{point[1], point[0]})
+		}
+	case MovementTypeRandomWalk:
+		movement.MaxDriftMeters = RandomWalkMaxDrift
+		if props.MaxDriftMeters > 0 {
+			movement.MaxDriftMeters = props.MaxDriftMeters
+		}
+		movement.Seed = props.MovementSeed
+		if movement.Seed == 0 {
+			movement.Seed = rand.Int63()
+		}
+	default:
+		return ZoneMovement{}, fmt.Errorf("movement_type must be %s or %s", MovementTypePath, MovementTypeRandomWalk)
+	}
+
+	return movement, nil
+}
+
+func (h *Handler) validateImportedArtifact(geometry *GeoJSONGeometry, props GeoJSONProperties, zone common.Zone) (common.Artifact, error) {
+	template := GetZoneTemplate(zone.Biome)
+
+	exclusive := containsString(template.ExclusiveArtifacts, props.Type)
+	if !exclusive && !containsString(template.AllowedArtifacts, props.Type) {
+		return common.Artifact{}, fmt.Errorf("artifact type %q is not allowed in biome %s", props.Type, zone.Biome)
+	}
+
+	rarity := props.Rarity
+	if rarity == "" {
+		rarity = GetArtifactRarity(props.Type, zone.TierRequired)
+	}
+	switch rarity {
+	case "common", "rare", "epic", "legendary":
+	default:
+		return common.Artifact{}, fmt.Errorf("invalid rarity %q", rarity)
+	}
+	if requiredTier := h.getRequiredTierForRarity(rarity); requiredTier > zone.TierRequired {
+		return common.Artifact{}, fmt.Errorf("%s artifacts require zone tier >= %d", rarity, requiredTier)
+	}
+
+	location, err := h.importedItemLocation(geometry, zone)
+	if err != nil {
+		return common.Artifact{}, err
+	}
+
+	name := props.Name
+	if name == "" {
+		name = GetArtifactDisplayName(props.Type)
+	}
+
+	return common.Artifact{
+		BaseModel:        common.BaseModel{ID: uuid.New()},
+		ZoneID:           zone.ID,
+		Name:             name,
+		Type:             props.Type,
+		Rarity:           rarity,
+		Location:         location,
+		Biome:            zone.Biome,
+		ExclusiveToBiome: exclusive,
+		Properties: common.JSONB{
+			"spawn_time":   time.Now().Unix(),
+			"spawner":      "geojson_import",
+			"zone_tier":    zone.TierRequired,
+			"biome":        zone.Biome,
+			"spawn_reason": "admin_import",
+		},
+		IsActive: true,
+	}, nil
+}
+
+func (h *Handler) validateImportedGear(geometry *GeoJSONGeometry, props GeoJSONProperties, zone common.Zone) (common.Gear, error) {
+	template := GetZoneTemplate(zone.Biome)
+
+	if _, allowed := template.GearSpawnRates[props.Type]; !allowed {
+		return common.Gear{}, fmt.Errorf("gear type %q is not allowed in biome %s", props.Type, zone.Biome)
+	}
+
+	level := props.Level
+	if level == 0 {
+		level = zone.TierRequired + 1
+	}
+	if maxLevel := h.getMaxGearLevelForTier(zone.TierRequired); level < 1 || level > maxLevel {
+		return common.Gear{}, fmt.Errorf("gear level must be between 1 and %d for tier %d zone", maxLevel, zone.TierRequired)
+	}
+
+	location, err := h.importedItemLocation(geometry, zone)
+	if err != nil {
+		return common.Gear{}, err
+	}
+
+	name := props.Name
+	if name == "" {
+		name = GetGearDisplayName(props.Type)
+	}
+
+	return common.Gear{
+		BaseModel: common.BaseModel{ID: uuid.New()},
+		ZoneID:    zone.ID,
+		Name:      name,
+		Type:      props.Type,
+		Level:     level,
+		Location:  location,
+		Biome:     zone.Biome,
+		Properties: common.JSONB{
+			"spawn_time":   time.Now().Unix(),
+			"spawner":      "geojson_import",
+			"zone_tier":    zone.TierRequired,
+			"biome":        zone.Biome,
+			"spawn_reason": "admin_import",
+		},
+		IsActive: true,
+	}, nil
+}
+
+// Item bez geometrie dostane náhodnú pozíciu v zóne, inak musí ležať v polomere zóny
+func (h *Handler) importedItemLocation(geometry *GeoJSONGeometry, zone common.Zone) (common.Location, error) {
+	if geometry == nil {
+		return h.generateRandomLocationInZone(zone.Location, zone.RadiusMeters), nil
+	}
+
+	lat, lng, err := pointFromGeometry(geometry)
+	if err != nil {
+		return common.Location{}, err
+	}
+
+	distance := CalculateDistance(zone.Location.Latitude, zone.Location.Longitude, lat, lng)
+	if distance > float64(zone.RadiusMeters) {
+		return common.Location{}, fmt.Errorf("item is %.0fm from zone center (radius %dm)", distance, zone.RadiusMeters)
+	}
+
+	return common.Location{Latitude: lat, Longitude: lng, Timestamp: time.Now()}, nil
+}
+
+func (h *Handler) applyImportPlan(plan *geoJSONImportPlan) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		for _, planned := range plan.zones {
+			zone := planned.zone
+			if err := tx.Omit(clause.Associations).Save(&zone).Error; err != nil {
+				return fmt.Errorf("zone %s: %w", zone.Name, err)
+			}
+		}
+		for _, planned := range plan.artifacts {
+			artifact := planned.artifact
+			if err := tx.Omit(clause.Associations).Save(&artifact).Error; err != nil {
+				return fmt.Errorf("artifact %s: %w", artifact.Name, err)
+			}
+		}
+		for _, planned := range plan.gear {
+			gear := planned.gear
+			if err := tx.Omit(clause.Associations).Save(&gear).Error; err != nil {
+				return fmt.Errorf("gear %s: %w", gear.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (p *geoJSONImportPlan) summary() GeoJSONImportSummary {
+	var summary GeoJSONImportSummary
+	for _, planned := range p.zones {
+		if planned.exists {
+			summary.ZonesUpdated++
+		} else {
+			summary.ZonesCreated++
+		}
+	}
+	for _, planned := range p.artifacts {
+		if planned.exists {
+			summary.ArtifactsUpdated++
+		} else {
+			summary.ArtifactsCreated++
+		}
+	}
+	for _, planned := range p.gear {
+		if planned.exists {
+			summary.GearUpdated++
+		} else {
+			summary.GearCreated++
+		}
+	}
+	summary.ZonesSkipped = p.skippedZones
+	summary.ArtifactsSkipped = p.skippedArtifacts
+	summary.GearSkipped = p.skippedGear
+	return summary
+}
+
+// ============================================
+// GEOJSON HELPERS
+// ============================================
+
+func zoneToFeature(zone common.Zone) GeoJSONFeature {
+	tier := zone.TierRequired
+	isActive := zone.IsActive
+	eventType, _ := zone.Properties["event_type"].(string)
+
+	radius := zone.RadiusMeters
+	shrink, shrinking := zoneShrinkFromProperties(zone)
+	if shrinking {
+		radius = shrink.OriginalRadius
+	}
+
+	props := GeoJSONProperties{
+		Kind:         "zone",
+		ID:           zone.ID.String(),
+		Name:         zone.Name,
+		Description:  zone.Description,
+		TierRequired: &tier,
+		RadiusMeters: radius,
+		ZoneType:     zone.ZoneType,
+		Biome:        zone.Biome,
+		DangerLevel:  zone.DangerLevel,
+		EventType:    eventType,
+		HiddenItems:  isHiddenItemsZone(zone),
+		Shrinking:    shrinking,
+		ExpiresAt:    zone.ExpiresAt,
+		IsActive:     &isActive,
+	}
+
+	if movement, ok := zoneMovementFromProperties(zone); ok {
+		props.MovementType = movement.Type
+		props.SpeedMPS = movement.SpeedMPS
+		props.MovementOrigin = []float64{movement.OriginLng, movement.OriginLat}
+		props.MovementStarted = movement.StartedAt
+		props.MovementSeed = movement.Seed
+		props.MaxDriftMeters = movement.MaxDriftMeters
+		for _, point := range movement.Path {
+			props.Path = append(props.Path, []float64{point[1], point[0]})
+		}
+	}
+
+	return newPointFeature(zone.Location, props)
+}
+
+func artifactToFeature(artifact common.Artifact) GeoJSONFeature {
+	return newPointFeature(artifact.Location, GeoJSONProperties{
+		Kind:             "artifact",
+		ID:               artifact.ID.String(),
+		ZoneID:           artifact.ZoneID.String(),
+		Name:             artifact.Name,
+		Type:             artifact.Type,
+		Rarity:           artifact.Rarity,
+		Biome:            artifact.Biome,
+		ExclusiveToBiome: artifact.ExclusiveToBiome,
+	})
+}
+
+func gearToFeature(gear common.Gear) GeoJSONFeature {
+	return newPointFeature(gear.Location, GeoJSONProperties{
+		Kind:   "gear",
+		ID:     gear.ID.String(),
+		ZoneID: gear.ZoneID.String(),
+		Name:   gear.Name,
+		Type:   gear.Type,
+		Level:  gear.Level,
+		Biome:  gear.Biome,
+	})
+}
+
+func newPointFeature(location common.Location, props GeoJSONProperties) GeoJSONFeature {
+	raw, _ := json.Marshal(props)
+	return GeoJSONFeature{
+		Type: "Feature",
+		Geometry: &GeoJSONGeometry{
+			Type:        "Point",
+			Coordinates: []float64{location.Longitude, location.Latitude},
+		},
+		Properties: raw,
+	}
+}
+
+// GeoJSON poradie súradníc je [lng, lat]
+func pointFromGeometry(geometry *GeoJSONGeometry) (float64, float64, error) {
+	if geometry == nil {
+		return 0, 0, fmt.Errorf("geometry is required")
+	}
+	if geometry.Type != "Point" || len(geometry.Coordinates) < 2 {
+		return 0, 0, fmt.Errorf("only Point geometry is supported")
+	}
+
+	lng, lat := geometry.Coordinates[0], geometry.Coordinates[1]
+	if !IsValidGPSCoordinate(lat, lng) {
+		return 0, 0, fmt.Errorf("invalid coordinates [%v, %v]", lng, lat)
+	}
+	return lat, lng, nil
+}
+
+func parseBBox(bbox string) (float64, float64, float64, float64, error) {
+	parts := strings.Split(bbox, ",")
+	if len(parts) != 4 {
+		return 0, 0, 0, 0, fmt.Errorf("Invalid bbox: expected minLng,minLat,maxLng,maxLat")
+	}
+
+	values := make([]float64, 4)
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("Invalid bbox: %s", part)
+		}
+		values[i] = value
+	}
+
+	if values[0] > values[2] || values[1] > values[3] ||
+		!IsValidGPSCoordinate(values[1], values[0]) || !IsValidGPSCoordinate(values[3], values[2]) {
+		return 0, 0, 0, 0, fmt.Errorf("Invalid bbox: coordinates out of range")
+	}
+	return values[0], values[1], values[2], values[3], nil
+}
+
+func isKnownBiome(biome string) bool {
+	switch biome {
+	case BiomeForest, BiomeMountain, BiomeUrban, BiomeWater, BiomeIndustrial, BiomeRadioactive, BiomeChemical:
+		return true
+	default:
+		return false
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
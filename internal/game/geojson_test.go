@@ -0,0 +1,117 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateImportedZone(t *testing.T) {
+	h := &Handler{}
+	point := &GeoJSONGeometry{Type: "Point", Coordinates: []float64{17.1077, 48.1486}}
+	tier := func(v int) *int { return &v }
+
+	tests := []struct {
+		name  string
+		geom  *GeoJSONGeometry
+		props GeoJSONProperties
+		valid bool
+	}{
+		{"valid static forest", point, GeoJSONProperties{Kind: "zone", Name: "Grove", TierRequired: tier(0), RadiusMeters: 200, Biome: BiomeForest}, true},
+		{"valid event chemical", point, GeoJSONProperties{Kind: "zone", Name: "Spill", ZoneType: "event", TierRequired: tier(4), RadiusMeters: 300, Biome: BiomeChemical}, true},
+		{"biome tier too low", point, GeoJSONProperties{Kind: "zone", Name: "Reactor", TierRequired: tier(1), RadiusMeters: 200, Biome: BiomeRadioactive}, false},
+		{"dynamic not allowed", point, GeoJSONProperties{Kind: "zone", Name: "X", ZoneType: "dynamic", TierRequired: tier(0), RadiusMeters: 200, Biome: BiomeForest}, false},
+		{"missing tier", point, GeoJSONProperties{Kind: "zone", Name: "X", RadiusMeters: 200, Biome: BiomeForest}, false},
+		{"radius too large", point, GeoJSONProperties{Kind: "zone", Name: "X", TierRequired: tier(0), RadiusMeters: 5000, Biome: BiomeForest}, false},
+		{"unknown biome", point, GeoJSONProperties{Kind: "zone", Name: "X", TierRequired: tier(0), RadiusMeters: 200, Biome: "desert"}, false},
+		{"polygon geometry", &GeoJSONGeometry{Type: "Polygon"}, GeoJSONProperties{Kind: "zone", Name: "X", TierRequired: tier(0), RadiusMeters: 200, Biome: BiomeForest}, false},
+	}
+
+	for _, tt := range tests {
+		zone, err := h.validateImportedZone(tt.geom, tt.props)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: error = %v; want valid=%v", tt.name, err, tt.valid)
+			continue
+		}
+		if tt.valid && zone.DangerLevel != GetZoneTemplate(tt.props.Biome).DangerLevel {
+			t.Errorf("%s: danger level = %s; want biome default", tt.name, zone.DangerLevel)
+		}
+	}
+}
+
+func TestValidateImportedArtifact(t *testing.T) {
+	h := &Handler{}
+	zone := common.Zone{
+		TierRequired: 0,
+		RadiusMeters: 200,
+		Biome:        BiomeForest,
+		Location:     common.Location{Latitude: 48.1486, Longitude: 17.1077},
+	}
+
+	if _, err := h.validateImportedArtifact(nil, GeoJSONProperties{Kind: "artifact", Type: "mushroom_sample"}, zone); err != nil {
+		t.Errorf("forest artifact without geometry: unexpected error %v", err)
+	}
+	if _, err := h.validateImportedArtifact(nil, GeoJSONProperties{Kind: "artifact", Type: "uranium_ore"}, zone); err == nil {
+		t.Error("radioactive artifact in forest zone: expected error")
+	}
+	if _, err := h.validateImportedArtifact(nil, GeoJSONProperties{Kind: "artifact", Type: "mushroom_sample", Rarity: "legendary"}, zone); err == nil {
+		t.Error("legendary artifact in tier 0 zone: expected error")
+	}
+
+	far := &GeoJSONGeometry{Type: "Point", Coordinates: []float64{17.2, 48.1486}}
+	if _, err := h.validateImportedArtifact(far, GeoJSONProperties{Kind: "artifact", Type: "mushroom_sample"}, zone); err == nil {
+		t.Error("artifact outside zone radius: expected error")
+	}
+}
+
+func TestZoneFeatureRoundTripKeepsDynamics(t *testing.T) {
+	h := &Handler{}
+	expires := time.Now().Add(3 * time.Hour)
+	existing := common.Zone{
+		BaseModel:    common.BaseModel{ID: uuid.New()},
+		Name:         "Drifting Grove",
+		TierRequired: 0,
+		Location:     common.Location{Latitude: 48.1512, Longitude: 17.1101},
+		RadiusMeters: 120, // už zmenšená
+		ZoneType:     "event",
+		Biome:        BiomeForest,
+		ExpiresAt:    &expires,
+		Properties: common.JSONB{
+			"movement": ZoneMovement{Type: MovementTypeRandomWalk, SpeedMPS: 0.5, StartedAt: 1700000000,
+				OriginLat: 48.1486, OriginLng: 17.1077, MaxDriftMeters: 500, Seed: 99},
+			"shrink": ZoneShrink{OriginalRadius: 200, MinRatio: ShrinkMinRatio},
+		},
+	}
+	// Vlastnosti z DB sú po načítaní mapy, nie štruktúry
+	raw, _ := json.Marshal(existing.Properties)
+	existing.Properties = common.JSONB{}
+	json.Unmarshal(raw, &existing.Properties)
+
+	feature := zoneToFeature(existing)
+	var props GeoJSONProperties
+	if err := json.Unmarshal(feature.Properties, &props); err != nil {
+		t.Fatal(err)
+	}
+
+	zone, err := h.validateImportedZone(feature.Geometry, props)
+	if err != nil {
+		t.Fatalf("exported zone does not import: %v", err)
+	}
+	preserveZoneDynamics(&zone, existing)
+
+	movement, _ := zoneMovementFromProperties(zone)
+	original, _ := zoneMovementFromProperties(existing)
+	if !sameMovement(movement, original) {
+		t.Errorf("movement restarted on re-import: %+v, want %+v", movement, original)
+	}
+	if zone.RadiusMeters != 120 {
+		t.Errorf("radius = %d, want the already shrunk 120", zone.RadiusMeters)
+	}
+	if zone.Location.Latitude != existing.Location.Latitude || zone.Location.Longitude != existing.Location.Longitude {
+		t.Errorf("location = %+v, want the stored center", zone.Location)
+	}
+}
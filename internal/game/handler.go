@@ -9,7 +9,11 @@ import (
 	"strconv"
 	"time"
 
+	"geoanomaly/internal/achievements"
+	"geoanomaly/internal/collections"
 	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+	"geoanomaly/internal/quests"
 	"geoanomaly/internal/xp"
 
 	"github.com/gin-gonic/gin"
@@ -58,8 +62,13 @@ func (h *Handler) ScanArea(c *gin.Context) {
 		return
 	}
 
-	// Get existing zones in area (7km visibility)
-	existingZones := h.getExistingZonesInArea(req.Latitude, req.Longitude, AreaScanRadius)
+	// Get existing zones in area (7km visibility, ✅ NEW: + scan_range z loadoutu)
+	stats := items.Stats(h.db, user.ID)
+	scanRadius := AreaScanRadius * (1 + stats.ScanRange)
+	existingZones := h.getExistingZonesInArea(req.Latitude, req.Longitude, scanRadius)
+
+	// ✅ NEW: Opakované skenovanie jednej bunky mapy spawnuje menej zón
+	scanYield := h.areaYield(user.ID, req.Latitude, req.Longitude).ScanYield
 
 	// ====== GARANCIA ZÓN PRE NÍZKE TIERY ======
 	newZones := []common.Zone{}
@@ -73,8 +82,7 @@ func (h *Handler) ScanArea(c *gin.Context) {
 				tier0Count++
 			}
 		}
-		if tier0Count < 2 {
-			toSpawn := 2 - tier0Count
+		if toSpawn := decayedCount(2-tier0Count, scanYield, rand.Float64()); toSpawn > 0 {
 			log.Printf("✅ Guaranteeing %d tier 0 zone(s) for tier 0 player (currently %d in area)", toSpawn, tier0Count)
 			tier0Zones := h.spawnDynamicZones(req.Latitude, req.Longitude, 0, toSpawn)
 			newZones = append(newZones, tier0Zones...)
@@ -91,12 +99,12 @@ func (h *Handler) ScanArea(c *gin.Context) {
 				tier1 = true
 			}
 		}
-		if !tier0 {
+		if !tier0 && decayedCount(1, scanYield, rand.Float64()) > 0 {
 			log.Printf("✅ Guaranteeing 1 tier 0 zone for tier 1 player")
 			tier0Zones := h.spawnDynamicZones(req.Latitude, req.Longitude, 0, 1)
 			newZones = append(newZones, tier0Zones...)
 		}
-		if !tier1 {
+		if !tier1 && decayedCount(1, scanYield, rand.Float64()) > 0 {
 			log.Printf("✅ Guaranteeing 1 tier 1 zone for tier 1 player")
 			tier1Zones := h.spawnDynamicZones(req.Latitude, req.Longitude, 1, 1)
 			newZones = append(newZones, tier1Zones...)
@@ -117,7 +125,8 @@ func (h *Handler) ScanArea(c *gin.Context) {
 		}
 		if !(tier0 || tier1 || tier2) {
 			log.Printf("✅ Guaranteeing 2 zones (randomly picked from tier 0,1,2) for tier 2 player")
-			for i := 0; i < 2; i++ {
+			guaranteed := decayedCount(2, scanYield, rand.Float64())
+			for i := 0; i < guaranteed; i++ {
 				randomTier := rand.Intn(3) // 0, 1 alebo 2
 				zones := h.spawnDynamicZones(req.Latitude, req.Longitude, randomTier, 1)
 				newZones = append(newZones, zones...)
@@ -128,24 +137,28 @@ func (h *Handler) ScanArea(c *gin.Context) {
 	// Calculate how many new zones can be created (only count zones in spawn radius - 2km)
 	maxZones := h.calculateMaxZones(user.Tier)
 	currentDynamicZones := h.countDynamicZonesInArea(req.Latitude, req.Longitude, MaxSpawnRadius)
-	newZonesNeeded := maxZones - currentDynamicZones
+	newZonesNeeded := decayedCount(maxZones-currentDynamicZones, scanYield, rand.Float64())
 
 	if newZonesNeeded > 0 {
 		log.Printf("🏗️ Creating %d new zones for tier %d player", newZonesNeeded, user.Tier)
-		additionalZones := h.spawnDynamicZones(req.Latitude, req.Longitude, user.Tier, newZonesNeeded)
+		// ✅ NEW: Obtiažnosť nových zón rastie aj s levelom hráča
+		additionalZones := h.spawnDynamicZones(req.Latitude, req.Longitude, user.ProgressionTier(), newZonesNeeded)
 		newZones = append(newZones, additionalZones...)
 	}
 
+	h.recordYield(user.ID, req.Latitude, req.Longitude, YieldKindScan, len(newZones))
+	areaYield := h.areaYield(user.ID, req.Latitude, req.Longitude)
+
 	// Combine all zones
 	allZones := append(existingZones, newZones...)
 
 	// Filter zones by tier
-	visibleZones := h.filterZonesByTier(allZones, user.Tier)
+	visibleZones := h.filterZonesByTier(allZones, user.ProgressionTier())
 
 	// Build detailed zone info
 	var zoneDetails []ZoneWithDetails
 	for _, zone := range visibleZones {
-		details := h.buildZoneDetails(zone, req.Latitude, req.Longitude, user.Tier)
+		details := h.buildZoneDetails(zone, req.Latitude, req.Longitude, user.ProgressionTier())
 		zoneDetails = append(zoneDetails, details)
 	}
 
@@ -157,6 +170,9 @@ func (h *Handler) ScanArea(c *gin.Context) {
 		MaxZones:          maxZones,
 		CurrentZoneCount:  len(visibleZones),
 		PlayerTier:        user.Tier,
+		EffectiveTier:     user.ProgressionTier(),
+		AreaYield:         &areaYield,
+		ScanRadius:        scanRadius,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -273,11 +289,16 @@ func (h *Handler) spawnDynamicZones(lat, lng float64, playerTier int, count int)
 				"spawn_distance":        spawnDistance,
 				"zone_tier":             zoneTier,
 				"player_tier":           playerTier,
+				"hidden_items":          rand.Float64() < HiddenItemsZoneChance,
 			},
 		}
+		h.assignZoneDynamics(&zone)
 
 		if err := h.db.Create(&zone).Error; err == nil {
 			h.spawnItemsInZone(zone.ID, zoneTier, zone.Biome, zone.Location, zone.RadiusMeters)
+			h.spawnSeasonArtifacts(zone)
+			h.spawnHazardsInZone(zone)
+			mapTileCache.InvalidateZone(zone)
 			newZones = append(newZones, zone)
 
 			log.Printf("🏰 Zone spawned: %s (Tier: %d, Biome: %s, Distance: %.0fm, Radius: %dm, TTL: %.1fh)",
@@ -324,21 +345,22 @@ func (h *Handler) GetNearbyZones(c *gin.Context) {
 
 	// Get nearby zones
 	zones := h.getExistingZonesInArea(lat, lng, radius)
-	visibleZones := h.filterZonesByTier(zones, user.Tier)
+	visibleZones := h.filterZonesByTier(zones, user.ProgressionTier())
 
 	// Build detailed response
 	var zoneDetails []ZoneWithDetails
 	for _, zone := range visibleZones {
-		details := h.buildZoneDetails(zone, lat, lng, user.Tier)
+		details := h.buildZoneDetails(zone, lat, lng, user.ProgressionTier())
 		zoneDetails = append(zoneDetails, details)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"zones":       zoneDetails,
-		"total_zones": len(zoneDetails),
-		"scan_center": LocationPoint{Latitude: lat, Longitude: lng},
-		"radius":      radius,
-		"player_tier": user.Tier,
+		"zones":          zoneDetails,
+		"total_zones":    len(zoneDetails),
+		"scan_center":    LocationPoint{Latitude: lat, Longitude: lng},
+		"radius":         radius,
+		"player_tier":    user.Tier,
+		"effective_tier": user.ProgressionTier(),
 	})
 }
 
@@ -370,19 +392,21 @@ func (h *Handler) GetZoneDetails(c *gin.Context) {
 		return
 	}
 
-	// Tier check
-	if zone.TierRequired > user.Tier {
+	// Tier check (tier alebo level)
+	if zone.TierRequired > user.ProgressionTier() {
 		c.JSON(http.StatusForbidden, gin.H{
-			"error":         "Zone not accessible",
-			"message":       "Upgrade your tier to access this zone",
-			"required_tier": zone.TierRequired,
-			"your_tier":     user.Tier,
+			"error":          "Zone not accessible",
+			"message":        fmt.Sprintf("Upgrade your tier or reach level %d to access this zone", common.RequiredLevelForTier(zone.TierRequired)),
+			"required_tier":  zone.TierRequired,
+			"required_level": common.RequiredLevelForTier(zone.TierRequired),
+			"your_tier":      user.Tier,
+			"your_level":     user.Level,
 		})
 		return
 	}
 
 	// Build detailed response
-	details := h.buildZoneDetails(zone, 0, 0, user.Tier)
+	details := h.buildZoneDetails(zone, 0, 0, user.ProgressionTier())
 
 	// Get all items in zone (filtered by tier)
 	var artifacts []common.Artifact
@@ -390,14 +414,14 @@ func (h *Handler) GetZoneDetails(c *gin.Context) {
 	h.db.Where("zone_id = ? AND is_active = true", zone.ID).Find(&artifacts)
 	h.db.Where("zone_id = ? AND is_active = true", zone.ID).Find(&gear)
 
-	filteredArtifacts := h.filterArtifactsByTier(artifacts, user.Tier)
-	filteredGear := h.filterGearByTier(gear, user.Tier)
+	filteredArtifacts := h.filterArtifactsByTier(artifacts, user.ProgressionTier())
+	filteredGear := h.filterGearByTier(gear, user.ProgressionTier())
 
 	c.JSON(http.StatusOK, gin.H{
 		"zone":      details,
 		"artifacts": filteredArtifacts,
 		"gear":      filteredGear,
-		"can_enter": user.Tier >= zone.TierRequired,
+		"can_enter": user.ProgressionTier() >= zone.TierRequired,
 		"message":   "Zone details retrieved successfully",
 	})
 }
@@ -430,17 +454,22 @@ func (h *Handler) EnterZone(c *gin.Context) {
 		return
 	}
 
-	// Tier check
-	if zone.TierRequired > user.Tier {
+	// Tier check (tier alebo level)
+	if zone.TierRequired > user.ProgressionTier() {
 		c.JSON(http.StatusForbidden, gin.H{
-			"error":         "Insufficient tier level",
-			"message":       "Upgrade your tier to enter this zone",
-			"required_tier": zone.TierRequired,
-			"your_tier":     user.Tier,
+			"error":          "Insufficient tier level",
+			"message":        fmt.Sprintf("Upgrade your tier or reach level %d to enter this zone", common.RequiredLevelForTier(zone.TierRequired)),
+			"required_tier":  zone.TierRequired,
+			"required_level": common.RequiredLevelForTier(zone.TierRequired),
+			"your_tier":      user.Tier,
+			"your_level":     user.Level,
 		})
 		return
 	}
 
+	// ✅ NEW: Klient dostane aktuálny stred a polomer pohyblivej zóny
+	zone = liveZone(zone, time.Now())
+
 	// Update zone activity
 	h.updateZoneActivity(zone.ID)
 
@@ -471,7 +500,32 @@ func (h *Handler) EnterZone(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	// ✅ NEW: XP za prvé objavenie zóny
+	discoveryResult, err := xp.NewHandler(h.db).AwardZoneDiscoveryXP(user.ID, zone.ID, zone.TierRequired)
+	if err != nil {
+		log.Printf("❌ Failed to award zone discovery XP: %v", err)
+	} else if !discoveryResult.Duplicate {
+		h.db.Model(&user).Update("zones_discovered", gorm.Expr("zones_discovered + ?", 1))
+	}
+
+	// ✅ NEW: Achievementy (vstup do zóny, objavenie, level-up)
+	achievementHandler := achievements.NewHandler(h.db)
+	unlocked := achievementHandler.Track(user.ID, achievements.Event{
+		Type:     achievements.EventEnterZone,
+		ZoneID:   zone.ID.String(),
+		Biome:    zone.Biome,
+		ZoneTier: zone.TierRequired,
+	})
+	if discoveryResult != nil && !discoveryResult.Duplicate {
+		unlocked = append(unlocked, achievementHandler.Track(user.ID, achievements.Event{
+			Type:     achievements.EventDiscoverZone,
+			Biome:    zone.Biome,
+			ZoneTier: zone.TierRequired,
+		})...)
+		unlocked = append(unlocked, achievementHandler.TrackXPResults(user.ID, discoveryResult)...)
+	}
+
+	response := gin.H{
 		"message":              "Successfully entered zone",
 		"zone_name":            zone.Name,
 		"biome":                zone.Biome,
@@ -483,7 +537,25 @@ func (h *Handler) EnterZone(c *gin.Context) {
 		"distance_from_center": 0,
 		"ttl_status":           zone.TTLStatus(),
 		"expires_in_seconds":   int64(zone.TimeUntilExpiry().Seconds()),
-	})
+	}
+	if discoveryResult != nil && !discoveryResult.Duplicate {
+		response["first_discovery"] = true
+		addXPToResponse(response, discoveryResult, nil)
+	}
+	if len(unlocked) > 0 {
+		response["achievements_unlocked"] = unlocked
+	}
+
+	// ✅ NEW: Questy (vstup do zóny)
+	if completedQuests := quests.NewHandler(h.db).Track(user.ID, quests.Event{
+		Type:     quests.EventEnterZone,
+		Biome:    zone.Biome,
+		ZoneTier: zone.TierRequired,
+	}); len(completedQuests) > 0 {
+		response["quests_completed"] = completedQuests
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // ExitZone - jednoduchá implementácia
@@ -566,12 +638,14 @@ func (h *Handler) ScanZone(c *gin.Context) {
 		return
 	}
 
-	// Tier check
-	if zone.TierRequired > user.Tier {
+	// Tier check (tier alebo level)
+	if zone.TierRequired > user.ProgressionTier() {
 		c.JSON(http.StatusForbidden, gin.H{
-			"error":         "Insufficient tier level",
-			"required_tier": zone.TierRequired,
-			"your_tier":     user.Tier,
+			"error":          "Insufficient tier level",
+			"required_tier":  zone.TierRequired,
+			"required_level": common.RequiredLevelForTier(zone.TierRequired),
+			"your_tier":      user.Tier,
+			"your_level":     user.Level,
 		})
 		return
 	}
@@ -596,20 +670,37 @@ func (h *Handler) ScanZone(c *gin.Context) {
 	h.db.Where("zone_id = ? AND is_active = true", zoneID).Find(&artifacts)
 	h.db.Where("zone_id = ? AND is_active = true", zoneID).Find(&gear)
 
-	filteredArtifacts := h.filterArtifactsByTier(artifacts, user.Tier)
-	filteredGear := h.filterGearByTier(gear, user.Tier)
+	// ✅ NEW: Pohyblivá zóna - stred, polomer aj itemy v aktuálnej polohe, nie z posledného ticku
+	stored := zone
+	zone = liveZone(stored, time.Now())
+	artifacts = liveArtifacts(stored, zone, artifacts)
+	gear = liveGear(stored, zone, gear)
+
+	filteredArtifacts := h.filterArtifactsByTier(artifacts, user.ProgressionTier())
+	filteredGear := h.filterGearByTier(gear, user.ProgressionTier())
+
+	// ✅ NEW: Skryté artefakty - vidno len tie v dosahu odhalenia, zvyšok cez detektor
+	hiddenArtifacts := 0
+	if isHiddenItemsZone(zone) {
+		visible := revealedArtifacts(filteredArtifacts, session.LastLocationLatitude, session.LastLocationLongitude, h.revealRadius(user.ID))
+		hiddenArtifacts = len(filteredArtifacts) - len(visible)
+		filteredArtifacts = visible
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"zone_name":       zone.Name,
-		"zone":            zone,
-		"artifacts":       h.addDistanceToItems(filteredArtifacts, session.LastLocationLatitude, session.LastLocationLongitude),
-		"gear":            h.addDistanceToGear(filteredGear, session.LastLocationLatitude, session.LastLocationLongitude),
-		"total_artifacts": len(filteredArtifacts),
-		"total_gear":      len(filteredGear),
-		"scan_timestamp":  time.Now().Unix(),
-		"message":         "Zone scanned successfully",
-		"ttl_status":      zone.TTLStatus(),
-		"expires_in":      int64(zone.TimeUntilExpiry().Seconds()),
+		"zone_name":        zone.Name,
+		"zone":             zone,
+		"artifacts":        h.addDistanceToItems(filteredArtifacts, session.LastLocationLatitude, session.LastLocationLongitude),
+		"gear":             h.addDistanceToGear(filteredGear, session.LastLocationLatitude, session.LastLocationLongitude),
+		"total_artifacts":  len(filteredArtifacts),
+		"total_gear":       len(filteredGear),
+		"hidden_items":     isHiddenItemsZone(zone),
+		"hidden_artifacts": hiddenArtifacts,
+		"hazards":          h.getZoneHazards(zone.ID),
+		"scan_timestamp":   time.Now().Unix(),
+		"message":          "Zone scanned successfully",
+		"ttl_status":       zone.TTLStatus(),
+		"expires_in":       int64(zone.TimeUntilExpiry().Seconds()),
 	})
 }
 
@@ -666,13 +757,14 @@ func (h *Handler) CollectItem(c *gin.Context) {
 	}
 
 	// Check if user can collect this item
-	canCollect, reason := h.CheckUserCanCollectItem(user.Tier, req.ItemType, req.ItemID)
+	canCollect, reason := h.CheckUserCanCollectItem(user.ProgressionTier(), req.ItemType, req.ItemID)
 	if !canCollect {
 		c.JSON(http.StatusForbidden, gin.H{
-			"error":     "Cannot collect item",
-			"reason":    reason,
-			"your_tier": user.Tier,
-			"item_type": req.ItemType,
+			"error":      "Cannot collect item",
+			"reason":     reason,
+			"your_tier":  user.Tier,
+			"your_level": user.Level,
+			"item_type":  req.ItemType,
 		})
 		return
 	}
@@ -682,6 +774,15 @@ func (h *Handler) CollectItem(c *gin.Context) {
 	var itemName string
 	var biome string
 	var xpResult *xp.XPResult
+	var bonusXP []*xp.XPResult
+	var rarity string
+	var collectedType string
+	var placement *items.Placement
+	xpHandler := xp.NewHandler(h.db)
+
+	// ✅ NEW: Opakovaný zber v jednej bunke mapy znižuje XP a kvalitu lootu
+	yieldSettings := yieldConfig.get(h.db)
+	collectYield := h.areaYield(user.ID, zone.Location.Latitude, zone.Location.Longitude).CollectYield
 
 	switch req.ItemType {
 	case "artifact":
@@ -691,9 +792,23 @@ func (h *Handler) CollectItem(c *gin.Context) {
 			return
 		}
 
-		// Deactivate artifact
-		artifact.IsActive = false
-		h.db.Save(&artifact)
+		// ✅ NEW: Skrytý artefakt sa dá zobrať až po odhalení detektorom
+		if isHiddenItemsZone(zone) {
+			// Poloha v pohyblivej zóne aktuálna, nie z posledného ticku scheduleru
+			location := liveLocation(zone, liveZone(zone, time.Now()), artifact.Location)
+			distance := CalculateDistance(session.LastLocationLatitude, session.LastLocationLongitude,
+				location.Latitude, location.Longitude)
+			// ✅ NEW: collect_radius z loadoutu predĺži dosah zberu
+			if distance > h.revealRadius(user.ID)+items.Stats(h.db, user.ID).CollectRadius {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Artifact not revealed",
+					"message": "Use your detector to locate this artifact first",
+				})
+				return
+			}
+		}
+
+		lootRarity := decayRarity(yieldSettings, artifact.Rarity, collectYield)
 
 		// Add to inventory
 		inventory := common.InventoryItem{
@@ -704,28 +819,61 @@ func (h *Handler) CollectItem(c *gin.Context) {
 			Properties: common.JSONB{
 				"name":           artifact.Name,
 				"type":           artifact.Type,
-				"rarity":         artifact.Rarity,
+				"rarity":         lootRarity,
 				"biome":          artifact.Biome,
 				"collected_at":   time.Now().Unix(),
 				"collected_from": zoneID,
 				"zone_name":      zone.Name,
 				"zone_biome":     zone.Biome,
 				"danger_level":   zone.DangerLevel,
+				"zone_tier":      zone.TierRequired, // ✅ NEW: cena u obchodníka
 			},
 		}
-		h.db.Create(&inventory)
+		if lootRarity != artifact.Rarity {
+			inventory.Properties["original_rarity"] = artifact.Rarity
+		}
+		inventory.DefinitionKey = common.ItemDefinitionKey(inventory.ItemType, inventory.Properties)
 
-		// Award XP for artifact
-		xpHandler := xp.NewHandler(h.db)
+		// ✅ NEW: Kapacita inventára - stack sa navýši, plný inventár ide do overflow
 		var err error
-		xpResult, err = xpHandler.AwardArtifactXP(user.ID, artifact.Rarity, artifact.Biome, zone.TierRequired)
+		placement, err = items.Collect(h.db, inventory, zone.ID)
 		if err != nil {
-			log.Printf("❌ Failed to award XP: %v", err)
+			h.respondCollectFailed(c, user.ID, err)
+			return
+		}
+
+		// Deactivate artifact
+		artifact.IsActive = false
+		h.db.Save(&artifact)
+
+		// Award XP for artifact (artefakt stratený v hazarde už XP raz priniesol)
+		if !isDroppedArtifact(artifact) {
+			xpResult, err = xpHandler.AwardArtifactXP(xp.ArtifactCollect{
+				UserID:     user.ID,
+				ArtifactID: artifact.ID,
+				ZoneID:     zone.ID,
+				Rarity:     lootRarity,
+				Biome:      artifact.Biome,
+				ZoneTier:   zone.TierRequired,
+				AreaYield:  collectYield,
+			})
+			if err != nil {
+				log.Printf("❌ Failed to award XP: %v", err)
+			}
+		}
+
+		// ✅ NEW: Bonus za prvý artefakt daného typu
+		if typeResult, err := xpHandler.AwardArtifactTypeXP(user.ID, artifact.Type, lootRarity); err != nil {
+			log.Printf("❌ Failed to award artifact type XP: %v", err)
+		} else if !typeResult.Duplicate {
+			bonusXP = append(bonusXP, typeResult)
 		}
 
 		collectedItem = artifact
 		itemName = artifact.Name
 		biome = artifact.Biome
+		rarity = lootRarity
+		collectedType = artifact.Type
 
 		// Update user stats
 		h.db.Model(&user).Update("total_artifacts", gorm.Expr("total_artifacts + ?", 1))
@@ -737,9 +885,7 @@ func (h *Handler) CollectItem(c *gin.Context) {
 			return
 		}
 
-		// Deactivate gear
-		gear.IsActive = false
-		h.db.Save(&gear)
+		gearLevel := decayGearLevel(yieldSettings, gear.Level, collectYield)
 
 		// Add to inventory
 		inventory := common.InventoryItem{
@@ -750,16 +896,34 @@ func (h *Handler) CollectItem(c *gin.Context) {
 			Properties: common.JSONB{
 				"name":           gear.Name,
 				"type":           gear.Type,
-				"level":          gear.Level,
+				"level":          gearLevel,
 				"biome":          gear.Biome,
 				"collected_at":   time.Now().Unix(),
 				"collected_from": zoneID,
 				"zone_name":      zone.Name,
 				"zone_biome":     zone.Biome,
 				"danger_level":   zone.DangerLevel,
+				"zone_tier":      zone.TierRequired, // ✅ NEW: cena u obchodníka
 			},
 		}
-		h.db.Create(&inventory)
+		inventory.DefinitionKey = common.ItemDefinitionKey(inventory.ItemType, inventory.Properties)
+
+		var err error
+		placement, err = items.Collect(h.db, inventory, zone.ID)
+		if err != nil {
+			h.respondCollectFailed(c, user.ID, err)
+			return
+		}
+
+		// Deactivate gear
+		gear.IsActive = false
+		h.db.Save(&gear)
+
+		// ✅ NEW: XP za gear podľa levelu
+		xpResult, err = xpHandler.AwardGearXP(user.ID, gear.ID, gearLevel, gear.Biome, zone.TierRequired, collectYield)
+		if err != nil {
+			log.Printf("❌ Failed to award gear XP: %v", err)
+		}
 
 		collectedItem = gear
 		itemName = gear.Name
@@ -773,10 +937,28 @@ func (h *Handler) CollectItem(c *gin.Context) {
 		return
 	}
 
+	h.recordYield(user.ID, zone.Location.Latitude, zone.Location.Longitude, YieldKindCollect, 1)
+
+	// Zozbieraný item zmizne z density vrstvy hneď, nie až po TileCacheSeconds
+	mapTileCache.InvalidateZone(zone)
+
+	// ✅ NEW: Zber opotrebí nástroj v slote tool
+	wornGear := items.WearSlot(h.db, user.ID, common.SlotTool, items.WearPerAction)
+
 	// Check if zone should be marked for empty cleanup
 	zoneUUID, _ := uuid.Parse(zoneID)
 	go h.checkAndCleanupEmptyZone(zoneUUID)
 
+	// ✅ NEW: Posledný item v zóne = zone clear bonus
+	zoneCleared := h.isZoneCleared(zoneUUID)
+	if zoneCleared {
+		if clearResult, err := xpHandler.AwardZoneClearXP(user.ID, zoneUUID, zone.TierRequired); err != nil {
+			log.Printf("❌ Failed to award zone clear XP: %v", err)
+		} else if !clearResult.Duplicate {
+			bonusXP = append(bonusXP, clearResult)
+		}
+	}
+
 	// Enhanced response s XP systémom
 	response := gin.H{
 		"message":      "Item collected successfully",
@@ -788,25 +970,86 @@ func (h *Handler) CollectItem(c *gin.Context) {
 		"danger_level": zone.DangerLevel,
 		"collected_at": time.Now().Unix(),
 		"new_total":    user.TotalArtifacts + user.TotalGear + 1,
+		"area_yield":   h.areaYield(user.ID, zone.Location.Latitude, zone.Location.Longitude),
 	}
 
-	// Add XP data if successful (len pre artifacts)
-	if req.ItemType == "artifact" && xpResult != nil {
-		response["xp_gained"] = xpResult.XPGained
-		response["total_xp"] = xpResult.TotalXP
-		response["current_level"] = xpResult.CurrentLevel
-		response["xp_breakdown"] = xpResult.Breakdown
+	if len(wornGear) > 0 {
+		response["worn_gear"] = wornGear
+	}
+
+	// ✅ NEW: Kam sa item uložil (stack / overflow) a zostávajúca kapacita
+	response["inventory"] = placement
+	if placement.Overflow != nil {
+		response["message"] = "Inventory full - item moved to overflow stash"
+	}
+
+	// Add XP data if successful
+	if xpResult != nil {
+		addXPToResponse(response, xpResult, bonusXP)
+	}
+
+	// ✅ NEW: Achievementy (zber + prípadný level-up)
+	achievementHandler := achievements.NewHandler(h.db)
+	unlocked := achievementHandler.Track(user.ID, achievements.Event{
+		Type:     achievements.EventCollect,
+		ItemType: req.ItemType,
+		Biome:    biome,
+		Rarity:   rarity,
+		ZoneTier: zone.TierRequired,
+	})
+	unlocked = append(unlocked, achievementHandler.TrackXPResults(user.ID, append(bonusXP, xpResult)...)...)
+	if len(unlocked) > 0 {
+		response["achievements_unlocked"] = unlocked
+	}
+
+	// ✅ NEW: Questy (zber, vyčistenie zóny)
+	questHandler := quests.NewHandler(h.db)
+	completedQuests := questHandler.Track(user.ID, quests.Event{
+		Type:     quests.EventCollect,
+		ItemType: req.ItemType,
+		Biome:    zone.Biome,
+		Rarity:   rarity,
+		ZoneTier: zone.TierRequired,
+	})
+	if zoneCleared {
+		completedQuests = append(completedQuests, questHandler.Track(user.ID, quests.Event{
+			Type:     quests.EventZoneClear,
+			Biome:    zone.Biome,
+			ZoneTier: zone.TierRequired,
+		})...)
+	}
+	if len(completedQuests) > 0 {
+		response["quests_completed"] = completedQuests
+	}
 
-		if xpResult.LevelUp {
-			response["level_up"] = true
-			response["level_up_info"] = xpResult.LevelUpInfo
-			response["congratulations"] = fmt.Sprintf("🎉 Level Up! You are now level %d!", xpResult.CurrentLevel)
+	// ✅ NEW: Zbierky (nový typ artefaktu môže dokončiť sadu)
+	if req.ItemType == "artifact" {
+		if completions := collections.NewHandler(h.db).CheckCompletion(user.ID, collectedType); len(completions) > 0 {
+			response["collections_completed"] = completions
 		}
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// respondCollectFailed - item sa nezmestil ani do overflow; item ostáva v zóne
+func (h *Handler) respondCollectFailed(c *gin.Context, userID uuid.UUID, err error) {
+	code := items.CapacityCode(err)
+	if code == "" {
+		log.Printf("❌ Failed to add collected item for %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add item to inventory"})
+		return
+	}
+
+	capacity, _ := items.CapacityOf(h.db, userID)
+	c.JSON(http.StatusConflict, gin.H{
+		"error":    err.Error(),
+		"code":     code,
+		"message":  "Inventory and overflow stash are full - sell, salvage or delete items to make room",
+		"capacity": capacity,
+	})
+}
+
 // Helper function to update zone activity
 func (h *Handler) updateZoneActivity(zoneID uuid.UUID) {
 	h.db.Model(&common.Zone{}).Where("id = ?", zoneID).Update("last_activity", time.Now())
@@ -1124,11 +1367,9 @@ func (h *Handler) GetAvailableGear(c *gin.Context) {
 	})
 }
 
+// ✅ NEW: Rovnaký engine efektov ako POST /inventory/:id/use
 func (h *Handler) UseItem(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{
-		"error":  "Use item not implemented yet",
-		"status": "planned",
-	})
+	items.NewHandler(h.db).UseItem(c)
 }
 
 func (h *Handler) GetLeaderboard(c *gin.Context) {
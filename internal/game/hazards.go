@@ -0,0 +1,511 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type HazardDefinition struct {
+	Effect     string
+	BaseDamage int
+}
+
+// Hazardy odvodené z EnvironmentalEffects biómov (efekty bez záznamu sú len atmosféra, napr. fog)
+var hazardDefinitions = map[string]HazardDefinition{
+	// Forest
+	"wild_animals": {Effect: HazardEffectHealth, BaseDamage: 5},
+	// Mountain
+	"altitude_sickness": {Effect: HazardEffectHealth, BaseDamage: 5},
+	"cold_weather":      {Effect: HazardEffectDurability, BaseDamage: 3},
+	"unstable_terrain":  {Effect: HazardEffectDropItem},
+	// Industrial
+	"toxic_air":         {Effect: HazardEffectHealth, BaseDamage: 8},
+	"radiation_low":     {Effect: HazardEffectHealth, BaseDamage: 6},
+	"structural_damage": {Effect: HazardEffectDurability, BaseDamage: 5},
+	// Urban
+	"unstable_buildings": {Effect: HazardEffectHealth, BaseDamage: 6},
+	"debris":             {Effect: HazardEffectDurability, BaseDamage: 4},
+	"darkness":           {Effect: HazardEffectDropItem},
+	// Water
+	"contaminated_water": {Effect: HazardEffectHealth, BaseDamage: 6},
+	"slippery_terrain":   {Effect: HazardEffectDropItem},
+	"methane_gas":        {Effect: HazardEffectHealth, BaseDamage: 8},
+	// Radioactive
+	"radiation_high":  {Effect: HazardEffectHealth, BaseDamage: 15},
+	"decontamination": {Effect: HazardEffectDurability, BaseDamage: 8},
+	"mutation_risk":   {Effect: HazardEffectHealth, BaseDamage: 10},
+	// Chemical
+	"toxic_gas":        {Effect: HazardEffectHealth, BaseDamage: 15},
+	"chemical_burns":   {Effect: HazardEffectHealth, BaseDamage: 12},
+	"corrosive_damage": {Effect: HazardEffectDurability, BaseDamage: 10},
+}
+
+// HazardHit - výsledok vstupu hráča do hazardu
+type HazardHit struct {
+	HazardID     uuid.UUID  `json:"hazard_id"`
+	Type         string     `json:"type"`
+	Effect       string     `json:"effect"`
+	Damage       int        `json:"damage"`
+	HealthAfter  *int       `json:"health_after,omitempty"`
+	AffectedItem *uuid.UUID `json:"affected_item,omitempty"`
+	Resisted     float64    `json:"resisted,omitempty"`
+	ItemName     string     `json:"item_name,omitempty"`
+	Message      string     `json:"message"`
+
+	WornGear []items.WearResult `json:"worn_gear,omitempty"` // ✅ NEW: gear opotrebený ochranou pred hazardom
+}
+
+// In-memory cooldown, ak Redis nie je dostupný
+var hazardCooldowns = struct {
+	sync.Mutex
+	hits map[string]time.Time
+}{hits: make(map[string]time.Time)}
+
+// spawnHazardsInZone - počet a sila hazardov podľa danger levelu zóny
+func (h *Handler) spawnHazardsInZone(zone common.Zone) int {
+	effects := hazardEffects(zone.Biome)
+	if len(effects) == 0 {
+		return 0
+	}
+
+	count := getHazardCount(zone.DangerLevel)
+	minRadius, maxRadius := hazardRadiusRange(zone.RadiusMeters)
+
+	spawned := 0
+	for i := 0; i < count; i++ {
+		hazardType := effects[rand.Intn(len(effects))]
+		definition := hazardDefinitions[hazardType]
+		radius := minRadius + rand.Float64()*(maxRadius-minRadius)
+
+		hazard := common.ZoneHazard{
+			BaseModel:    common.BaseModel{ID: uuid.New()},
+			ZoneID:       zone.ID,
+			Type:         hazardType,
+			Effect:       definition.Effect,
+			Location:     h.generateRandomLocationInZone(zone.Location, zone.RadiusMeters-int(radius)),
+			RadiusMeters: int(radius),
+			Damage:       hazardDamage(hazardType, zone.DangerLevel),
+			IsActive:     true,
+			Properties: common.JSONB{
+				"spawn_time":   time.Now().Unix(),
+				"biome":        zone.Biome,
+				"danger_level": zone.DangerLevel,
+			},
+		}
+
+		if err := h.db.Create(&hazard).Error; err != nil {
+			log.Printf("❌ Failed to spawn hazard %s: %v", hazardType, err)
+			continue
+		}
+		spawned++
+	}
+
+	log.Printf("☢️ Spawned %d hazards in zone %s (%s, danger: %s)", spawned, zone.Name, zone.Biome, zone.DangerLevel)
+	return spawned
+}
+
+// hazardEffects - zapnuté EnvironmentalEffects biómu, ktoré majú definíciu hazardu
+func hazardEffects(biome string) []string {
+	var effects []string
+	for effect, enabled := range GetZoneTemplate(biome).EnvironmentalEffects {
+		if on, _ := enabled.(bool); !on {
+			continue
+		}
+		if _, dangerous := hazardDefinitions[effect]; dangerous {
+			effects = append(effects, effect)
+		}
+	}
+	sort.Strings(effects)
+	return effects
+}
+
+// hazardRadiusRange - hazard musí byť celý vo vnútri zóny
+func hazardRadiusRange(zoneRadius int) (float64, float64) {
+	maxRadius := math.Min(HazardMaxRadius, float64(zoneRadius)/4)
+	return math.Min(HazardMinRadius, maxRadius), maxRadius
+}
+
+func hazardDamage(hazardType, dangerLevel string) int {
+	return int(math.Round(float64(hazardDefinitions[hazardType].BaseDamage) * getDangerMultiplier(dangerLevel)))
+}
+
+func inHazard(hazard common.ZoneHazard, lat, lng float64) bool {
+	return CalculateDistance(lat, lng, hazard.Location.Latitude, hazard.Location.Longitude) <= float64(hazard.RadiusMeters)
+}
+
+// resistedDamage - poškodenie po odolnosti z gearu a z použitých itemov (násobia sa, nesčítavajú)
+func resistedDamage(damage int, gearResist, itemResist float64) (int, float64) {
+	resist := 1 - (1-gearResist)*(1-itemResist)
+	if resist <= 0 {
+		return damage, 0
+	}
+	return int(math.Round(float64(damage) * (1 - resist))), math.Round(resist*100) / 100
+}
+
+// getZoneHazards - aktívne hazardy s polohou prenesenou na aktuálnu geometriu pohyblivej zóny
+func (h *Handler) getZoneHazards(zoneID uuid.UUID) []common.ZoneHazard {
+	var hazards []common.ZoneHazard
+	h.db.Where("zone_id = ? AND is_active = true", zoneID).Find(&hazards)
+
+	var zone common.Zone
+	if len(hazards) == 0 || h.db.First(&zone, "id = ?", zoneID).Error != nil || !isDynamicGeometry(zone) {
+		return hazards
+	}
+	live := liveZone(zone, time.Now())
+	for i := range hazards {
+		hazards[i].Location = liveLocation(zone, live, hazards[i].Location)
+	}
+	return hazards
+}
+
+// CheckHazards - volané pri update polohy; aplikuje efekty hazardov, v ktorých hráč stojí
+func (h *Handler) CheckHazards(userID, zoneID uuid.UUID, lat, lng float64) []HazardHit {
+	var hits []HazardHit
+
+	for _, hazard := range h.getZoneHazards(zoneID) {
+		if !inHazard(hazard, lat, lng) {
+			continue
+		}
+		if !h.acquireHazardCooldown(userID, hazard.ID) {
+			continue
+		}
+
+		hit, err := h.applyHazard(userID, hazard, lat, lng)
+		if err != nil {
+			log.Printf("❌ Failed to apply hazard %s to user %s: %v", hazard.Type, userID, err)
+			continue
+		}
+		hits = append(hits, hit)
+	}
+
+	return hits
+}
+
+func (h *Handler) applyHazard(userID uuid.UUID, hazard common.ZoneHazard, lat, lng float64) (HazardHit, error) {
+	hit := HazardHit{
+		HazardID: hazard.ID,
+		Type:     hazard.Type,
+		Effect:   hazard.Effect,
+		Damage:   hazard.Damage,
+	}
+
+	// ✅ NEW: Odolnosť z vybaveného gearu a použitých itemov (napr. radiation_pills) zníži poškodenie
+	stats := items.Stats(h.db, userID)
+	hazard.Damage, hit.Resisted = resistedDamage(hazard.Damage, stats.Resist(hazard.Type), items.HazardResist(h.db, userID, hazard.Type))
+	hit.Damage = hazard.Damage
+
+	// ✅ NEW: Gear chrániaci pred hazardom alebo z biómu hazardu sa opotrebúva
+	biome, _ := hazard.Properties["biome"].(string)
+	hit.WornGear = items.WearEquipped(h.db, userID, items.WearPerHazard, func(slot items.LoadoutSlot) bool {
+		return exposedToHazard(*slot.Item, *slot.Definition, hazard.Type, biome)
+	})
+
+	switch hazard.Effect {
+	case HazardEffectDurability:
+		item, durability, err := h.damageEquippedGear(userID, hazard.Damage)
+		if err != nil {
+			return hit, err
+		}
+		if item != nil {
+			hit.AffectedItem = &item.ID
+			hit.ItemName, _ = item.Properties["name"].(string)
+			hit.Message = fmt.Sprintf("%s damaged your %s (durability %d)", GetHazardDisplayName(hazard.Type), hit.ItemName, durability)
+			return hit, nil
+		}
+		// Bez vybaveného gearu ide poškodenie priamo do zdravia
+		hit.Effect = HazardEffectHealth
+		fallthrough
+
+	case HazardEffectHealth:
+		// Brnenie z loadoutu uberá pevnú časť poškodenia zdravia
+		hazard.Damage = max(hazard.Damage-stats.Armor, 0)
+		hit.Damage = hazard.Damage
+		health, err := h.damagePlayerHealth(userID, hazard.Damage)
+		if err != nil {
+			return hit, err
+		}
+		hit.HealthAfter = &health
+		hit.Message = fmt.Sprintf("%s hurt you for %d damage", GetHazardDisplayName(hazard.Type), hazard.Damage)
+
+	case HazardEffectDropItem:
+		artifact, err := h.dropCarriedArtifact(userID, hazard.ZoneID, lat, lng)
+		if err != nil {
+			return hit, err
+		}
+		hit.Damage = 0
+		if artifact == nil {
+			hit.Message = fmt.Sprintf("%s - you almost lost something", GetHazardDisplayName(hazard.Type))
+			return hit, nil
+		}
+		hit.AffectedItem = &artifact.ID
+		hit.ItemName = artifact.Name
+		hit.Message = fmt.Sprintf("%s - you dropped %s", GetHazardDisplayName(hazard.Type), hit.ItemName)
+	}
+
+	return hit, nil
+}
+
+func (h *Handler) damagePlayerHealth(userID uuid.UUID, damage int) (int, error) {
+	if err := h.db.Model(&common.User{}).Where("id = ?", userID).
+		Update("health", gorm.Expr("GREATEST(health - ?, 0)", damage)).Error; err != nil {
+		return 0, err
+	}
+
+	var user common.User
+	if err := h.db.Select("health").First(&user, "id = ?", userID).Error; err != nil {
+		return 0, err
+	}
+	return user.Health, nil
+}
+
+// exposedToHazard - gear s odolnosťou voči hazardu alebo gear z biómu, v ktorom hazard vznikol
+func exposedToHazard(item common.InventoryItem, def common.ItemDefinition, hazardType, biome string) bool {
+	if resist, ok := def.Stats["resist"].(map[string]interface{}); ok {
+		if _, protects := resist[hazardType]; protects {
+			return true
+		}
+	}
+	gearBiome, _ := item.Properties["biome"].(string)
+	return biome != "" && gearBiome == biome
+}
+
+// Náhodný nerozbitý gear z loadoutu stratí durability
+func (h *Handler) damageEquippedGear(userID uuid.UUID, damage int) (*common.InventoryItem, int, error) {
+	var equipped []common.InventoryItem
+	for _, slot := range items.Loadout(h.db, userID) {
+		if slot.Item != nil && !items.IsBroken(*slot.Item) {
+			equipped = append(equipped, *slot.Item)
+		}
+	}
+	if len(equipped) == 0 {
+		return nil, 0, nil
+	}
+
+	item := equipped[rand.Intn(len(equipped))]
+	durability, err := items.Wear(h.db, item.ID, damage)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &item, durability, nil
+}
+
+// ApplyZoneExposure - pobyt v zóne s DangerHigh/DangerExtreme opotrebúva všetok vybavený gear.
+// Zlomok opotrebenia za krátky úsek sa zaokrúhli náhodne, aby časté update polohy nestratili opotrebenie.
+func (h *Handler) ApplyZoneExposure(userID, zoneID uuid.UUID, elapsed time.Duration) []items.WearResult {
+	if elapsed <= 0 {
+		return nil
+	}
+
+	var zone common.Zone
+	if err := h.db.Select("id", "danger_level").First(&zone, "id = ?", zoneID).Error; err != nil {
+		return nil
+	}
+
+	perMinute := exposureWearPerMinute(zone.DangerLevel)
+	if perMinute == 0 {
+		return nil
+	}
+
+	wear := elapsed.Minutes() * perMinute
+	amount := int(wear)
+	if rand.Float64() < wear-float64(amount) {
+		amount++
+	}
+	return items.WearEquipped(h.db, userID, amount, nil)
+}
+
+func exposureWearPerMinute(dangerLevel string) float64 {
+	switch dangerLevel {
+	case DangerHigh:
+		return ExposureWearHighPerMinute
+	case DangerExtreme:
+		return ExposureWearExtremePerMinute
+	default:
+		return 0
+	}
+}
+
+// Hráč stratí náhodný artefakt (nie obľúbený) - jeden kus zostane ležať v zóne
+func (h *Handler) dropCarriedArtifact(userID, zoneID uuid.UUID, lat, lng float64) (*common.Artifact, error) {
+	var items []common.InventoryItem
+	if err := h.db.Where("user_id = ? AND item_type = ? AND deleted_at IS NULL AND COALESCE(properties->>'favorite', 'false') <> 'true'",
+		userID, "artifact").Where("id NOT IN (SELECT item_id FROM item_escrows)").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	// Pohyblivá zóna: artefakt sa uloží v rámci zóny z posledného ticku, aby sa ďalej hýbal so zónou
+	var zone common.Zone
+	if err := h.db.First(&zone, "id = ?", zoneID).Error; err != nil {
+		return nil, err
+	}
+	location := common.Location{Latitude: lat, Longitude: lng}
+	if isDynamicGeometry(zone) {
+		location = storedLocation(zone, liveZone(zone, time.Now()), location)
+	}
+
+	item := items[rand.Intn(len(items))]
+	artifact, remaining := droppedArtifact(item, zoneID, location.Latitude, location.Longitude)
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		// Zo stacku vypadne len jeden kus ako nový artefakt, zvyšok ostáva v inventári
+		if remaining > 0 {
+			if err := tx.Model(&item).Update("quantity", remaining).Error; err != nil {
+				return err
+			}
+			return tx.Create(&artifact).Error
+		}
+
+		if err := tx.Model(&item).Update("deleted_at", time.Now()).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&common.Artifact{}).Where("id = ?", artifact.ID).Updates(map[string]interface{}{
+			"zone_id":            zoneID,
+			"location_latitude":  artifact.Location.Latitude,
+			"location_longitude": artifact.Location.Longitude,
+			"is_active":          true,
+			"properties":         gorm.Expr(`COALESCE(properties, '{}'::jsonb) || '{"dropped": true}'::jsonb`),
+		})
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+		// Item bez pôvodného záznamu (crafting, rozobratie) - artefakt sa vytvorí
+		return tx.Create(&artifact).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &artifact, nil
+}
+
+// droppedArtifact - artefakt, ktorý po strate ostane v zóne, a zvyšok stacku v inventári.
+// Posledný kus vráti pôvodný záznam artefaktu (item.ItemID), zo stacku vznikne nový.
+func droppedArtifact(item common.InventoryItem, zoneID uuid.UUID, lat, lng float64) (common.Artifact, int) {
+	artifact := common.Artifact{
+		ZoneID:     zoneID,
+		Name:       "Unknown Artifact",
+		Type:       common.ItemTypeMisc,
+		Rarity:     "common",
+		Biome:      "forest",
+		Location:   common.Location{Latitude: lat, Longitude: lng},
+		Properties: common.JSONB{"dropped": true},
+		IsActive:   true,
+	}
+	for key, target := range map[string]*string{"name": &artifact.Name, "type": &artifact.Type, "rarity": &artifact.Rarity, "biome": &artifact.Biome} {
+		if value, ok := item.Properties[key].(string); ok && value != "" {
+			*target = value
+		}
+	}
+
+	remaining := max(1, item.Quantity) - 1
+	if remaining == 0 {
+		artifact.ID = item.ItemID
+	}
+	return artifact, remaining
+}
+
+// isDroppedArtifact - artefakt vypadnutý z inventára v hazarde; opätovný zber nedáva XP
+func isDroppedArtifact(artifact common.Artifact) bool {
+	dropped, _ := artifact.Properties["dropped"].(bool)
+	return dropped
+}
+
+func (h *Handler) acquireHazardCooldown(userID, hazardID uuid.UUID) bool {
+	key := fmt.Sprintf("hazard:%s:%s", userID, hazardID)
+	cooldown := HazardHitCooldownSecs * time.Second
+
+	if h.redis != nil {
+		acquired, err := h.redis.SetNX(context.Background(), key, 1, cooldown).Result()
+		if err == nil {
+			return acquired
+		}
+	}
+
+	hazardCooldowns.Lock()
+	defer hazardCooldowns.Unlock()
+
+	if len(hazardCooldowns.hits) > 10000 {
+		for k, hitAt := range hazardCooldowns.hits {
+			if time.Since(hitAt) > cooldown {
+				delete(hazardCooldowns.hits, k)
+			}
+		}
+	}
+
+	if hitAt, exists := hazardCooldowns.hits[key]; exists && time.Since(hitAt) < cooldown {
+		return false
+	}
+	hazardCooldowns.hits[key] = time.Now()
+	return true
+}
+
+func getHazardCount(dangerLevel string) int {
+	switch dangerLevel {
+	case DangerLow:
+		return 1
+	case DangerMedium:
+		return 2
+	case DangerHigh:
+		return 3
+	case DangerExtreme:
+		return 4
+	default:
+		return 1
+	}
+}
+
+func getDangerMultiplier(dangerLevel string) float64 {
+	switch dangerLevel {
+	case DangerMedium:
+		return 1.5
+	case DangerHigh:
+		return 2.0
+	case DangerExtreme:
+		return 3.0
+	default:
+		return 1.0
+	}
+}
+
+func GetHazardDisplayName(hazardType string) string {
+	displayNames := map[string]string{
+		"wild_animals":       "Wild Animals",
+		"altitude_sickness":  "Altitude Sickness",
+		"cold_weather":       "Freezing Cold",
+		"unstable_terrain":   "Unstable Terrain",
+		"toxic_air":          "Toxic Air",
+		"radiation_low":      "Low Radiation",
+		"structural_damage":  "Collapsing Structure",
+		"unstable_buildings": "Unstable Building",
+		"debris":             "Falling Debris",
+		"darkness":           "Darkness",
+		"contaminated_water": "Contaminated Water",
+		"slippery_terrain":   "Slippery Terrain",
+		"methane_gas":        "Methane Pocket",
+		"radiation_high":     "Radiation Hotspot",
+		"decontamination":    "Decontamination Spray",
+		"mutation_risk":      "Mutagenic Field",
+		"toxic_gas":          "Toxic Gas Cloud",
+		"chemical_burns":     "Chemical Burns",
+		"corrosive_damage":   "Corrosive Mist",
+	}
+
+	if name, exists := displayNames[hazardType]; exists {
+		return name
+	}
+	return hazardType
+}
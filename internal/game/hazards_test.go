@@ -0,0 +1,120 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+)
+
+func TestHazardSpawn(t *testing.T) {
+	effects := hazardEffects(BiomeRadioactive)
+	if want := []string{"decontamination", "mutation_risk", "radiation_high"}; !reflect.DeepEqual(effects, want) {
+		t.Errorf("radioactive hazards = %v, want %v", effects, want)
+	}
+
+	if minRadius, maxRadius := hazardRadiusRange(500); minRadius != HazardMinRadius || maxRadius != HazardMaxRadius {
+		t.Errorf("large zone radius range = %v-%v", minRadius, maxRadius)
+	}
+	// Malá zóna: hazard najviac štvrtina polomeru, aby sa zmestil celý do zóny
+	if minRadius, maxRadius := hazardRadiusRange(20); minRadius != 5 || maxRadius != 5 {
+		t.Errorf("small zone radius range = %v-%v, want 5-5", minRadius, maxRadius)
+	}
+
+	if got := hazardDamage("radiation_high", DangerExtreme); got != 45 {
+		t.Errorf("extreme radiation damage = %d, want 45", got)
+	}
+	if got := hazardDamage("radiation_high", DangerLow); got != 15 {
+		t.Errorf("low danger radiation damage = %d, want 15", got)
+	}
+	if got := hazardDamage("slippery_terrain", DangerHigh); got != 0 {
+		t.Errorf("drop hazard damage = %d, want 0", got)
+	}
+}
+
+func TestHazardHit(t *testing.T) {
+	hazard := common.ZoneHazard{
+		BaseModel:    common.BaseModel{ID: uuid.New()},
+		Location:     common.Location{Latitude: 48.0, Longitude: 17.0},
+		RadiusMeters: 20,
+	}
+	if !inHazard(hazard, 48.0001, 17.0) {
+		t.Error("player ~11m from the center should be inside a 20m hazard")
+	}
+	if inHazard(hazard, 48.001, 17.0) {
+		t.Error("player ~111m away should be outside the hazard")
+	}
+
+	h := &Handler{}
+	userID := uuid.New()
+	if !h.acquireHazardCooldown(userID, hazard.ID) {
+		t.Fatal("first hit should pass")
+	}
+	if h.acquireHazardCooldown(userID, hazard.ID) {
+		t.Error("the same hazard must not hit again within the cooldown")
+	}
+	if !h.acquireHazardCooldown(userID, uuid.New()) || !h.acquireHazardCooldown(uuid.New(), hazard.ID) {
+		t.Error("cooldown is per player and hazard")
+	}
+}
+
+func TestHazardDamage(t *testing.T) {
+	if damage, resisted := resistedDamage(20, 0, 0); damage != 20 || resisted != 0 {
+		t.Errorf("no resist = %d (%v), want 20", damage, resisted)
+	}
+	// Odolnosti sa násobia: 50 % z gearu a 50 % z pilulky = 75 %
+	if damage, resisted := resistedDamage(20, 0.5, 0.5); damage != 5 || resisted != 0.75 {
+		t.Errorf("stacked resist = %d (%v), want 5 (0.75)", damage, resisted)
+	}
+	if damage, _ := resistedDamage(20, 1, 0); damage != 0 {
+		t.Errorf("full resist = %d, want 0", damage)
+	}
+
+	mask := common.ItemDefinition{Stats: common.JSONB{"resist": map[string]interface{}{"toxic_gas": 0.5}}}
+	if !exposedToHazard(common.InventoryItem{}, mask, "toxic_gas", BiomeChemical) {
+		t.Error("gear protecting against the hazard should wear")
+	}
+	forestGear := common.InventoryItem{Properties: common.JSONB{"biome": BiomeForest}}
+	if exposedToHazard(forestGear, common.ItemDefinition{}, "toxic_gas", BiomeChemical) {
+		t.Error("unrelated gear should not wear")
+	}
+	if exposureWearPerMinute(DangerLow) != 0 || exposureWearPerMinute(DangerExtreme) <= exposureWearPerMinute(DangerHigh) {
+		t.Error("exposure wear should apply only to high and extreme zones, extreme faster")
+	}
+}
+
+func TestDroppedArtifactFromStack(t *testing.T) {
+	zoneID := uuid.New()
+	stack := common.InventoryItem{
+		ItemID:   uuid.New(),
+		Quantity: 12,
+		Properties: common.JSONB{
+			"name": "Rare Mineral Ore", "type": "mineral_ore", "rarity": "rare", "biome": "mountain",
+		},
+	}
+
+	artifact, remaining := droppedArtifact(stack, zoneID, 48.1, 17.1)
+	if remaining != 11 {
+		t.Errorf("remaining in stack = %d, want 11", remaining)
+	}
+	if artifact.ID == stack.ItemID {
+		t.Error("a drop from a stack must create a new artifact, not move the stack's original record")
+	}
+	if artifact.ZoneID != zoneID || !artifact.IsActive || artifact.Rarity != "rare" || artifact.Type != "mineral_ore" {
+		t.Errorf("dropped artifact = %+v", artifact)
+	}
+	if !isDroppedArtifact(artifact) {
+		t.Error("a piece dropped from a stack must be marked so collecting it again gives no XP")
+	}
+	if isDroppedArtifact(common.Artifact{Properties: common.JSONB{"spawn_time": 1}}) {
+		t.Error("a spawned artifact must not count as dropped")
+	}
+
+	stack.Quantity = 1
+	artifact, remaining = droppedArtifact(stack, zoneID, 48.1, 17.1)
+	if remaining != 0 || artifact.ID != stack.ItemID {
+		t.Errorf("last piece: remaining %d, id %s; want 0 and the original artifact %s", remaining, artifact.ID, stack.ItemID)
+	}
+}
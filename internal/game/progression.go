@@ -0,0 +1,102 @@
+package game
+
+import (
+	"net/http"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Požadovaný tier pre biom (level ekvivalent cez common.LevelTierThresholds)
+var biomeTierRequirements = map[string]int{
+	BiomeForest:      0,
+	BiomeMountain:    1,
+	BiomeUrban:       1,
+	BiomeWater:       1,
+	BiomeIndustrial:  2,
+	BiomeRadioactive: 3,
+	BiomeChemical:    4,
+	//BiomeNight:       2, // ← pridané pre nočný biome
+}
+
+// minTierFor - najnižší tier, pri ktorom check prejde (-1 ak žiadny)
+func minTierFor(check func(tier int) bool) int {
+	for tier := 0; tier < len(common.LevelTierThresholds); tier++ {
+		if check(tier) {
+			return tier
+		}
+	}
+	return -1
+}
+
+// GetProgression - GET /user/progression
+// Čo má hráč odomknuté tierom alebo levelom a čo odomkne ďalší level
+func (h *Handler) GetProgression(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var user common.User
+	if err := h.db.First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	effectiveTier := user.ProgressionTier()
+	unlockedBy := "tier"
+	if common.LevelTier(user.Level) > user.Tier {
+		unlockedBy = "level"
+	}
+
+	unlock := func(requiredTier int) gin.H {
+		return gin.H{
+			"required_tier":  requiredTier,
+			"required_level": common.RequiredLevelForTier(requiredTier),
+			"unlocked":       requiredTier >= 0 && effectiveTier >= requiredTier,
+		}
+	}
+
+	biomes := []gin.H{}
+	for _, biome := range []string{BiomeForest, BiomeMountain, BiomeUrban, BiomeWater, BiomeIndustrial, BiomeRadioactive, BiomeChemical} {
+		entry := unlock(biomeTierRequirements[biome])
+		entry["biome"] = biome
+		biomes = append(biomes, entry)
+	}
+
+	rarities := []gin.H{}
+	for _, rarity := range rarityOrder {
+		required := minTierFor(func(tier int) bool {
+			return h.canCollectArtifact(common.Artifact{Rarity: rarity}, tier)
+		})
+		entry := unlock(required)
+		entry["rarity"] = rarity
+		rarities = append(rarities, entry)
+	}
+
+	response := gin.H{
+		"tier":                  user.Tier,
+		"level":                 user.Level,
+		"effective_tier":        effectiveTier,
+		"unlocked_by":           unlockedBy,
+		"biomes":                biomes,
+		"rarities":              rarities,
+		"max_gear_level":        h.getMaxGearLevelForTier(effectiveTier),
+		"max_visible_zone_tier": getMaxVisibleZoneTier(effectiveTier),
+		"next_unlock":           nil,
+	}
+
+	if next := effectiveTier + 1; next < len(common.LevelTierThresholds) {
+		nextLevel := common.RequiredLevelForTier(next)
+		response["next_unlock"] = gin.H{
+			"effective_tier": next,
+			"level":          nextLevel,
+			"levels_to_go":   nextLevel - user.Level,
+			"max_gear_level": h.getMaxGearLevelForTier(next),
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,22 @@
+package game
+
+import (
+	"testing"
+
+	"geoanomaly/internal/common"
+)
+
+func TestFreePlayerReachesEndgameBiomes(t *testing.T) {
+	h := &Handler{}
+	free := common.User{Tier: 0, Level: common.RequiredLevelForTier(biomeTierRequirements[BiomeChemical])}.ProgressionTier()
+
+	if !h.canAccessBiome(BiomeChemical, free) || !h.canAccessBiome(BiomeRadioactive, free) {
+		t.Error("free player at the required level must access radioactive and chemical biomes")
+	}
+	if !h.canCollectArtifact(common.Artifact{Rarity: "legendary"}, free) {
+		t.Error("free player at the required level must be able to collect legendary artifacts")
+	}
+	if got := h.getMaxGearLevelForTier(free); got != 10 {
+		t.Errorf("expected max gear level 10, got %d", got)
+	}
+}
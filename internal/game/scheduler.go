@@ -5,6 +5,12 @@ import (
 	"log"
 	"time"
 
+	"geoanomaly/internal/items"
+	"geoanomaly/internal/market"
+	"geoanomaly/internal/seasons"
+	"geoanomaly/internal/trading"
+	"geoanomaly/internal/wallet"
+
 	"gorm.io/gorm"
 )
 
@@ -12,9 +18,17 @@ type Scheduler struct {
 	db             *gorm.DB
 	cleanupService *CleanupService
 	ticker         *time.Ticker
-	ctx            context.Context
-	cancel         context.CancelFunc
-	isRunning      bool
+
+	// ✅ NEW: Moving & shrinking zones
+	movementService *ZoneMovementService
+	movementTicker  *time.Ticker
+
+	// ✅ NEW: Kontrola invariantov ledgeru peňaženiek
+	reconcileTicker *time.Ticker
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	isRunning bool
 }
 
 type SchedulerStats struct {
@@ -33,11 +47,12 @@ func NewScheduler(db *gorm.DB) *Scheduler {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Scheduler{
-		db:             db,
-		cleanupService: cleanupService,
-		ctx:            ctx,
-		cancel:         cancel,
-		isRunning:      false,
+		db:              db,
+		cleanupService:  cleanupService,
+		movementService: NewZoneMovementService(db),
+		ctx:             ctx,
+		cancel:          cancel,
+		isRunning:       false,
 	}
 }
 
@@ -50,6 +65,8 @@ func (s *Scheduler) Start() {
 
 	s.isRunning = true
 	s.ticker = time.NewTicker(5 * time.Minute) // Every 5 minutes
+	s.movementTicker = time.NewTicker(MovementTickSeconds * time.Second)
+	s.reconcileTicker = time.NewTicker(wallet.ReconcileInterval)
 
 	log.Printf("🕐 Zone cleanup scheduler started (5min interval)")
 
@@ -75,6 +92,12 @@ func (s *Scheduler) Stop() {
 	if s.ticker != nil {
 		s.ticker.Stop()
 	}
+	if s.movementTicker != nil {
+		s.movementTicker.Stop()
+	}
+	if s.reconcileTicker != nil {
+		s.reconcileTicker.Stop()
+	}
 	s.isRunning = false
 
 	log.Printf("🛑 Zone cleanup scheduler stopped")
@@ -87,6 +110,12 @@ func (s *Scheduler) run() {
 		if s.ticker != nil {
 			s.ticker.Stop()
 		}
+		if s.movementTicker != nil {
+			s.movementTicker.Stop()
+		}
+		if s.reconcileTicker != nil {
+			s.reconcileTicker.Stop()
+		}
 	}()
 
 	for {
@@ -104,6 +133,37 @@ func (s *Scheduler) run() {
 
 			// Check for zones about to expire (30min warning)
 			s.checkExpiringZones()
+
+			// ✅ NEW: Snapshot poradia skončených sezón
+			seasons.FinalizeEndedSeasons(s.db)
+
+			// ✅ NEW: Výnos v bunkách mimo dlhého okna už nie je potrebný
+			if removed := CleanupCellYields(s.db); removed > 0 {
+				log.Printf("🌾 Removed %d expired cell yield records", removed)
+			}
+
+			// ✅ NEW: Expirované efekty z použitých itemov
+			if removed := items.CleanupExpiredBuffs(s.db); removed > 0 {
+				log.Printf("🧪 Removed %d expired player buffs", removed)
+			}
+			if expired := trading.ExpireOffers(s.db); expired > 0 {
+				log.Printf("🤝 Expired %d trade offers", expired)
+			}
+			if sold, expired := market.SettleExpired(s.db); sold+expired > 0 {
+				log.Printf("🏪 Settled %d auctions, expired %d market listings", sold, expired)
+			}
+			if expired := items.ExpireOverflow(s.db); expired > 0 {
+				log.Printf("🎒 Expired %d unclaimed overflow items", expired)
+			}
+
+		case <-s.reconcileTicker.C:
+			wallet.RunReconcile(s.db)
+
+		case <-s.movementTicker.C:
+			// Posun driftujúcich a zmenšovanie expirujúcich zón
+			if moved := s.movementService.UpdateMovingZones(); moved > 0 {
+				log.Printf("🌀 Updated geometry of %d moving/shrinking zones", moved)
+			}
 		}
 	}
 }
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"log"
+	"math/rand"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/seasons"
+
+	"github.com/google/uuid"
+)
+
+// ✅ NEW: Sezónne exkluzívne artefakty - pri spawne zóny sa podľa šance pridajú do lootu
+// Season.ExclusiveArtifacts: {"<type>": {"name": "...", "rarity": "epic", "chance": 0.1, "biomes": ["forest"]}}
+func (h *Handler) spawnSeasonArtifacts(zone common.Zone) int {
+	season := seasons.ActiveSeason(h.db)
+	if season == nil || len(season.ExclusiveArtifacts) == 0 {
+		return 0
+	}
+
+	spawned := 0
+	for artifactType, raw := range season.ExclusiveArtifacts {
+		config, ok := raw.(map[string]interface{})
+		if !ok || !seasonArtifactAllowedIn(config, zone.Biome) {
+			continue
+		}
+
+		chance := SeasonArtifactDefaultChance
+		if c, ok := config["chance"].(float64); ok {
+			chance = c
+		}
+		if rand.Float64() >= chance {
+			continue
+		}
+
+		name, _ := config["name"].(string)
+		if name == "" {
+			name = GetArtifactDisplayName(artifactType)
+		}
+		rarity, _ := config["rarity"].(string)
+		if rarity == "" {
+			rarity = "epic"
+		}
+
+		lat, lng := h.generateRandomPosition(zone.Location.Latitude, zone.Location.Longitude, float64(zone.RadiusMeters))
+		artifact := common.Artifact{
+			BaseModel: common.BaseModel{ID: uuid.New()},
+			ZoneID:    zone.ID,
+			Name:      name,
+			Type:      artifactType,
+			Rarity:    rarity,
+			Biome:     zone.Biome,
+			Location: common.Location{
+				Latitude:  lat,
+				Longitude: lng,
+				Timestamp: time.Now(),
+			},
+			Properties: common.JSONB{
+				"spawn_time":       time.Now().Unix(),
+				"spawner":          "season",
+				"zone_tier":        zone.TierRequired,
+				"biome":            zone.Biome,
+				"spawn_reason":     "season_exclusive",
+				"season_id":        season.ID,
+				"season_name":      season.Name,
+				"season_exclusive": true,
+			},
+			IsActive: true,
+		}
+
+		if err := h.db.Create(&artifact).Error; err != nil {
+			log.Printf("❌ [ERROR] Failed to spawn season artifact %s: %v", artifactType, err)
+			continue
+		}
+		spawned++
+		log.Printf("🗓️ [SEASON] Spawned %s exclusive: %s", season.Name, name)
+	}
+
+	return spawned
+}
+
+func seasonArtifactAllowedIn(config map[string]interface{}, biome string) bool {
+	biomes, ok := config["biomes"].([]interface{})
+	if !ok || len(biomes) == 0 {
+		return true
+	}
+	for _, b := range biomes {
+		if name, _ := b.(string); name == biome {
+			return true
+		}
+	}
+	return false
+}
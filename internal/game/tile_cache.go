@@ -0,0 +1,142 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	redis_client "github.com/redis/go-redis/v9"
+)
+
+// TileCache drží vyrenderované MVT dlaždice (zones + density vrstvy).
+// Ak je Redis dostupný, cache je zdieľaná medzi inštanciami, inak len in-memory.
+type TileCache struct {
+	mu      sync.RWMutex
+	entries map[string]cachedTile
+	redis   *redis_client.Client
+}
+
+type cachedTile struct {
+	data     []byte
+	cachedAt time.Time
+}
+
+// Zdieľaná cache pre Handler aj CleanupService (invalidácia pri spawne/expirácii)
+var mapTileCache = NewTileCache(nil)
+
+func NewTileCache(redisClient *redis_client.Client) *TileCache {
+	return &TileCache{
+		entries: make(map[string]cachedTile),
+		redis:   redisClient,
+	}
+}
+
+// SetTileCacheRedis zapne zdieľanú Redis cache pre mapové dlaždice
+func SetTileCacheRedis(redisClient *redis_client.Client) {
+	mapTileCache.mu.Lock()
+	defer mapTileCache.mu.Unlock()
+	mapTileCache.redis = redisClient
+}
+
+func tileCacheKey(maxVisibleTier, z, x, y int) string {
+	return fmt.Sprintf("mvt:t%d:%d/%d/%d", maxVisibleTier, z, x, y)
+}
+
+func (tc *TileCache) Get(key string) ([]byte, bool) {
+	tc.mu.RLock()
+	redisClient := tc.redis
+	cached, ok := tc.entries[key]
+	tc.mu.RUnlock()
+
+	if redisClient != nil {
+		data, err := redisClient.Get(context.Background(), key).Bytes()
+		if err != nil {
+			return nil, false
+		}
+		return data, true
+	}
+
+	if !ok || time.Since(cached.cachedAt) > TileCacheSeconds*time.Second {
+		return nil, false
+	}
+	return cached.data, true
+}
+
+func (tc *TileCache) Set(key string, data []byte) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.redis != nil {
+		if err := tc.redis.Set(context.Background(), key, data, TileCacheSeconds*time.Second).Err(); err != nil {
+			log.Printf("⚠️ Failed to cache tile %s: %v", key, err)
+		}
+		return
+	}
+
+	// Pri raste cache vyhoď expirované dlaždice
+	if len(tc.entries) > 5000 {
+		tc.cleanupLocked()
+	}
+	tc.entries[key] = cachedTile{data: data, cachedAt: time.Now()}
+}
+
+// InvalidateZone zmaže všetky dlaždice, ktoré zóna prekrýva (všetky zoomy a tier viditeľnosti)
+func (tc *TileCache) InvalidateZone(zone common.Zone) {
+	minLng, minLat, maxLng, maxLat := expandBounds(
+		zone.Location.Longitude, zone.Location.Latitude,
+		zone.Location.Longitude, zone.Location.Latitude,
+		float64(zone.RadiusMeters),
+	)
+
+	var keys []string
+	for z := MinTileZoom; z <= MaxTileZoom; z++ {
+		minX, minY := lngLatToTile(minLng, maxLat, z)
+		maxX, maxY := lngLatToTile(maxLng, minLat, z)
+		for x := minX; x <= maxX; x++ {
+			for y := minY; y <= maxY; y++ {
+				for _, tier := range tileVisibilityTiers() {
+					keys = append(keys, tileCacheKey(tier, z, x, y))
+				}
+			}
+		}
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.redis != nil {
+		if err := tc.redis.Del(context.Background(), keys...).Err(); err != nil {
+			log.Printf("⚠️ Failed to invalidate tiles for zone %s: %v", zone.ID, err)
+		}
+		return
+	}
+	for _, key := range keys {
+		delete(tc.entries, key)
+	}
+}
+
+func (tc *TileCache) cleanupLocked() {
+	for key, cached := range tc.entries {
+		if time.Since(cached.cachedAt) > TileCacheSeconds*time.Second {
+			delete(tc.entries, key)
+		}
+	}
+}
+
+// Všetky rozdielne hodnoty getMaxVisibleZoneTier (tier 0-5)
+func tileVisibilityTiers() []int {
+	seen := make(map[int]bool)
+	var tiers []int
+	for userTier := 0; userTier <= 5; userTier++ {
+		tier := getMaxVisibleZoneTier(userTier)
+		if !seen[tier] {
+			seen[tier] = true
+			tiers = append(tiers, tier)
+		}
+	}
+	return tiers
+}
@@ -0,0 +1,283 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const mvtContentType = "application/vnd.mapbox-vector-tile"
+
+// GetMapTile - GET /game/tiles/:z/:x/:y.mvt
+// Vracia Mapbox Vector Tile s vrstvami "zones", "density" a (ak je hráč v zóne) "items"
+func (h *Handler) GetMapTile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	z, x, y, err := parseTileCoords(c.Param("z"), c.Param("x"), c.Param("y"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user common.User
+	if err := h.db.First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	maxVisibleTier := getMaxVisibleZoneTier(user.ProgressionTier())
+
+	// Zdieľané vrstvy (zones + density) sú cachované podľa tier viditeľnosti
+	cacheKey := tileCacheKey(maxVisibleTier, z, x, y)
+	tile, cached := mapTileCache.Get(cacheKey)
+	if !cached {
+		tile, err = h.renderSharedTile(z, x, y, maxVisibleTier)
+		if err != nil {
+			log.Printf("❌ Failed to render tile %d/%d/%d: %v", z, x, y, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render tile"})
+			return
+		}
+		mapTileCache.Set(cacheKey, tile)
+	}
+
+	// Items vrstva je per-hráč - len keď je hráč v zóne
+	var session common.PlayerSession
+	if err := h.db.Where("user_id = ? AND current_zone IS NOT NULL", userID).First(&session).Error; err == nil {
+		itemsLayer, err := h.renderItemsLayer(z, x, y, session, user.ProgressionTier())
+		if err != nil {
+			log.Printf("⚠️ Failed to render items layer for tile %d/%d/%d: %v", z, x, y, err)
+		} else {
+			// MVT dlaždica je zoznam vrstiev, takže vrstvy možno jednoducho spojiť
+			tile = append(append([]byte{}, tile...), itemsLayer...)
+		}
+	}
+
+	c.Header("Cache-Control", "private, max-age=30")
+	if cached {
+		c.Header("X-Cache", "HIT")
+	} else {
+		c.Header("X-Cache", "MISS")
+	}
+
+	if len(tile) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.Data(http.StatusOK, mvtContentType, tile)
+}
+
+// Zones + density vrstvy v jednom dotaze (nahrádza COUNT dotazy z buildZoneDetails)
+func (h *Handler) renderSharedTile(z, x, y, maxVisibleTier int) ([]byte, error) {
+	minLng, minLat, maxLng, maxLat := tileBounds(z, x, y)
+	// Zóny s centrom mimo dlaždice ju môžu stále prekrývať svojím polomerom
+	zMinLng, zMinLat, zMaxLng, zMaxLat := expandBounds(minLng, minLat, maxLng, maxLat, MaxZoneRadius)
+
+	query := `
+		WITH bounds AS (
+			SELECT ST_TileEnvelope(@z, @x, @y) AS geom
+		),
+		zone_rows AS (
+			SELECT
+				zn.id::text AS id, zn.name, zn.tier_required, zn.biome, zn.danger_level,
+				zn.zone_type, zn.radius_meters,
+				zn.location_latitude::float8 AS center_lat, zn.location_longitude::float8 AS center_lng,
+				COALESCE(EXTRACT(EPOCH FROM zn.expires_at)::bigint, 0) AS expires_at,
+				(SELECT COUNT(*) FROM artifacts a WHERE a.zone_id = zn.id AND a.is_active = true) AS active_artifacts,
+				(SELECT COUNT(*) FROM gear g WHERE g.zone_id = zn.id AND g.is_active = true) AS active_gear,
+				ST_AsMVTGeom(
+					ST_Transform(ST_Buffer(ST_SetSRID(ST_Point(zn.location_longitude, zn.location_latitude), 4326)::geography, zn.radius_meters)::geometry, 3857),
+					bounds.geom, @extent, @buffer, true
+				) AS geom
+			FROM zones zn, bounds
+			WHERE zn.is_active = true AND zn.deleted_at IS NULL
+				AND zn.tier_required <= @max_tier
+				AND (zn.expires_at IS NULL OR zn.expires_at > NOW())
+				AND ST_Point(zn.location_longitude, zn.location_latitude) && ST_MakeEnvelope(@z_min_lng, @z_min_lat, @z_max_lng, @z_max_lat)
+		),
+		item_points AS (
+			SELECT ST_Transform(ST_SetSRID(ST_Point(a.location_longitude, a.location_latitude), 4326), 3857) AS geom
+			FROM artifacts a JOIN zones zn ON zn.id = a.zone_id
+			WHERE a.is_active = true AND zn.is_active = true AND zn.deleted_at IS NULL
+				AND zn.tier_required <= @max_tier
+				AND (zn.expires_at IS NULL OR zn.expires_at > NOW())
+				AND COALESCE((zn.properties->>'hidden_items')::boolean, false) = false
+				AND ST_Point(a.location_longitude, a.location_latitude) && ST_MakeEnvelope(@min_lng, @min_lat, @max_lng, @max_lat)
+			UNION ALL
+			SELECT ST_Transform(ST_SetSRID(ST_Point(g.location_longitude, g.location_latitude), 4326), 3857) AS geom
+			FROM gear g JOIN zones zn ON zn.id = g.zone_id
+			WHERE g.is_active = true AND zn.is_active = true AND zn.deleted_at IS NULL
+				AND zn.tier_required <= @max_tier
+				AND (zn.expires_at IS NULL OR zn.expires_at > NOW())
+				AND ST_Point(g.location_longitude, g.location_latitude) && ST_MakeEnvelope(@min_lng, @min_lat, @max_lng, @max_lat)
+		),
+		density_rows AS (
+			SELECT
+				COUNT(*) AS item_count,
+				ST_AsMVTGeom(ST_Centroid(ST_Collect(item_points.geom)), bounds.geom, @extent, @buffer, true) AS geom
+			FROM item_points, bounds
+			GROUP BY ST_SnapToGrid(item_points.geom, @cell_size), bounds.geom
+		)
+		SELECT
+			COALESCE((SELECT ST_AsMVT(t, 'zones', @extent, 'geom') FROM (SELECT * FROM zone_rows WHERE geom IS NOT NULL) t), ''::bytea)
+			|| COALESCE((SELECT ST_AsMVT(t, 'density', @extent, 'geom') FROM (SELECT * FROM density_rows WHERE geom IS NOT NULL) t), ''::bytea)
+	`
+
+	var tile []byte
+	err := h.db.Raw(query, map[string]interface{}{
+		"z": z, "x": x, "y": y,
+		"extent":    TileExtent,
+		"buffer":    TileBuffer,
+		"max_tier":  maxVisibleTier,
+		"cell_size": tileWidthMeters(z) / DensityGridCells,
+		"min_lng":   minLng, "min_lat": minLat, "max_lng": maxLng, "max_lat": maxLat,
+		"z_min_lng": zMinLng, "z_min_lat": zMinLat, "z_max_lng": zMaxLng, "z_max_lat": zMaxLat,
+	}).Row().Scan(&tile)
+
+	return tile, err
+}
+
+// Items vrstva pre zónu, v ktorej hráč práve je (rovnaké tier filtre ako ScanZone)
+func (h *Handler) renderItemsLayer(z, x, y int, session common.PlayerSession, userTier int) ([]byte, error) {
+	zoneID := *session.CurrentZone
+
+	var zone common.Zone
+	if err := h.db.First(&zone, "id = ?", zoneID).Error; err != nil {
+		return nil, err
+	}
+
+	var artifacts []common.Artifact
+	var gear []common.Gear
+	h.db.Where("zone_id = ? AND is_active = true", zoneID).Find(&artifacts)
+	h.db.Where("zone_id = ? AND is_active = true", zoneID).Find(&gear)
+
+	visibleArtifacts := h.filterArtifactsByTier(artifacts, userTier)
+	if isHiddenItemsZone(zone) {
+		visibleArtifacts = revealedArtifacts(visibleArtifacts, session.LastLocationLatitude, session.LastLocationLongitude, h.revealRadius(session.UserID))
+	}
+
+	var itemIDs []uuid.UUID
+	for _, artifact := range visibleArtifacts {
+		itemIDs = append(itemIDs, artifact.ID)
+	}
+	for _, g := range h.filterGearByTier(gear, userTier) {
+		itemIDs = append(itemIDs, g.ID)
+	}
+	if len(itemIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		WITH bounds AS (
+			SELECT ST_TileEnvelope(@z, @x, @y) AS geom
+		),
+		item_rows AS (
+			SELECT a.id::text AS id, 'artifact' AS item_type, a.name, a.type, a.rarity, 0 AS level, a.biome,
+				ST_AsMVTGeom(ST_Transform(ST_SetSRID(ST_Point(a.location_longitude, a.location_latitude), 4326), 3857), bounds.geom, @extent, @buffer, true) AS geom
+			FROM artifacts a, bounds
+			WHERE a.id IN @ids
+			UNION ALL
+			SELECT g.id::text AS id, 'gear' AS item_type, g.name, g.type, '' AS rarity, g.level, g.biome,
+				ST_AsMVTGeom(ST_Transform(ST_SetSRID(ST_Point(g.location_longitude, g.location_latitude), 4326), 3857), bounds.geom, @extent, @buffer, true) AS geom
+			FROM gear g, bounds
+			WHERE g.id IN @ids
+		)
+		SELECT COALESCE((SELECT ST_AsMVT(t, 'items', @extent, 'geom') FROM (SELECT * FROM item_rows WHERE geom IS NOT NULL) t), ''::bytea)
+	`
+
+	var layer []byte
+	err := h.db.Raw(query, map[string]interface{}{
+		"z": z, "x": x, "y": y,
+		"extent": TileExtent,
+		"buffer": TileBuffer,
+		"ids":    itemIDs,
+	}).Row().Scan(&layer)
+
+	return layer, err
+}
+
+// ============================================
+// TILE MATH (Web Mercator / XYZ schéma)
+// ============================================
+
+func parseTileCoords(zParam, xParam, yParam string) (int, int, int, error) {
+	z, err := strconv.Atoi(zParam)
+	if err != nil || z < MinTileZoom || z > MaxTileZoom {
+		return 0, 0, 0, fmt.Errorf("Invalid tile: zoom must be between %d and %d", MinTileZoom, MaxTileZoom)
+	}
+
+	x, err := strconv.Atoi(xParam)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Invalid tile: invalid x")
+	}
+
+	y, err := strconv.Atoi(strings.TrimSuffix(yParam, ".mvt"))
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Invalid tile: invalid y")
+	}
+
+	maxIndex := 1 << z
+	if x < 0 || x >= maxIndex || y < 0 || y >= maxIndex {
+		return 0, 0, 0, fmt.Errorf("Invalid tile: out of range")
+	}
+
+	return z, x, y, nil
+}
+
+// Severozápadný roh dlaždice
+func tileToLngLat(z, x, y int) (float64, float64) {
+	n := math.Exp2(float64(z))
+	lng := float64(x)/n*360.0 - 180.0
+	lat := math.Atan(math.Sinh(math.Pi*(1-2*float64(y)/n))) * 180.0 / math.Pi
+	return lng, lat
+}
+
+func lngLatToTile(lng, lat float64, z int) (int, int) {
+	n := math.Exp2(float64(z))
+	latRad := lat * math.Pi / 180.0
+
+	x := int(math.Floor((lng + 180.0) / 360.0 * n))
+	y := int(math.Floor((1.0 - math.Log(math.Tan(latRad)+1/math.Cos(latRad))/math.Pi) / 2.0 * n))
+
+	maxIndex := int(n) - 1
+	return clampInt(x, 0, maxIndex), clampInt(y, 0, maxIndex)
+}
+
+func tileBounds(z, x, y int) (minLng, minLat, maxLng, maxLat float64) {
+	minLng, maxLat = tileToLngLat(z, x, y)
+	maxLng, minLat = tileToLngLat(z, x+1, y+1)
+	return minLng, minLat, maxLng, maxLat
+}
+
+// Šírka dlaždice v metroch (EPSG:3857)
+func tileWidthMeters(z int) float64 {
+	return 2 * math.Pi * 6378137.0 / math.Exp2(float64(z))
+}
+
+// Rozšíri bounding box o daný počet metrov na každú stranu
+func expandBounds(minLng, minLat, maxLng, maxLat, meters float64) (float64, float64, float64, float64) {
+	latOffset := meters / 111000
+	lngOffset := meters / (111000 * math.Cos(((minLat+maxLat)/2)*math.Pi/180))
+	return minLng - lngOffset, minLat - latOffset, maxLng + lngOffset, maxLat + latOffset
+}
+
+func clampInt(value, minValue, maxValue int) int {
+	if value < minValue {
+		return minValue
+	}
+	if value > maxValue {
+		return maxValue
+	}
+	return value
+}
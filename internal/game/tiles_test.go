@@ -0,0 +1,49 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLngLatToTile_RoundTrip(t *testing.T) {
+	// Bratislava
+	lng, lat := 17.1077, 48.1486
+	for z := MinTileZoom; z <= MaxTileZoom; z++ {
+		x, y := lngLatToTile(lng, lat, z)
+		minLng, minLat, maxLng, maxLat := tileBounds(z, x, y)
+		if lng < minLng || lng > maxLng || lat < minLat || lat > maxLat {
+			t.Errorf("z=%d: point (%v, %v) not inside tile %d/%d bounds [%v %v %v %v]",
+				z, lng, lat, x, y, minLng, minLat, maxLng, maxLat)
+		}
+	}
+}
+
+func TestTileWidthMeters(t *testing.T) {
+	if got := tileWidthMeters(0); math.Abs(got-40075016.686) > 1 {
+		t.Errorf("tileWidthMeters(0) = %v; want ~40075016.686", got)
+	}
+	if got, want := tileWidthMeters(10), tileWidthMeters(0)/1024; math.Abs(got-want) > 1e-6 {
+		t.Errorf("tileWidthMeters(10) = %v; want %v", got, want)
+	}
+}
+
+func TestParseTileCoords(t *testing.T) {
+	tests := []struct {
+		z, x, y string
+		valid   bool
+	}{
+		{"14", "9071", "5659.mvt", true},
+		{"14", "9071", "5659", true},
+		{"9", "1", "1.mvt", false},
+		{"19", "1", "1.mvt", false},
+		{"10", "1024", "1.mvt", false},
+		{"10", "-1", "1.mvt", false},
+		{"10", "1", "abc.mvt", false},
+	}
+	for _, tt := range tests {
+		_, _, _, err := parseTileCoords(tt.z, tt.x, tt.y)
+		if (err == nil) != tt.valid {
+			t.Errorf("parseTileCoords(%s, %s, %s) error = %v; want valid=%v", tt.z, tt.x, tt.y, err, tt.valid)
+		}
+	}
+}
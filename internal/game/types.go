@@ -20,6 +20,26 @@ type ScanAreaRequest struct {
 	Longitude float64 `json:"longitude" binding:"required"`
 }
 
+// ✅ NEW: Detector (hidden artifacts)
+type DetectRequest struct {
+	Latitude  float64 `json:"latitude" binding:"required"`
+	Longitude float64 `json:"longitude" binding:"required"`
+}
+
+type DetectorProfile struct {
+	Type            string  `json:"type"` // basic, geiger_counter, radiation_detector
+	Level           int     `json:"level"`
+	RangeMeters     float64 `json:"range_meters"`
+	BearingAccuracy float64 `json:"bearing_accuracy_degrees"` // ± odchýlka smeru
+}
+
+type DetectorSignal struct {
+	Strength       float64 `json:"strength"` // 0.0 - 1.0
+	Level          string  `json:"level"`    // hot, warm, cold
+	BearingDegrees float64 `json:"bearing_degrees"`
+	Direction      string  `json:"direction"`
+}
+
 type ScanAreaResponse struct {
 	ZonesCreated      int               `json:"zones_created"`
 	Zones             []ZoneWithDetails `json:"zones"`
@@ -28,6 +48,9 @@ type ScanAreaResponse struct {
 	MaxZones          int               `json:"max_zones"`
 	CurrentZoneCount  int               `json:"current_zone_count"`
 	PlayerTier        int               `json:"player_tier"`
+	EffectiveTier     int               `json:"effective_tier"`       // vyšší z tieru a levelu (prístup, obtiažnosť)
+	AreaYield         *AreaYield        `json:"area_yield,omitempty"` // indikátor vyčerpania bunky
+	ScanRadius        float64           `json:"scan_radius"`          // viditeľnosť zón vrátane bonusu z loadoutu
 }
 
 type ZoneWithDetails struct {
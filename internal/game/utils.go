@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"geoanomaly/internal/common"
+	"geoanomaly/internal/xp"
 
+	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
@@ -87,35 +89,60 @@ func (h *Handler) getSessionItemsCollected(userID uuid.UUID, zoneID *uuid.UUID,
 	// Count items collected from this zone since entered
 	var count int64
 
-	h.db.Model(&common.InventoryItem{}).
-		Where("user_id = ? AND created_at > ? AND properties->>'collected_from' = ?",
-			userID, enteredAt, zoneID.String()).
+	h.db.Model(&common.ItemCollect{}).
+		Where("user_id = ? AND created_at > ? AND zone_id = ?", userID, enteredAt, *zoneID).
 		Count(&count)
 
 	return int(count)
 }
 
-// ✅ NEW: XP calculation based on items and tier
-func (h *Handler) calculateXPGained(itemsCollected int, zoneTier int, biome string) int {
-	baseXP := itemsCollected * 10 // 10 XP per item
+// ✅ NEW: XP do odpovede - hlavný grant + bonusy (prvý typ artefaktu, zone clear...)
+func addXPToResponse(response gin.H, main *xp.XPResult, bonuses []*xp.XPResult) {
+	latest := main
+	xpGained := main.XPGained
+	levelUp := main.LevelUp
+	levelUpInfo := main.LevelUpInfo
+
+	for _, bonus := range bonuses {
+		latest = bonus
+		xpGained += bonus.XPGained
+		if bonus.LevelUp {
+			if !levelUp {
+				levelUpInfo = bonus.LevelUpInfo
+			} else {
+				levelUpInfo.NewLevel = bonus.CurrentLevel
+			}
+			levelUp = true
+		}
+	}
 
-	// Bonus for higher tier zones
-	tierBonus := zoneTier * 5
+	response["xp_gained"] = xpGained
+	response["total_xp"] = latest.TotalXP
+	response["current_level"] = latest.CurrentLevel
+	response["xp_breakdown"] = main.Breakdown
+	if main.Multipliers != nil {
+		response["xp_multipliers"] = main.Multipliers
+	}
+	if len(bonuses) > 0 {
+		response["bonus_xp"] = bonuses
+	}
 
-	// Bonus for dangerous biomes
-	biomeBonus := 0
-	switch biome {
-	case BiomeRadioactive, BiomeChemical:
-		biomeBonus = itemsCollected * 15 // Extreme danger bonus
-	case BiomeIndustrial:
-		biomeBonus = itemsCollected * 10 // High danger bonus
-	case BiomeMountain, BiomeUrban, BiomeWater:
-		biomeBonus = itemsCollected * 5 // Medium danger bonus
-	default:
-		biomeBonus = 0 // Forest - no bonus
+	if levelUp {
+		response["level_up"] = true
+		response["level_up_info"] = levelUpInfo
+		response["congratulations"] = fmt.Sprintf("🎉 Level Up! You are now level %d!", latest.CurrentLevel)
 	}
+}
 
-	return baseXP + tierBonus + biomeBonus
+// ✅ NEW: V zóne nezostal žiadny aktívny artefakt ani gear
+func (h *Handler) isZoneCleared(zoneID uuid.UUID) bool {
+	var remaining int64
+	h.db.Model(&common.Artifact{}).Where("zone_id = ? AND is_active = true", zoneID).Count(&remaining)
+	if remaining > 0 {
+		return false
+	}
+	h.db.Model(&common.Gear{}).Where("zone_id = ? AND is_active = true", zoneID).Count(&remaining)
+	return remaining == 0
 }
 
 // ✅ NEW: Zone information extraction
@@ -0,0 +1,359 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Druhy výnosu v bunke
+const (
+	YieldKindScan    = "scan"    // zóny spawnuté skenom
+	YieldKindCollect = "collect" // zobrané itemy
+)
+
+// Stav oblasti pre klienta (indikátor vyčerpania)
+const (
+	AreaStatusFresh    = "fresh"
+	AreaStatusWorn     = "worn"
+	AreaStatusDepleted = "depleted"
+)
+
+const (
+	areaYieldConfigKey = "default"
+	areaYieldConfigTTL = time.Minute
+)
+
+// Poradie rarít od najnižšej (pre zhoršenie lootu)
+var rarityOrder = []string{"common", "rare", "epic", "legendary"}
+
+// DefaultAreaYieldConfig - zapíše sa do area_yield_configs pri štarte
+var DefaultAreaYieldConfig = common.AreaYieldConfig{
+	Key:                  areaYieldConfigKey,
+	CellZoom:             14,
+	ShortWindowMinutes:   60,
+	LongWindowHours:      24,
+	ScanFreeShort:        6,
+	ScanFreeLong:         20,
+	CollectFreeShort:     15,
+	CollectFreeLong:      50,
+	DecayStep:            0.05,
+	MinYield:             0.1,
+	RarityDowngradeBelow: 0.5,
+}
+
+// AreaYield - výnos hráča v bunke (1.0 = plný, MinYield = vyčerpaná)
+type AreaYield struct {
+	Cell          string  `json:"cell"`
+	ScanYield     float64 `json:"scan_yield"`
+	CollectYield  float64 `json:"collect_yield"`
+	Depletion     int     `json:"depletion_percent"`
+	Status        string  `json:"status"` // fresh, worn, depleted
+	ScansShort    int     `json:"zones_spawned_short"`
+	ScansLong     int     `json:"zones_spawned_long"`
+	CollectsShort int     `json:"collects_short"`
+	CollectsLong  int     `json:"collects_long"`
+}
+
+type areaYieldConfigCache struct {
+	mu       sync.RWMutex
+	config   *common.AreaYieldConfig
+	loadedAt time.Time
+}
+
+var yieldConfig = &areaYieldConfigCache{}
+
+// InvalidateAreaYieldConfig - po úprave area_yield_configs sa parametre načítajú znova
+func InvalidateAreaYieldConfig() {
+	yieldConfig.mu.Lock()
+	defer yieldConfig.mu.Unlock()
+	yieldConfig.config = nil
+}
+
+func (cc *areaYieldConfigCache) get(db *gorm.DB) common.AreaYieldConfig {
+	cc.mu.RLock()
+	config, loadedAt := cc.config, cc.loadedAt
+	cc.mu.RUnlock()
+
+	if config != nil && time.Since(loadedAt) < areaYieldConfigTTL {
+		return *config
+	}
+
+	var fresh common.AreaYieldConfig
+	if err := db.Where("key = ? AND deleted_at IS NULL", areaYieldConfigKey).First(&fresh).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Printf("❌ Failed to load area yield config: %v", err)
+		}
+		if config != nil {
+			return *config
+		}
+		return DefaultAreaYieldConfig
+	}
+
+	cc.mu.Lock()
+	cc.config = &fresh
+	cc.loadedAt = time.Now()
+	cc.mu.Unlock()
+
+	return fresh
+}
+
+// SeedAreaYieldConfig - zapíše predvolené parametre, ak ešte neexistujú
+func SeedAreaYieldConfig(db *gorm.DB) error {
+	config := DefaultAreaYieldConfig
+	result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).Create(&config)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("🌾 Seeded area yield config")
+	}
+	return nil
+}
+
+// yieldCell - bunka mapy = dlaždica na zoome CellZoom
+func yieldCell(config common.AreaYieldConfig, lat, lng float64) string {
+	x, y := lngLatToTile(lng, lat, config.CellZoom)
+	return fmt.Sprintf("%d/%d/%d", config.CellZoom, x, y)
+}
+
+// areaYield - aktuálny výnos hráča v bunke, kde leží daný bod
+func (h *Handler) areaYield(userID uuid.UUID, lat, lng float64) AreaYield {
+	config := yieldConfig.get(h.db)
+	cell := yieldCell(config, lat, lng)
+	now := time.Now()
+
+	var rows []struct {
+		Kind       string
+		ShortUnits int
+		LongUnits  int
+	}
+	h.db.Model(&common.PlayerCellYield{}).
+		Select("kind, COALESCE(SUM(units) FILTER (WHERE created_at >= ?), 0) AS short_units, COALESCE(SUM(units), 0) AS long_units",
+			now.Add(-time.Duration(config.ShortWindowMinutes)*time.Minute)).
+		Where("user_id = ? AND cell = ? AND created_at >= ?", userID, cell,
+			now.Add(-time.Duration(config.LongWindowHours)*time.Hour)).
+		Group("kind").
+		Scan(&rows)
+
+	yield := AreaYield{Cell: cell}
+	for _, row := range rows {
+		switch row.Kind {
+		case YieldKindScan:
+			yield.ScansShort, yield.ScansLong = row.ShortUnits, row.LongUnits
+		case YieldKindCollect:
+			yield.CollectsShort, yield.CollectsLong = row.ShortUnits, row.LongUnits
+		}
+	}
+	return computeAreaYield(config, yield)
+}
+
+// computeAreaYield - výnos je minimum z krátkeho a dlhého okna
+func computeAreaYield(config common.AreaYieldConfig, yield AreaYield) AreaYield {
+	yield.ScanYield = math.Min(
+		windowYield(config, yield.ScansShort, config.ScanFreeShort),
+		windowYield(config, yield.ScansLong, config.ScanFreeLong))
+	yield.CollectYield = math.Min(
+		windowYield(config, yield.CollectsShort, config.CollectFreeShort),
+		windowYield(config, yield.CollectsLong, config.CollectFreeLong))
+
+	lowest := math.Min(yield.ScanYield, yield.CollectYield)
+	yield.Depletion = int(math.Round((1 - lowest) * 100))
+	switch {
+	case lowest >= 0.9:
+		yield.Status = AreaStatusFresh
+	case lowest > config.RarityDowngradeBelow:
+		yield.Status = AreaStatusWorn
+	default:
+		yield.Status = AreaStatusDepleted
+	}
+	return yield
+}
+
+func windowYield(config common.AreaYieldConfig, units, free int) float64 {
+	over := units - free
+	if over <= 0 {
+		return 1
+	}
+	value := math.Max(config.MinYield, 1-float64(over)*config.DecayStep)
+	return math.Round(value*1000) / 1000
+}
+
+// recordYield - zapíše výnos hráča do bunky
+func (h *Handler) recordYield(userID uuid.UUID, lat, lng float64, kind string, units int) {
+	if units <= 0 {
+		return
+	}
+	config := yieldConfig.get(h.db)
+	entry := common.PlayerCellYield{
+		UserID: userID,
+		Cell:   yieldCell(config, lat, lng),
+		Kind:   kind,
+		Units:  units,
+	}
+	if err := h.db.Create(&entry).Error; err != nil {
+		log.Printf("❌ Failed to record area yield: %v", err)
+	}
+}
+
+// decayedCount - počet spawnov znížený výnosom; zlomok sa rozhodne hodom (roll v <0,1))
+func decayedCount(count int, yield, roll float64) int {
+	if count <= 0 {
+		return 0
+	}
+	expected := float64(count) * yield
+	result := int(expected)
+	if roll < expected-float64(result) {
+		result++
+	}
+	return result
+}
+
+// decayRarity - vo vyčerpanej oblasti je loot o stupeň horší
+func decayRarity(config common.AreaYieldConfig, rarity string, yield float64) string {
+	if yield >= config.RarityDowngradeBelow {
+		return rarity
+	}
+	for i, r := range rarityOrder {
+		if r == rarity && i > 0 {
+			return rarityOrder[i-1]
+		}
+	}
+	return rarity
+}
+
+// decayGearLevel - gear vo vyčerpanej oblasti má o level menej (min 1)
+func decayGearLevel(config common.AreaYieldConfig, level int, yield float64) int {
+	if yield >= config.RarityDowngradeBelow || level <= 1 {
+		return level
+	}
+	return level - 1
+}
+
+// CleanupCellYields - zmaže záznamy mimo dlhého okna (volá scheduler)
+func CleanupCellYields(db *gorm.DB) int64 {
+	config := yieldConfig.get(db)
+	result := db.Where("created_at < ?", time.Now().Add(-time.Duration(config.LongWindowHours)*time.Hour)).
+		Delete(&common.PlayerCellYield{})
+	if result.Error != nil {
+		log.Printf("❌ Failed to cleanup cell yields: %v", result.Error)
+		return 0
+	}
+	return result.RowsAffected
+}
+
+// GetAreaYield - GET /game/area/yield?lat=&lng=
+// Indikátor vyčerpania oblasti pre klienta
+func (h *Handler) GetAreaYield(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+	if latErr != nil || lngErr != nil || !IsValidGPSCoordinate(lat, lng) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Valid lat and lng query parameters required"})
+		return
+	}
+
+	config := yieldConfig.get(h.db)
+	c.JSON(http.StatusOK, gin.H{
+		"area_yield":             h.areaYield(userID.(uuid.UUID), lat, lng),
+		"cell_size_meters":       math.Round(tileWidthMeters(config.CellZoom) * math.Cos(lat*math.Pi/180)),
+		"short_window_mins":      config.ShortWindowMinutes,
+		"long_window_hours":      config.LongWindowHours,
+		"rarity_downgrade_below": config.RarityDowngradeBelow,
+	})
+}
+
+// UpdateAreaYieldConfigRequest - len zadané polia sa menia
+type UpdateAreaYieldConfigRequest struct {
+	CellZoom             *int     `json:"cell_zoom,omitempty" binding:"omitempty,min=10,max=18"`
+	ShortWindowMinutes   *int     `json:"short_window_minutes,omitempty" binding:"omitempty,min=1"`
+	LongWindowHours      *int     `json:"long_window_hours,omitempty" binding:"omitempty,min=1,max=720"`
+	ScanFreeShort        *int     `json:"scan_free_short,omitempty" binding:"omitempty,min=0"`
+	ScanFreeLong         *int     `json:"scan_free_long,omitempty" binding:"omitempty,min=0"`
+	CollectFreeShort     *int     `json:"collect_free_short,omitempty" binding:"omitempty,min=0"`
+	CollectFreeLong      *int     `json:"collect_free_long,omitempty" binding:"omitempty,min=0"`
+	DecayStep            *float64 `json:"decay_step,omitempty" binding:"omitempty,min=0,max=1"`
+	MinYield             *float64 `json:"min_yield,omitempty" binding:"omitempty,gt=0,max=1"`
+	RarityDowngradeBelow *float64 `json:"rarity_downgrade_below,omitempty" binding:"omitempty,min=0,max=1"`
+}
+
+// GetAreaYieldConfig - GET /admin/yield/config
+func (h *Handler) GetAreaYieldConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"config": yieldConfig.get(h.db)})
+}
+
+// UpdateAreaYieldConfig - PUT /admin/yield/config
+func (h *Handler) UpdateAreaYieldConfig(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var req UpdateAreaYieldConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var config common.AreaYieldConfig
+	if err := h.db.Where("key = ? AND deleted_at IS NULL", areaYieldConfigKey).First(&config).Error; err != nil {
+		config = DefaultAreaYieldConfig
+	}
+
+	setInt := func(target *int, value *int) {
+		if value != nil {
+			*target = *value
+		}
+	}
+	setFloat := func(target *float64, value *float64) {
+		if value != nil {
+			*target = *value
+		}
+	}
+	setInt(&config.CellZoom, req.CellZoom)
+	setInt(&config.ShortWindowMinutes, req.ShortWindowMinutes)
+	setInt(&config.LongWindowHours, req.LongWindowHours)
+	setInt(&config.ScanFreeShort, req.ScanFreeShort)
+	setInt(&config.ScanFreeLong, req.ScanFreeLong)
+	setInt(&config.CollectFreeShort, req.CollectFreeShort)
+	setInt(&config.CollectFreeLong, req.CollectFreeLong)
+	setFloat(&config.DecayStep, req.DecayStep)
+	setFloat(&config.MinYield, req.MinYield)
+	setFloat(&config.RarityDowngradeBelow, req.RarityDowngradeBelow)
+
+	if config.ShortWindowMinutes > config.LongWindowHours*60 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Short window must not exceed long window"})
+		return
+	}
+
+	admin := adminID.(uuid.UUID)
+	config.UpdatedBy = &admin
+	if err := h.db.Save(&config).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update area yield config"})
+		return
+	}
+	InvalidateAreaYieldConfig()
+
+	log.Printf("🌾 Area yield config updated by admin %s", admin)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Area yield config updated",
+		"config":  config,
+	})
+}
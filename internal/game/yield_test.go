@@ -0,0 +1,57 @@
+package game
+
+import "testing"
+
+func TestComputeAreaYield(t *testing.T) {
+	config := DefaultAreaYieldConfig
+	config.ScanFreeShort, config.ScanFreeLong = 2, 10
+	config.CollectFreeShort, config.CollectFreeLong = 5, 20
+	config.DecayStep, config.MinYield = 0.2, 0.1
+
+	fresh := computeAreaYield(config, AreaYield{ScansShort: 2, ScansLong: 2, CollectsShort: 5, CollectsLong: 5})
+	if fresh.ScanYield != 1 || fresh.CollectYield != 1 || fresh.Status != AreaStatusFresh || fresh.Depletion != 0 {
+		t.Errorf("expected fresh cell, got %+v", fresh)
+	}
+
+	// Dlhé okno penalizuje aj keď krátke je v limite
+	worn := computeAreaYield(config, AreaYield{ScansShort: 1, ScansLong: 12})
+	if worn.ScanYield != 0.6 || worn.Status != AreaStatusWorn || worn.Depletion != 40 {
+		t.Errorf("expected worn cell from long window, got %+v", worn)
+	}
+
+	depleted := computeAreaYield(config, AreaYield{CollectsShort: 50, CollectsLong: 50})
+	if depleted.CollectYield != config.MinYield || depleted.Status != AreaStatusDepleted {
+		t.Errorf("expected depleted cell clamped to min yield, got %+v", depleted)
+	}
+}
+
+func TestDecayedCount(t *testing.T) {
+	if got := decayedCount(4, 1, 0.99); got != 4 {
+		t.Errorf("full yield must keep spawn count, got %d", got)
+	}
+	if got := decayedCount(3, 0.5, 0.9); got != 1 {
+		t.Errorf("expected 1 with a high roll, got %d", got)
+	}
+	if got := decayedCount(3, 0.5, 0.1); got != 2 {
+		t.Errorf("expected 2 with a low roll, got %d", got)
+	}
+	if got := decayedCount(-1, 1, 0); got != 0 {
+		t.Errorf("negative count must yield 0, got %d", got)
+	}
+}
+
+func TestDecayLoot(t *testing.T) {
+	config := DefaultAreaYieldConfig
+	if got := decayRarity(config, "legendary", 0.3); got != "epic" {
+		t.Errorf("expected downgraded rarity, got %s", got)
+	}
+	if got := decayRarity(config, "common", 0.1); got != "common" {
+		t.Errorf("common cannot be downgraded, got %s", got)
+	}
+	if got := decayRarity(config, "epic", 0.9); got != "epic" {
+		t.Errorf("healthy yield must keep rarity, got %s", got)
+	}
+	if got := decayGearLevel(config, 3, 0.2); got != 2 {
+		t.Errorf("expected gear level 2, got %d", got)
+	}
+}
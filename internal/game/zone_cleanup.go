@@ -95,6 +95,9 @@ func (cs *CleanupService) cleanupSingleZone(zone common.Zone) (int, int) {
 		log.Printf("   ⚔️ Deactivated %d gear items", len(gear))
 	}
 
+	// 2b. Deactivate hazards
+	cs.db.Model(&common.ZoneHazard{}).Where("zone_id = ? AND is_active = true", zone.ID).Update("is_active", false)
+
 	// 3. Remove players from zone
 	var sessions []common.PlayerSession
 	cs.db.Where("current_zone = ?", zone.ID).Find(&sessions)
@@ -110,6 +113,9 @@ func (cs *CleanupService) cleanupSingleZone(zone common.Zone) (int, int) {
 	zone.Properties["cleanup_time"] = time.Now().Unix()
 	cs.db.Save(&zone)
 
+	// 5. Invalidate cached map tiles
+	mapTileCache.InvalidateZone(zone)
+
 	log.Printf("   ✅ Zone %s cleaned successfully", zone.Name)
 	return itemsRemoved, playersAffected
 }
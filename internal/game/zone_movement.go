@@ -0,0 +1,510 @@
+package game
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ZoneMovement - uložené v zone.Properties["movement"]
+// Poloha je deterministická funkcia času, takže každá inštancia vypočíta rovnaký stred
+type ZoneMovement struct {
+	Type           string       `json:"type"` // path, random_walk
+	SpeedMPS       float64      `json:"speed_mps"`
+	StartedAt      int64        `json:"started_at"`
+	OriginLat      float64      `json:"origin_lat"`
+	OriginLng      float64      `json:"origin_lng"`
+	Path           [][2]float64 `json:"path,omitempty"` // [lat, lng] waypointy, zóna sa po poslednom vracia na začiatok
+	MaxDriftMeters float64      `json:"max_drift_meters,omitempty"`
+	Seed           int64        `json:"seed,omitempty"`
+}
+
+// ZoneShrink - uložené v zone.Properties["shrink"]
+type ZoneShrink struct {
+	OriginalRadius int     `json:"original_radius"`
+	MinRatio       float64 `json:"min_ratio"`
+}
+
+type ZoneGeometry struct {
+	ZoneID         uuid.UUID     `json:"zone_id"`
+	Center         LocationPoint `json:"center"`
+	RadiusMeters   int           `json:"radius_meters"`
+	OriginalRadius int           `json:"original_radius"`
+	MovementType   string        `json:"movement_type,omitempty"`
+	SpeedMPS       float64       `json:"speed_mps"`
+	HeadingDegrees float64       `json:"heading_degrees"`
+	Shrinking      bool          `json:"shrinking"`
+	ComputedAt     int64         `json:"computed_at"`
+}
+
+type ZoneMovementService struct {
+	db *gorm.DB
+}
+
+func NewZoneMovementService(db *gorm.DB) *ZoneMovementService {
+	return &ZoneMovementService{db: db}
+}
+
+// GetZoneGeometry - GET /game/zones/:id/geometry
+// Aktuálny stred, polomer a rýchlosť zóny (počítané pri čítaní, presnejšie ako posledný tick scheduleru)
+func (h *Handler) GetZoneGeometry(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var user common.User
+	if err := h.db.First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var zone common.Zone
+	if err := h.db.First(&zone, "id = ? AND is_active = true", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Zone not found"})
+		return
+	}
+
+	if zone.TierRequired > getMaxVisibleZoneTier(user.ProgressionTier()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Zone not visible for your tier"})
+		return
+	}
+
+	geometry := CurrentZoneGeometry(zone, time.Now())
+
+	c.JSON(http.StatusOK, gin.H{
+		"geometry":     geometry,
+		"ttl_status":   zone.TTLStatus(),
+		"expires_in":   int64(zone.TimeUntilExpiry().Seconds()),
+		"next_update":  time.Now().Add(MovementTickSeconds * time.Second).Unix(),
+		"is_moving":    geometry.SpeedMPS > 0,
+		"is_shrinking": geometry.Shrinking,
+	})
+}
+
+// CurrentZoneGeometry - poloha a polomer zóny v čase now
+func CurrentZoneGeometry(zone common.Zone, now time.Time) ZoneGeometry {
+	geometry := ZoneGeometry{
+		ZoneID:         zone.ID,
+		Center:         LocationPoint{Latitude: zone.Location.Latitude, Longitude: zone.Location.Longitude},
+		RadiusMeters:   zone.RadiusMeters,
+		OriginalRadius: zone.RadiusMeters,
+		ComputedAt:     now.Unix(),
+	}
+
+	if movement, ok := zoneMovementFromProperties(zone); ok {
+		// Po expirácii sa zóna už nehýbe
+		at := now
+		if zone.ExpiresAt != nil && at.After(*zone.ExpiresAt) {
+			at = *zone.ExpiresAt
+		}
+		elapsed := at.Sub(time.Unix(movement.StartedAt, 0)).Seconds()
+
+		lat, lng, heading, speed := movement.positionAt(math.Max(elapsed, 0))
+		geometry.Center = LocationPoint{Latitude: lat, Longitude: lng}
+		geometry.MovementType = movement.Type
+		geometry.HeadingDegrees = math.Round(heading)
+		if at.Equal(now) {
+			geometry.SpeedMPS = speed
+		}
+	}
+
+	if shrink, ok := zoneShrinkFromProperties(zone); ok {
+		geometry.OriginalRadius = shrink.OriginalRadius
+		geometry.RadiusMeters = shrink.radiusAt(zone.ExpiresAt, now)
+		geometry.Shrinking = geometry.RadiusMeters < shrink.OriginalRadius
+	}
+
+	return geometry
+}
+
+// liveZone - zóna s aktuálnym stredom a polomerom; hodnoty v DB zaostávajú až o MovementTickSeconds
+func liveZone(zone common.Zone, now time.Time) common.Zone {
+	if !isDynamicGeometry(zone) {
+		return zone
+	}
+	geometry := CurrentZoneGeometry(zone, now)
+	zone.Location.Latitude = geometry.Center.Latitude
+	zone.Location.Longitude = geometry.Center.Longitude
+	zone.RadiusMeters = geometry.RadiusMeters
+	return zone
+}
+
+// liveLocation - poloha itemu/hazardu z posledného ticku prenesená na aktuálnu geometriu
+// (rovnaký posun a zmenšenie, aké pri ďalšom ticku zapíše updateZoneGeometry)
+func liveLocation(stored, live common.Zone, location common.Location) common.Location {
+	scale := 1.0
+	if stored.RadiusMeters > 0 {
+		scale = float64(live.RadiusMeters) / float64(stored.RadiusMeters)
+	}
+	location.Latitude = live.Location.Latitude + (location.Latitude-stored.Location.Latitude)*scale
+	location.Longitude = live.Location.Longitude + (location.Longitude-stored.Location.Longitude)*scale
+	return location
+}
+
+// storedLocation - opak liveLocation: aktuálna poloha v pohyblivej zóne prepočítaná na geometriu
+// z posledného ticku, v ktorej sú uložené itemy (ďalší tick ich posunie spolu so zónou)
+func storedLocation(stored, live common.Zone, location common.Location) common.Location {
+	scale := 1.0
+	if live.RadiusMeters > 0 {
+		scale = float64(stored.RadiusMeters) / float64(live.RadiusMeters)
+	}
+	location.Latitude = stored.Location.Latitude + (location.Latitude-live.Location.Latitude)*scale
+	location.Longitude = stored.Location.Longitude + (location.Longitude-live.Location.Longitude)*scale
+	return location
+}
+
+func liveArtifacts(stored, live common.Zone, artifacts []common.Artifact) []common.Artifact {
+	if isDynamicGeometry(stored) {
+		for i := range artifacts {
+			artifacts[i].Location = liveLocation(stored, live, artifacts[i].Location)
+		}
+	}
+	return artifacts
+}
+
+func liveGear(stored, live common.Zone, gear []common.Gear) []common.Gear {
+	if isDynamicGeometry(stored) {
+		for i := range gear {
+			gear[i].Location = liveLocation(stored, live, gear[i].Location)
+		}
+	}
+	return gear
+}
+
+func isDynamicGeometry(zone common.Zone) bool {
+	_, moving := zoneMovementFromProperties(zone)
+	_, shrinking := zoneShrinkFromProperties(zone)
+	return moving || shrinking
+}
+
+// positionAt - stred zóny po elapsed sekundách od štartu
+func (m ZoneMovement) positionAt(elapsed float64) (lat, lng, heading, speed float64) {
+	if m.SpeedMPS <= 0 {
+		return m.OriginLat, m.OriginLng, 0, 0
+	}
+
+	switch m.Type {
+	case MovementTypePath:
+		return m.pathPositionAt(elapsed)
+	case MovementTypeRandomWalk:
+		return m.randomWalkPositionAt(elapsed)
+	default:
+		return m.OriginLat, m.OriginLng, 0, 0
+	}
+}
+
+// Uzavretá trasa origin -> waypointy -> origin, dokola
+func (m ZoneMovement) pathPositionAt(elapsed float64) (float64, float64, float64, float64) {
+	points := append([][2]float64{{m.OriginLat, m.OriginLng}}, m.Path...)
+	if len(points) < 2 {
+		return m.OriginLat, m.OriginLng, 0, 0
+	}
+	points = append(points, points[0])
+
+	total := 0.0
+	legs := make([]float64, len(points)-1)
+	for i := 0; i < len(points)-1; i++ {
+		legs[i] = CalculateDistance(points[i][0], points[i][1], points[i+1][0], points[i+1][1])
+		total += legs[i]
+	}
+	if total == 0 {
+		return m.OriginLat, m.OriginLng, 0, 0
+	}
+
+	distance := math.Mod(elapsed*m.SpeedMPS, total)
+	for i, leg := range legs {
+		if distance > leg {
+			distance -= leg
+			continue
+		}
+		from, to := points[i], points[i+1]
+		heading := calculateBearing(from[0], from[1], to[0], to[1])
+		lat, lng := offsetLocation(from[0], from[1], distance, heading)
+		return lat, lng, heading, m.SpeedMPS
+	}
+
+	return m.OriginLat, m.OriginLng, 0, m.SpeedMPS
+}
+
+// Náhodná prechádzka so segmentmi podľa seedu - každá inštancia dostane rovnaký výsledok
+// Celé segmenty pokračujú od posledného checkpointu, takže volanie nerastie s vekom zóny
+func (m ZoneMovement) randomWalkPositionAt(elapsed float64) (float64, float64, float64, float64) {
+	full := int64(elapsed / RandomWalkSegmentSeconds)
+
+	segment, lat, lng, ok := walkCheckpoints.load(m, full)
+	if !ok {
+		segment, lat, lng = 0, m.OriginLat, m.OriginLng
+	}
+	lat, lng = m.walkSegments(segment, full, lat, lng)
+	walkCheckpoints.store(m, full, lat, lng)
+
+	rest := elapsed - float64(full)*RandomWalkSegmentSeconds
+	heading := m.walkHeading(full, lat, lng)
+	lat, lng = offsetLocation(lat, lng, m.SpeedMPS*rest, heading)
+
+	return lat, lng, heading, m.SpeedMPS
+}
+
+// walkSegments - celé segmenty from..to-1 od polohy lat, lng
+func (m ZoneMovement) walkSegments(from, to int64, lat, lng float64) (float64, float64) {
+	for segment := from; segment < to; segment++ {
+		heading := m.walkHeading(segment, lat, lng)
+		lat, lng = offsetLocation(lat, lng, m.SpeedMPS*RandomWalkSegmentSeconds, heading)
+	}
+	return lat, lng
+}
+
+// walkHeading - smer segmentu; mimo povoleného driftu sa zóna otočí späť k pôvodnému stredu
+func (m ZoneMovement) walkHeading(segment int64, lat, lng float64) float64 {
+	maxDrift := m.MaxDriftMeters
+	if maxDrift <= 0 {
+		maxDrift = RandomWalkMaxDrift
+	}
+
+	heading := rand.New(rand.NewSource(m.Seed+segment)).Float64() * 360
+	nextLat, nextLng := offsetLocation(lat, lng, m.SpeedMPS*RandomWalkSegmentSeconds, heading)
+	if CalculateDistance(m.OriginLat, m.OriginLng, nextLat, nextLng) > maxDrift {
+		heading = calculateBearing(lat, lng, m.OriginLat, m.OriginLng)
+	}
+	return heading
+}
+
+// Od tohto počtu prechádzok sa mažú checkpointy nepoužité hodinu
+const walkCheckpointSweepSize = 1000
+
+// walkCheckpoint - poloha prechádzky na začiatku segmentu
+type walkCheckpoint struct {
+	segment  int64
+	lat, lng float64
+	usedAt   time.Time
+}
+
+// Prechádzka je určená parametrami pohybu (nie ID zóny) - zmena parametrov = iná prechádzka
+type walkKey struct {
+	seed, startedAt      int64
+	speed, drift         float64
+	originLat, originLng float64
+}
+
+type walkCheckpointCache struct {
+	sync.Mutex
+	walks map[walkKey]walkCheckpoint
+}
+
+var walkCheckpoints = &walkCheckpointCache{walks: make(map[walkKey]walkCheckpoint)}
+
+func movementWalkKey(m ZoneMovement) walkKey {
+	return walkKey{m.Seed, m.StartedAt, m.SpeedMPS, m.MaxDriftMeters, m.OriginLat, m.OriginLng}
+}
+
+// load - posledný checkpoint nie ďalej ako segment upTo
+func (wc *walkCheckpointCache) load(m ZoneMovement, upTo int64) (int64, float64, float64, bool) {
+	wc.Lock()
+	defer wc.Unlock()
+	checkpoint, ok := wc.walks[movementWalkKey(m)]
+	if !ok || checkpoint.segment > upTo {
+		return 0, 0, 0, false
+	}
+	return checkpoint.segment, checkpoint.lat, checkpoint.lng, true
+}
+
+func (wc *walkCheckpointCache) store(m ZoneMovement, segment int64, lat, lng float64) {
+	wc.Lock()
+	defer wc.Unlock()
+	now := time.Now()
+	if len(wc.walks) >= walkCheckpointSweepSize {
+		for key, checkpoint := range wc.walks {
+			if now.Sub(checkpoint.usedAt) > time.Hour {
+				delete(wc.walks, key)
+			}
+		}
+	}
+	key := movementWalkKey(m)
+	if existing, ok := wc.walks[key]; ok && existing.segment > segment {
+		existing.usedAt = now
+		wc.walks[key] = existing
+		return
+	}
+	wc.walks[key] = walkCheckpoint{segment: segment, lat: lat, lng: lng, usedAt: now}
+}
+
+// radiusAt - lineárne zmenšovanie počas posledných ShrinkWindowMinutes
+func (s ZoneShrink) radiusAt(expiresAt *time.Time, now time.Time) int {
+	if expiresAt == nil || s.OriginalRadius <= 0 {
+		return s.OriginalRadius
+	}
+
+	window := ShrinkWindowMinutes * time.Minute
+	timeLeft := expiresAt.Sub(now)
+	if timeLeft >= window {
+		return s.OriginalRadius
+	}
+
+	progress := 1.0
+	if timeLeft > 0 {
+		progress = 1 - float64(timeLeft)/float64(window)
+	}
+	ratio := 1 - (1-s.MinRatio)*progress
+
+	return max(int(float64(s.OriginalRadius)*ratio), int(MinZoneRadius))
+}
+
+// UpdateMovingZones - scheduler tick: prenesie vypočítanú geometriu do DB aj s itemami a hazardmi
+func (ms *ZoneMovementService) UpdateMovingZones() int {
+	var zones []common.Zone
+	if err := ms.db.Where("is_active = true AND (properties->'movement' IS NOT NULL OR properties->'shrink' IS NOT NULL)").
+		Find(&zones).Error; err != nil {
+		log.Printf("❌ Failed to load moving zones: %v", err)
+		return 0
+	}
+
+	updated := 0
+	for _, zone := range zones {
+		moved, err := ms.updateZoneGeometry(zone.ID)
+		if err != nil {
+			log.Printf("❌ Failed to move zone %s: %v", zone.Name, err)
+			continue
+		}
+		if moved {
+			updated++
+		}
+	}
+
+	return updated
+}
+
+func (ms *ZoneMovementService) updateZoneGeometry(zoneID uuid.UUID) (bool, error) {
+	var before, after common.Zone
+	moved := false
+
+	err := ms.db.Transaction(func(tx *gorm.DB) error {
+		// Zámok na zóne - pri viacerých inštanciách posunie itemy len prvá, ostatné uvidia nový stred
+		var zone common.Zone
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&zone, "id = ?", zoneID).Error; err != nil {
+			return err
+		}
+
+		geometry := CurrentZoneGeometry(zone, time.Now())
+		shift := CalculateDistance(zone.Location.Latitude, zone.Location.Longitude, geometry.Center.Latitude, geometry.Center.Longitude)
+		if shift < 0.5 && geometry.RadiusMeters == zone.RadiusMeters {
+			return nil
+		}
+
+		// Itemy a hazardy sa posunú so stredom a zmenšia s polomerom
+		params := map[string]interface{}{
+			"zone_id": zone.ID,
+			"old_lat": zone.Location.Latitude,
+			"old_lng": zone.Location.Longitude,
+			"new_lat": geometry.Center.Latitude,
+			"new_lng": geometry.Center.Longitude,
+			"scale":   float64(geometry.RadiusMeters) / float64(zone.RadiusMeters),
+		}
+		for _, table := range []string{"artifacts", "gear", "zone_hazards"} {
+			if err := tx.Exec(`UPDATE `+table+` SET
+				location_latitude = @new_lat + (location_latitude - @old_lat) * @scale,
+				location_longitude = @new_lng + (location_longitude - @old_lng) * @scale
+				WHERE zone_id = @zone_id AND is_active = true`, params).Error; err != nil {
+				return err
+			}
+		}
+
+		before = zone
+		zone.Location.Latitude = geometry.Center.Latitude
+		zone.Location.Longitude = geometry.Center.Longitude
+		zone.RadiusMeters = geometry.RadiusMeters
+		if err := tx.Model(&common.Zone{}).Where("id = ?", zone.ID).Updates(map[string]interface{}{
+			"location_latitude":  zone.Location.Latitude,
+			"location_longitude": zone.Location.Longitude,
+			"radius_meters":      zone.RadiusMeters,
+		}).Error; err != nil {
+			return err
+		}
+
+		after = zone
+		moved = true
+		return nil
+	})
+	if err != nil || !moved {
+		return false, err
+	}
+
+	mapTileCache.InvalidateZone(before)
+	mapTileCache.InvalidateZone(after)
+	return true, nil
+}
+
+// assignZoneDynamics - náhodne z dynamickej zóny spraví driftujúcu a/alebo zmenšujúcu sa
+func (h *Handler) assignZoneDynamics(zone *common.Zone) {
+	if zone.Properties == nil {
+		zone.Properties = common.JSONB{}
+	}
+
+	if rand.Float64() < MovingZoneChance {
+		zone.Properties["movement"] = ZoneMovement{
+			Type:           MovementTypeRandomWalk,
+			SpeedMPS:       RandomWalkSpeedMPS,
+			StartedAt:      time.Now().Unix(),
+			OriginLat:      zone.Location.Latitude,
+			OriginLng:      zone.Location.Longitude,
+			MaxDriftMeters: RandomWalkMaxDrift,
+			Seed:           rand.Int63(),
+		}
+	}
+
+	if zone.ExpiresAt != nil && rand.Float64() < ShrinkingZoneChance {
+		zone.Properties["shrink"] = ZoneShrink{
+			OriginalRadius: zone.RadiusMeters,
+			MinRatio:       ShrinkMinRatio,
+		}
+	}
+}
+
+func zoneMovementFromProperties(zone common.Zone) (ZoneMovement, bool) {
+	var movement ZoneMovement
+	if !decodeZoneProperty(zone, "movement", &movement) || movement.Type == "" {
+		return movement, false
+	}
+	return movement, true
+}
+
+func zoneShrinkFromProperties(zone common.Zone) (ZoneShrink, bool) {
+	var shrink ZoneShrink
+	if !decodeZoneProperty(zone, "shrink", &shrink) || shrink.OriginalRadius == 0 {
+		return shrink, false
+	}
+	return shrink, true
+}
+
+// JSONB hodnoty sú po načítaní z DB map[string]interface{}, pred uložením Go štruktúry
+func decodeZoneProperty(zone common.Zone, key string, target interface{}) bool {
+	value, exists := zone.Properties[key]
+	if !exists || value == nil {
+		return false
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(raw, target) == nil
+}
+
+// Posun bodu o distance metrov v smere bearing (rovnaká aproximácia ako generateRandomLocationInZone)
+func offsetLocation(lat, lng, distance, bearing float64) (float64, float64) {
+	angle := bearing * math.Pi / 180
+	latOffset := (distance * math.Cos(angle)) / 111000
+	lngOffset := (distance * math.Sin(angle)) / (111000 * math.Cos(lat*math.Pi/180))
+	return lat + latOffset, lng + lngOffset
+}
@@ -0,0 +1,139 @@
+package game
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"geoanomaly/internal/common"
+)
+
+func TestZoneMovement_PathLoops(t *testing.T) {
+	movement := ZoneMovement{
+		Type:      MovementTypePath,
+		SpeedMPS:  2,
+		OriginLat: 48.0,
+		OriginLng: 17.0,
+		Path:      [][2]float64{{48.0, 17.01}},
+	}
+
+	legLength := CalculateDistance(48.0, 17.0, 48.0, 17.01)
+
+	lat, lng, heading, _ := movement.positionAt(legLength / 2 / movement.SpeedMPS)
+	if d := CalculateDistance(48.0, 17.0, lat, lng); math.Abs(d-legLength/2) > 5 {
+		t.Errorf("half way: distance from origin = %.1fm; want ~%.1fm", d, legLength/2)
+	}
+	if math.Abs(heading-90) > 1 {
+		t.Errorf("half way: heading = %v; want ~90", heading)
+	}
+
+	// Po celom okruhu je zóna späť na začiatku
+	lat, lng, _, _ = movement.positionAt(2 * legLength / movement.SpeedMPS)
+	if d := CalculateDistance(48.0, 17.0, lat, lng); d > 5 {
+		t.Errorf("full loop: distance from origin = %.1fm; want ~0", d)
+	}
+}
+
+func TestZoneMovement_RandomWalkDeterministicAndBounded(t *testing.T) {
+	movement := ZoneMovement{
+		Type:           MovementTypeRandomWalk,
+		SpeedMPS:       RandomWalkSpeedMPS,
+		OriginLat:      48.0,
+		OriginLng:      17.0,
+		MaxDriftMeters: 200,
+		Seed:           42,
+	}
+
+	for _, hours := range []float64{1, 6, 24} {
+		elapsed := hours * 3600
+		lat1, lng1, _, _ := movement.positionAt(elapsed)
+		lat2, lng2, _, _ := movement.positionAt(elapsed)
+		if lat1 != lat2 || lng1 != lng2 {
+			t.Errorf("%.0fh: random walk is not deterministic", hours)
+		}
+
+		maxDistance := movement.MaxDriftMeters + RandomWalkSpeedMPS*RandomWalkSegmentSeconds
+		if d := CalculateDistance(48.0, 17.0, lat1, lng1); d > maxDistance {
+			t.Errorf("%.0fh: drifted %.0fm; want <= %.0fm", hours, d, maxDistance)
+		}
+	}
+}
+
+func TestZoneShrink_RadiusAt(t *testing.T) {
+	now := time.Now()
+	shrink := ZoneShrink{OriginalRadius: 300, MinRatio: 0.3}
+
+	fresh := now.Add(3 * time.Hour)
+	if got := shrink.radiusAt(&fresh, now); got != 300 {
+		t.Errorf("fresh zone radius = %d; want 300", got)
+	}
+
+	halfway := now.Add(ShrinkWindowMinutes / 2 * time.Minute)
+	if got := shrink.radiusAt(&halfway, now); got != 195 {
+		t.Errorf("half shrunk radius = %d; want 195", got)
+	}
+
+	expired := now.Add(-time.Minute)
+	if got := shrink.radiusAt(&expired, now); got != 90 {
+		t.Errorf("expired zone radius = %d; want 90", got)
+	}
+
+	small := ZoneShrink{OriginalRadius: 100, MinRatio: 0.3}
+	if got := small.radiusAt(&expired, now); got != int(MinZoneRadius) {
+		t.Errorf("radius should not drop below %d, got %d", int(MinZoneRadius), got)
+	}
+}
+
+func TestCurrentZoneGeometry_StaticZone(t *testing.T) {
+	zone := common.Zone{
+		RadiusMeters: 200,
+		Location:     common.Location{Latitude: 48.0, Longitude: 17.0},
+		Properties:   common.JSONB{},
+	}
+
+	geometry := CurrentZoneGeometry(zone, time.Now())
+	if geometry.SpeedMPS != 0 || geometry.Shrinking || geometry.RadiusMeters != 200 {
+		t.Errorf("static zone geometry = %+v; want unchanged", geometry)
+	}
+}
+
+func TestZoneMovement_RandomWalkCheckpointMatchesFullWalk(t *testing.T) {
+	movement := ZoneMovement{
+		Type:      MovementTypeRandomWalk,
+		SpeedMPS:  RandomWalkSpeedMPS,
+		OriginLat: 48.0,
+		OriginLng: 17.0,
+		Seed:      7,
+	}
+
+	// Prvé volanie uloží checkpoint, druhé pokračuje od neho
+	movement.positionAt(10 * 3600)
+	elapsed := 30*3600 + 120.0
+	lat, lng, _, _ := movement.positionAt(elapsed)
+
+	full := int64(elapsed / RandomWalkSegmentSeconds)
+	wantLat, wantLng := movement.walkSegments(0, full, 48.0, 17.0)
+	heading := movement.walkHeading(full, wantLat, wantLng)
+	wantLat, wantLng = offsetLocation(wantLat, wantLng, movement.SpeedMPS*120, heading)
+
+	if d := CalculateDistance(lat, lng, wantLat, wantLng); d > 0.01 {
+		t.Errorf("checkpointed walk is %.3fm off the full walk", d)
+	}
+}
+
+func TestLiveLocation(t *testing.T) {
+	stored := common.Zone{Location: common.Location{Latitude: 48.0, Longitude: 17.0}, RadiusMeters: 200}
+	live := common.Zone{Location: common.Location{Latitude: 48.001, Longitude: 17.0}, RadiusMeters: 100}
+
+	item := common.Location{Latitude: 48.0, Longitude: 17.002}
+	got := liveLocation(stored, live, item)
+	if math.Abs(got.Latitude-48.001) > 1e-9 || math.Abs(got.Longitude-17.001) > 1e-9 {
+		t.Errorf("item should move with the center and halve its offset, got %+v", got)
+	}
+
+	// Item položený na aktuálnej polohe sa po prepočte na ďalší tick objaví na tom istom mieste
+	back := storedLocation(stored, live, got)
+	if math.Abs(back.Latitude-item.Latitude) > 1e-9 || math.Abs(back.Longitude-item.Longitude) > 1e-9 {
+		t.Errorf("storedLocation should invert liveLocation, got %+v; want %+v", back, item)
+	}
+}
@@ -36,17 +36,7 @@ func (h *Handler) getExistingZonesInArea(lat, lng, radiusMeters float64) []commo
 // Filter zones by tier based on user tier
 // This function ensures that users only see zones they are allowed to enter based on their tier
 func (h *Handler) filterZonesByTier(zones []common.Zone, userTier int) []common.Zone {
-	var maxVisibleTier int
-	switch userTier {
-	case 0:
-		maxVisibleTier = 2
-	case 1, 2:
-		maxVisibleTier = 3
-	case 3, 4:
-		maxVisibleTier = 4
-	default:
-		maxVisibleTier = userTier
-	}
+	maxVisibleTier := getMaxVisibleZoneTier(userTier)
 	var visibleZones []common.Zone
 	for _, zone := range zones {
 		if zone.TierRequired <= maxVisibleTier {
@@ -57,6 +47,20 @@ func (h *Handler) filterZonesByTier(zones []common.Zone, userTier int) []common.
 	return visibleZones
 }
 
+// Najvyšší tier zóny, ktorú hráč na mape vidí (zdieľané so zoznamom zón aj s mapovými dlaždicami)
+func getMaxVisibleZoneTier(userTier int) int {
+	switch userTier {
+	case 0:
+		return 2
+	case 1, 2:
+		return 3
+	case 3, 4:
+		return 4
+	default:
+		return userTier
+	}
+}
+
 func (h *Handler) countDynamicZonesInArea(lat, lng, radiusMeters float64) int {
 	zones := h.getExistingZonesInArea(lat, lng, radiusMeters)
 	count := 0
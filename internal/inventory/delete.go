@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -61,9 +62,20 @@ func (h *Handler) DeleteItem(c *gin.Context) {
 		return
 	}
 
-	// Soft delete
+	// ✅ NEW: Item v obchode sa nedá zmazať
+	if items.InEscrow(h.db, item.ID) {
+		c.JSON(http.StatusConflict, gin.H{"error": items.ErrItemInEscrow.Error()})
+		return
+	}
+
+	// Soft delete (vybavený gear zároveň zmizne z loadoutu)
 	now := time.Now()
-	if err := h.db.Model(&item).Update("deleted_at", now).Error; err != nil {
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&item).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return items.ReleaseItem(tx, item.ID)
+	}); err != nil {
 		fmt.Printf("❌ Failed to delete item: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete item"})
 		return
@@ -1,16 +1,19 @@
 package inventory
 
 import (
-	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"time"
 
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // GET /api/v1/inventory/items
@@ -52,9 +55,9 @@ func (h *Handler) GetInventory(c *gin.Context) {
 		return
 	}
 
-	// Get items as maps first (to handle JSONB properly)
-	var rawItems []map[string]interface{}
-	if err := query.Limit(limit).Offset(offset).Order("created_at DESC").Find(&rawItems).Error; err != nil {
+	// ✅ FIX: Typované itemy + definícia z katalógu (JSONB.Scan zvláda aj string)
+	var inventory []common.InventoryItem
+	if err := query.Limit(limit).Offset(offset).Order("created_at DESC").Find(&inventory).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to fetch inventory",
 			"details": err.Error(),
@@ -68,80 +71,9 @@ func (h *Handler) GetInventory(c *gin.Context) {
 		totalPages = (totalCount + int64(limit) - 1) / int64(limit)
 	}
 
-	// Format items with image URLs
-	var formattedItems []gin.H
-	for _, rawItem := range rawItems {
-		itemData := gin.H{
-			"id":         rawItem["id"],
-			"user_id":    rawItem["user_id"],
-			"item_type":  rawItem["item_type"],
-			"item_id":    rawItem["item_id"],
-			"quantity":   rawItem["quantity"],
-			"created_at": rawItem["created_at"],
-		}
-
-		// ✅ FIX: Handle properties - they might be string in DB
-		var properties map[string]interface{}
-
-		switch props := rawItem["properties"].(type) {
-		case string:
-			// Properties are stored as JSON string, parse them
-			if err := json.Unmarshal([]byte(props), &properties); err != nil {
-				log.Printf("Failed to parse properties string: %v", err)
-				properties = make(map[string]interface{})
-			}
-		case map[string]interface{}:
-			// Properties are already a map
-			properties = props
-		default:
-			log.Printf("Unexpected properties type: %T", rawItem["properties"])
-			properties = make(map[string]interface{})
-		}
-
-		// Store parsed properties
-		itemData["properties"] = properties
-
-		// Extract common properties
-		if name, ok := properties["name"].(string); ok {
-			itemData["name"] = name
-		}
-		if desc, ok := properties["description"].(string); ok {
-			itemData["description"] = desc
-		}
-		if rarity, ok := properties["rarity"].(string); ok {
-			itemData["rarity"] = rarity
-		}
-		if biome, ok := properties["biome"].(string); ok {
-			itemData["biome"] = biome
-		}
-
-		// Add image URL based on item type
-		itemTypeStr, _ := rawItem["item_type"].(string)
-		if itemTypeStr == "artifact" {
-			if artifactType, exists := properties["type"].(string); exists {
-				itemData["image_url"] = fmt.Sprintf("/api/v1/media/artifact/%s", artifactType)
-			}
-		} else if itemTypeStr == "gear" {
-			// For gear, we might use a different pattern
-			if gearType, exists := properties["type"].(string); exists {
-				itemData["image_url"] = fmt.Sprintf("/api/v1/media/gear/%s", gearType)
-			}
-
-			// Add equipped status for gear
-			if equipped, ok := properties["equipped"].(bool); ok {
-				itemData["equipped"] = equipped
-			}
-			if slot, ok := properties["slot"].(string); ok {
-				itemData["slot"] = slot
-			}
-		}
-
-		// Add favorite status
-		if favorite, ok := properties["favorite"].(bool); ok {
-			itemData["favorite"] = favorite
-		}
-
-		formattedItems = append(formattedItems, itemData)
+	formattedItems := make([]gin.H, 0, len(inventory))
+	for _, item := range inventory {
+		formattedItems = append(formattedItems, formatItem(item, items.Resolve(h.db, item)))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -181,28 +113,9 @@ func (h *Handler) GetItemDetail(c *gin.Context) {
 		return
 	}
 
-	// ✅ FIX: Use raw SQL query to avoid GORM model issues
-	var id, userIDResult, itemType, itemIDResult string
-	var quantity int
-	var propertiesJSON string
-	var createdAt, updatedAt time.Time
-	var deletedAt sql.NullTime
-
-	query := `
-		SELECT 
-			id, user_id, item_type, item_id, quantity, 
-			properties::text, created_at, updated_at, deleted_at
-		FROM inventory_items 
-		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
-	`
-
-	err = h.db.Raw(query, itemUUID, userID).Row().Scan(
-		&id, &userIDResult, &itemType, &itemIDResult,
-		&quantity, &propertiesJSON, &createdAt, &updatedAt, &deletedAt,
-	)
-
-	if err != nil {
-		if err == sql.ErrNoRows {
+	var item common.InventoryItem
+	if err := h.db.Where("id = ? AND user_id = ? AND deleted_at IS NULL", itemUUID, userID).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
 			return
 		}
@@ -214,51 +127,67 @@ func (h *Handler) GetItemDetail(c *gin.Context) {
 		return
 	}
 
-	// Parse properties JSON
-	var properties map[string]interface{}
-	if err := json.Unmarshal([]byte(propertiesJSON), &properties); err != nil {
-		log.Printf("Failed to parse properties JSON: %v", err)
-		log.Printf("Raw properties: %s", propertiesJSON)
-		properties = make(map[string]interface{})
-	}
+	def := items.Resolve(h.db, item)
+	response := formatItem(item, def)
+	response["updated_at"] = item.UpdatedAt
+	response["definition"] = def
 
-	// Build response
-	response := gin.H{
-		"id":         id,
-		"user_id":    userIDResult,
-		"item_type":  itemType,
-		"item_id":    itemIDResult,
-		"quantity":   quantity,
-		"properties": properties,
-		"created_at": createdAt,
-		"updated_at": updatedAt,
-	}
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"item":      response,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
 
-	// Extract common fields from properties
-	if name, ok := properties["name"].(string); ok {
-		response["name"] = name
-	}
-	if desc, ok := properties["description"].(string); ok {
-		response["description"] = desc
+// formatItem - zobrazenie itemu pre klienta; meno, slot a ikona idú z katalógu
+func formatItem(item common.InventoryItem, def common.ItemDefinition) gin.H {
+	properties := item.Properties
+	if properties == nil {
+		properties = common.JSONB{}
+	}
+
+	name := def.Name
+	if custom, ok := properties["name"].(string); ok && custom != "" {
+		name = custom
+	}
+
+	itemData := gin.H{
+		"id":             item.ID,
+		"user_id":        item.UserID,
+		"item_type":      item.ItemType,
+		"item_id":        item.ItemID,
+		"quantity":       item.Quantity,
+		"created_at":     item.CreatedAt,
+		"properties":     properties,
+		"definition_key": def.Key,
+		"category":       def.Category,
+		"name":           name,
+		"description":    def.Description,
+		"icon_key":       def.IconKey,
+		"stack_size":     def.StackSize,
+		"image_url":      fmt.Sprintf("/api/v1/media/%s/%s", item.ItemType, def.Type),
+	}
+	if desc, ok := properties["description"].(string); ok && desc != "" {
+		itemData["description"] = desc
 	}
 	if rarity, ok := properties["rarity"].(string); ok {
-		response["rarity"] = rarity
+		itemData["rarity"] = rarity
 	}
-
-	// Add image URL based on item type
-	if itemType == "artifact" {
-		if artifactType, exists := properties["type"].(string); exists {
-			response["image_url"] = fmt.Sprintf("/api/v1/media/artifact/%s", artifactType)
-		}
-	} else if itemType == "gear" {
-		if gearType, exists := properties["type"].(string); exists {
-			response["image_url"] = fmt.Sprintf("/api/v1/media/gear/%s", gearType)
-		}
+	if biome, ok := properties["biome"].(string); ok {
+		itemData["biome"] = biome
+	}
+	if def.Slot != "" {
+		itemData["slot"] = def.Slot
+		itemData["stats"] = def.Stats
+		equipped, _ := properties["equipped"].(bool)
+		itemData["equipped"] = equipped
+	}
+	if durability := items.DurabilityOf(item, def); durability != nil {
+		itemData["durability"] = durability
+	}
+	if favorite, ok := properties["favorite"].(bool); ok {
+		itemData["favorite"] = favorite
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success":   true,
-		"item":      response,
-		"timestamp": time.Now().Format(time.RFC3339),
-	})
+	return itemData
 }
@@ -0,0 +1,13 @@
+package inventory
+
+import (
+	"geoanomaly/internal/items"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /api/v1/inventory/:id/repair
+// ✅ NEW: Oprava gearu za artefakty rieši items.Repair
+func (h *Handler) RepairItem(c *gin.Context) {
+	items.NewHandler(h.db).RepairItem(c)
+}
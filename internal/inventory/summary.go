@@ -2,10 +2,14 @@ package inventory
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
+	"geoanomaly/internal/items"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // GET /api/v1/inventory/summary
@@ -83,6 +87,14 @@ func (h *Handler) GetInventorySummary(c *gin.Context) {
 
 	fmt.Printf("✅ Biome stats: %v\n", biomeStats)
 
+	// ✅ NEW: Obsadené a voľné miesta (tier + gear), váha a overflow
+	capacity, err := items.CapacityOf(h.db, userID.(uuid.UUID))
+	if err != nil {
+		log.Printf("❌ Failed to compute inventory capacity for user %v: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute inventory capacity"})
+		return
+	}
+
 	// ✅ CRITICAL FIX: Return JSON object, NOT string!
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -92,6 +104,7 @@ func (h *Handler) GetInventorySummary(c *gin.Context) {
 			"total_gear":      gearCount,
 			"by_rarity":       rarityStats,
 			"by_biome":        biomeStats,
+			"capacity":        capacity,
 		},
 		"message":   "Inventory summary retrieved successfully",
 		"timestamp": time.Now().Format(time.RFC3339),
@@ -0,0 +1,62 @@
+package items
+
+import (
+	"log"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ActiveBuffs - neexpirované dočasné efekty hráča
+func ActiveBuffs(db *gorm.DB, userID uuid.UUID) []common.PlayerBuff {
+	var buffs []common.PlayerBuff
+	db.Where("user_id = ? AND expires_at > ?", userID, time.Now()).Order("expires_at").Find(&buffs)
+	return buffs
+}
+
+// BuffValue - najsilnejší aktívny efekt daného druhu (0 = žiadny)
+func BuffValue(db *gorm.DB, userID uuid.UUID, effect string) float64 {
+	var value float64
+	db.Model(&common.PlayerBuff{}).
+		Where("user_id = ? AND effect = ? AND expires_at > ?", userID, effect, time.Now()).
+		Select("COALESCE(MAX(value), 0)").Scan(&value)
+	return value
+}
+
+// HazardResist - aktívna odolnosť voči danému typu hazardu (0..1)
+func HazardResist(db *gorm.DB, userID uuid.UUID, hazardType string) float64 {
+	resist := 0.0
+	for _, buff := range ActiveBuffs(db, userID) {
+		if buff.Effect != common.EffectHazardResist || !coversHazard(buff.Properties, hazardType) {
+			continue
+		}
+		resist = max(resist, buff.Value)
+	}
+	return min(resist, 1)
+}
+
+func coversHazard(properties common.JSONB, hazardType string) bool {
+	hazards, ok := properties["hazards"].([]interface{})
+	if !ok || len(hazards) == 0 {
+		return true
+	}
+	for _, hazard := range hazards {
+		if hazard == hazardType {
+			return true
+		}
+	}
+	return false
+}
+
+// CleanupExpiredBuffs - zmaže expirované efekty (volá scheduler)
+func CleanupExpiredBuffs(db *gorm.DB) int64 {
+	result := db.Where("expires_at <= ?", time.Now()).Delete(&common.PlayerBuff{})
+	if result.Error != nil {
+		log.Printf("❌ Failed to cleanup expired buffs: %v", result.Error)
+		return 0
+	}
+	return result.RowsAffected
+}
@@ -0,0 +1,351 @@
+package items
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInventoryFull    = errors.New("inventory is full")
+	ErrOverweight       = errors.New("inventory weight limit reached")
+	ErrOverflowFull     = errors.New("overflow stash is full")
+	ErrOverflowNotFound = errors.New("overflow item not found or expired")
+)
+
+const (
+	DefaultInventorySlots = 50 // tier 0, ak riadok v tier_definitions chýba
+	MaxOverflowItems      = 10
+	OverflowTTL           = 24 * time.Hour
+)
+
+// Capacity - kapacita inventára; stack zaberá jedno miesto bez ohľadu na Quantity
+type Capacity struct {
+	Slots     int     `json:"slots"`
+	TierSlots int     `json:"tier_slots"` // tier_definitions.inventory_slots
+	GearSlots int     `json:"gear_slots"` // inventory_slots z vybaveného gearu
+	UsedSlots int     `json:"used_slots"`
+	FreeSlots int     `json:"free_slots"`
+	MaxWeight float64 `json:"max_weight"` // 0 = bez limitu
+	Weight    float64 `json:"weight"`
+	Overflow  int64   `json:"overflow"` // itemy čakajúce v overflow stash
+}
+
+// Placement - kam sa zozbieraný item uložil
+type Placement struct {
+	Item     *common.InventoryItem `json:"item,omitempty"`
+	Stacked  bool                  `json:"stacked"` // pripočítaný k existujúcemu stacku
+	Overflow *common.OverflowItem  `json:"overflow,omitempty"`
+	Capacity Capacity              `json:"capacity"`
+}
+
+// CapacityOf - sloty podľa tieru hráča (+gear), obsadené miesta a váha
+func CapacityOf(db *gorm.DB, userID uuid.UUID) (Capacity, error) {
+	var user common.User
+	if err := db.Select("id, tier, level").First(&user, "id = ?", userID).Error; err != nil {
+		return Capacity{}, err
+	}
+
+	var tier struct {
+		InventorySlots int
+		MaxWeight      float64
+	}
+	if err := db.Raw("SELECT inventory_slots, COALESCE(max_weight, 0) AS max_weight FROM tier_definitions WHERE tier_level = ?",
+		user.ProgressionTier()).Scan(&tier).Error; err != nil {
+		return Capacity{}, err
+	}
+	if tier.InventorySlots <= 0 {
+		tier.InventorySlots = DefaultInventorySlots
+	}
+
+	var usage struct {
+		UsedSlots int
+		Weight    float64
+	}
+	if err := db.Raw(`
+		SELECT COUNT(*) AS used_slots, COALESCE(SUM(i.quantity * COALESCE(d.weight, 0)), 0) AS weight
+		FROM inventory_items i
+		LEFT JOIN item_definitions d ON d.key = i.definition_key AND d.deleted_at IS NULL
+		WHERE i.user_id = ? AND i.deleted_at IS NULL`, userID).Scan(&usage).Error; err != nil {
+		return Capacity{}, err
+	}
+
+	capacity := Capacity{
+		TierSlots: tier.InventorySlots,
+		GearSlots: Stats(db, userID).InventorySlots,
+		UsedSlots: usage.UsedSlots,
+		MaxWeight: tier.MaxWeight,
+		Weight:    usage.Weight,
+	}
+	capacity.Slots = capacity.TierSlots + capacity.GearSlots
+	capacity.FreeSlots = max(0, capacity.Slots-capacity.UsedSlots)
+	db.Model(&common.OverflowItem{}).Where("user_id = ? AND expires_at > ?", userID, time.Now()).Count(&capacity.Overflow)
+	return capacity, nil
+}
+
+// CapacityCode - kód pre klienta pri chybe kapacity ("" = iná chyba)
+func CapacityCode(err error) string {
+	switch {
+	case errors.Is(err, ErrInventoryFull):
+		return "INVENTORY_FULL"
+	case errors.Is(err, ErrOverweight):
+		return "INVENTORY_OVERWEIGHT"
+	}
+	return ""
+}
+
+// fits - zmestí sa toľko nových riadkov a váhy navyše
+func (c Capacity) fits(slots int, weight float64) error {
+	if slots > 0 && c.UsedSlots+slots > c.Slots {
+		return ErrInventoryFull
+	}
+	if c.MaxWeight > 0 && weight > 0 && c.Weight+weight > c.MaxWeight {
+		return ErrOverweight
+	}
+	return nil
+}
+
+// Store - pridá item do inventára s kontrolou kapacity. Stackovateľný item sa pripočíta k stacku
+// rovnakého typu a rarity (nové miesto nezaberie), inak vznikne nový riadok.
+func Store(tx *gorm.DB, item *common.InventoryItem) (bool, error) {
+	// Zámok hráča serializuje súbežné zbery, aby dva naraz neprešli cez posledné voľné miesto
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		First(&common.User{}, "id = ?", item.UserID).Error; err != nil {
+		return false, err
+	}
+
+	def := Resolve(tx, *item)
+	if item.DefinitionKey == "" {
+		item.DefinitionKey = def.Key
+	}
+	capacity, err := CapacityOf(tx, item.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	weight := def.Weight * float64(max(1, item.Quantity))
+	stack, err := findStack(tx, *item, def)
+	if err != nil {
+		return false, err
+	}
+	if stack != nil {
+		if err := capacity.fits(0, weight); err != nil {
+			return false, err
+		}
+		if err := addToStack(tx, stack, item.Quantity); err != nil {
+			return false, err
+		}
+		*item = *stack
+		return true, nil
+	}
+
+	if err := capacity.fits(1, weight); err != nil {
+		return false, err
+	}
+	return false, tx.Create(item).Error
+}
+
+// Collect - uloží zozbieraný item zo zóny; pri plnom inventári ho odloží do overflow stash na OverflowTTL.
+// Chybu kapacity vráti, len ak je plný aj overflow. Každý zber sa zapíše do item_collects.
+func Collect(db *gorm.DB, item common.InventoryItem, zoneID uuid.UUID) (*Placement, error) {
+	placement := &Placement{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&common.ItemCollect{
+			UserID:        item.UserID,
+			ZoneID:        zoneID,
+			ItemType:      item.ItemType,
+			ItemID:        item.ItemID,
+			DefinitionKey: item.DefinitionKey,
+		}).Error; err != nil {
+			return err
+		}
+
+		stacked, err := Store(tx, &item)
+		if err == nil {
+			placement.Item, placement.Stacked = &item, stacked
+			return nil
+		}
+		if CapacityCode(err) == "" {
+			return err
+		}
+
+		overflow, stashErr := stash(tx, item, time.Now())
+		if errors.Is(stashErr, ErrOverflowFull) {
+			return err
+		}
+		placement.Overflow = overflow
+		return stashErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	placement.Capacity, _ = CapacityOf(db, item.UserID)
+	return placement, nil
+}
+
+// OverflowItems - itemy hráča čakajúce v overflow, najskôr expirujúce prvé
+func OverflowItems(db *gorm.DB, userID uuid.UUID) ([]common.OverflowItem, error) {
+	var stashed []common.OverflowItem
+	err := db.Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("expires_at").Find(&stashed).Error
+	return stashed, err
+}
+
+// ClaimOverflow - presunie item z overflow do inventára, ak je už miesto
+func ClaimOverflow(db *gorm.DB, userID, overflowID uuid.UUID) (*Placement, error) {
+	placement := &Placement{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var stashed common.OverflowItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ? AND expires_at > ?", overflowID, userID, time.Now()).
+			First(&stashed).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOverflowNotFound
+			}
+			return err
+		}
+
+		item := common.InventoryItem{
+			UserID:        userID,
+			ItemType:      stashed.ItemType,
+			ItemID:        stashed.ItemID,
+			Quantity:      stashed.Quantity,
+			Properties:    stashed.Properties,
+			DefinitionKey: stashed.DefinitionKey,
+		}
+		stacked, err := Store(tx, &item)
+		if err != nil {
+			return err
+		}
+		placement.Item, placement.Stacked = &item, stacked
+		return tx.Delete(&stashed).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	placement.Capacity, _ = CapacityOf(db, userID)
+	return placement, nil
+}
+
+// ExpireOverflow - zmaže itemy, ktoré si hráči z overflow nevyzdvihli včas
+func ExpireOverflow(db *gorm.DB) int64 {
+	result := db.Where("expires_at <= ?", time.Now()).Delete(&common.OverflowItem{})
+	if result.Error != nil {
+		log.Printf("❌ Failed to expire overflow items: %v", result.Error)
+		return 0
+	}
+	return result.RowsAffected
+}
+
+func stash(tx *gorm.DB, item common.InventoryItem, now time.Time) (*common.OverflowItem, error) {
+	var count int64
+	tx.Model(&common.OverflowItem{}).Where("user_id = ? AND expires_at > ?", item.UserID, now).Count(&count)
+	if count >= MaxOverflowItems {
+		return nil, ErrOverflowFull
+	}
+
+	stashed := common.OverflowItem{
+		ExpiresAt:     now.Add(OverflowTTL),
+		UserID:        item.UserID,
+		ItemType:      item.ItemType,
+		ItemID:        item.ItemID,
+		DefinitionKey: item.DefinitionKey,
+		Quantity:      max(1, item.Quantity),
+		Properties:    item.Properties,
+	}
+	if err := tx.Create(&stashed).Error; err != nil {
+		return nil, err
+	}
+	return &stashed, nil
+}
+
+// Properties, v ktorých sa kusy jedného stacku musia zhodovať - od nich závisí cena u obchodníka
+// a rozobratie (stack drží properties prvého kusu). Zóna pôvodu nie je súčasťou stacku -
+// história zberov po zónach je v item_collects.
+var stackKeyProperties = []string{"rarity", "original_rarity", "biome", "zone_tier"}
+
+// stackKey - hodnoty stackKeyProperties ako text (ako ich vráti properties->>'key'; chýbajúca = "")
+func stackKey(item common.InventoryItem) map[string]string {
+	key := make(map[string]string, len(stackKeyProperties))
+	for _, property := range stackKeyProperties {
+		if value, ok := item.Properties[property]; ok && value != nil {
+			key[property] = fmt.Sprint(value)
+		} else {
+			key[property] = ""
+		}
+	}
+	return key
+}
+
+// findStack - stack hráča, ku ktorému sa dá item pripočítať: rovnaký typ a stackKey, voľné miesto v stacku,
+// nie je v obchode/na trhu. Nestackovateľné typy (gear) vždy zaberú nový riadok.
+func findStack(tx *gorm.DB, item common.InventoryItem, def common.ItemDefinition) (*common.InventoryItem, error) {
+	quantity := max(1, item.Quantity)
+	if def.StackSize <= 1 || quantity >= def.StackSize {
+		return nil, nil
+	}
+
+	var candidates []common.InventoryItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND definition_key = ? AND deleted_at IS NULL", item.UserID, def.Key).
+		Where("quantity + ? <= ?", quantity, def.StackSize).
+		Where(NotInEscrow).
+		Order("quantity DESC").
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	return pickStack(candidates, item, def), nil
+}
+
+// pickStack - prvý stack so zhodným stackKey, do ktorého sa item ešte zmestí (nil = nový riadok)
+func pickStack(candidates []common.InventoryItem, item common.InventoryItem, def common.ItemDefinition) *common.InventoryItem {
+	quantity := max(1, item.Quantity)
+	if def.StackSize <= 1 || quantity >= def.StackSize {
+		return nil
+	}
+
+	key := stackKey(item)
+	for i := range candidates {
+		if candidates[i].Quantity+quantity <= def.StackSize && maps.Equal(stackKey(candidates[i]), key) {
+			return &candidates[i]
+		}
+	}
+	return nil
+}
+
+func addToStack(tx *gorm.DB, stack *common.InventoryItem, quantity int) error {
+	stack.Quantity += max(1, quantity)
+	return tx.Model(stack).Update("quantity", stack.Quantity).Error
+}
+
+// BackfillCollects - prvé spustenie s item_collects prevezme zbery z inventára (properties.collected_from),
+// aby sa klesajúci výnos zóny po nasadení nevynuloval
+func BackfillCollects(db *gorm.DB) error {
+	var existing int64
+	if err := db.Model(&common.ItemCollect{}).Count(&existing).Error; err != nil || existing > 0 {
+		return err
+	}
+
+	result := db.Exec(`
+		INSERT INTO item_collects (user_id, zone_id, item_type, item_id, definition_key, created_at)
+		SELECT user_id, (properties->>'collected_from')::uuid, item_type, item_id, COALESCE(definition_key, ''), created_at
+		FROM inventory_items
+		WHERE properties->>'collected_from' ~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("🎒 Backfilled %d collects into item_collects", result.RowsAffected)
+	}
+	return nil
+}
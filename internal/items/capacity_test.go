@@ -0,0 +1,102 @@
+package items
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"geoanomaly/internal/common"
+)
+
+func TestCapacityFits(t *testing.T) {
+	full := Capacity{Slots: 50, UsedSlots: 50}
+	if err := full.fits(1, 0); !errors.Is(err, ErrInventoryFull) {
+		t.Errorf("new row into full inventory: got %v, want ErrInventoryFull", err)
+	}
+	if err := full.fits(0, 0); err != nil {
+		t.Errorf("merging into an existing stack needs no slot, got %v", err)
+	}
+
+	heavy := Capacity{Slots: 50, UsedSlots: 10, MaxWeight: 100, Weight: 95}
+	if err := heavy.fits(1, 10); !errors.Is(err, ErrOverweight) {
+		t.Errorf("over weight limit: got %v, want ErrOverweight", err)
+	}
+	if err := heavy.fits(1, 5); err != nil {
+		t.Errorf("exactly at weight limit should fit, got %v", err)
+	}
+
+	unlimited := Capacity{Slots: 50, UsedSlots: 10, Weight: 1000}
+	if err := unlimited.fits(1, 500); err != nil {
+		t.Errorf("MaxWeight 0 means no weight limit, got %v", err)
+	}
+}
+
+func TestCapacityCode(t *testing.T) {
+	if code := CapacityCode(fmt.Errorf("collect: %w", ErrInventoryFull)); code != "INVENTORY_FULL" {
+		t.Errorf("wrapped ErrInventoryFull code = %q", code)
+	}
+	if code := CapacityCode(ErrOverweight); code != "INVENTORY_OVERWEIGHT" {
+		t.Errorf("ErrOverweight code = %q", code)
+	}
+	if code := CapacityCode(ErrItemNotFound); code != "" {
+		t.Errorf("unrelated error code = %q, want empty", code)
+	}
+}
+
+func TestStackKey(t *testing.T) {
+	fresh := common.InventoryItem{Properties: common.JSONB{
+		"rarity": "rare", "biome": "forest", "zone_tier": 2, "collected_from": "zone-a", "collected_at": 1,
+	}}
+	// Properties načítané z DB majú čísla ako float64
+	stored := common.InventoryItem{Properties: common.JSONB{
+		"rarity": "rare", "biome": "forest", "zone_tier": float64(2), "collected_from": "zone-a", "collected_at": float64(99),
+	}}
+	if !reflect.DeepEqual(stackKey(fresh), stackKey(stored)) {
+		t.Errorf("same origin must stack: %v vs %v", stackKey(fresh), stackKey(stored))
+	}
+
+	otherTier := common.InventoryItem{Properties: common.JSONB{"rarity": "rare", "biome": "forest", "zone_tier": 4, "collected_from": "zone-a"}}
+	if reflect.DeepEqual(stackKey(fresh), stackKey(otherTier)) {
+		t.Error("pickups from different zone tiers must not stack (vendor price depends on zone_tier)")
+	}
+	if key := stackKey(common.InventoryItem{Properties: common.JSONB{}}); key["zone_tier"] != "" || key["rarity"] != "" {
+		t.Errorf("missing properties must match empty strings, got %v", key)
+	}
+}
+
+func TestPickStackMergesPickupsFromDifferentZones(t *testing.T) {
+	def := common.ItemDefinition{Key: "artifact:mineral_ore", Category: common.ItemCategoryArtifact, StackSize: 20}
+	pickup := func(zone string) common.InventoryItem {
+		return common.InventoryItem{Quantity: 1, Properties: common.JSONB{
+			"rarity": "rare", "biome": "mountain", "zone_tier": 2, "collected_from": zone,
+		}}
+	}
+
+	// Rovnaký postup ako Store: zhodný stack sa navýši, inak vznikne nový riadok
+	var rows []common.InventoryItem
+	for _, item := range []common.InventoryItem{pickup("zone-a"), pickup("zone-b")} {
+		if stack := pickStack(rows, item, def); stack != nil {
+			stack.Quantity += item.Quantity
+			continue
+		}
+		rows = append(rows, item)
+	}
+	if len(rows) != 1 || rows[0].Quantity != 2 {
+		t.Fatalf("pickups from two zones = %d rows (%+v), want one row with quantity 2", len(rows), rows)
+	}
+
+	full := []common.InventoryItem{{Quantity: 20, Properties: pickup("zone-a").Properties}}
+	if pickStack(full, pickup("zone-b"), def) != nil {
+		t.Error("full stack must not take another piece")
+	}
+	epic := pickup("zone-a")
+	epic.Properties["rarity"] = "epic"
+	if pickStack(rows, epic, def) != nil {
+		t.Error("different rarity must not stack")
+	}
+	gear := common.ItemDefinition{Key: "gear:gas_mask", Category: common.ItemCategoryGear, StackSize: 1}
+	if pickStack(rows, pickup("zone-a"), gear) != nil {
+		t.Error("non-stackable items always take a new row")
+	}
+}
@@ -0,0 +1,123 @@
+package items
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"gorm.io/gorm"
+)
+
+const catalogTTL = 5 * time.Minute
+
+type catalogCache struct {
+	mu          sync.RWMutex
+	definitions map[string]common.ItemDefinition
+	loadedAt    time.Time
+}
+
+var catalog = &catalogCache{}
+
+// Invalidate - po úprave item_definitions sa katalóg načíta znova
+func Invalidate() {
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+	catalog.definitions = nil
+}
+
+func (cc *catalogCache) get(db *gorm.DB) map[string]common.ItemDefinition {
+	cc.mu.RLock()
+	definitions, loadedAt := cc.definitions, cc.loadedAt
+	cc.mu.RUnlock()
+
+	if definitions != nil && time.Since(loadedAt) < catalogTTL {
+		return definitions
+	}
+
+	var rows []common.ItemDefinition
+	if err := db.Where("is_active = true AND deleted_at IS NULL").Find(&rows).Error; err != nil {
+		log.Printf("❌ Failed to load item catalog: %v", err)
+		if definitions != nil {
+			return definitions
+		}
+		return defaultCatalog()
+	}
+
+	fresh := make(map[string]common.ItemDefinition, len(rows))
+	for _, row := range rows {
+		fresh[row.Key] = row
+	}
+
+	cc.mu.Lock()
+	cc.definitions = fresh
+	cc.loadedAt = time.Now()
+	cc.mu.Unlock()
+
+	return fresh
+}
+
+func defaultCatalog() map[string]common.ItemDefinition {
+	definitions := make(map[string]common.ItemDefinition, len(defaultDefinitions))
+	for _, def := range defaultDefinitions {
+		definitions[def.Key] = def
+	}
+	return definitions
+}
+
+// Lookup - definícia podľa kľúča
+func Lookup(db *gorm.DB, key string) (common.ItemDefinition, bool) {
+	def, ok := catalog.get(db)[key]
+	return def, ok
+}
+
+// Resolve - definícia itemu z inventára; pre neznámy typ vráti generickú (nikdy nie prázdnu)
+func Resolve(db *gorm.DB, item common.InventoryItem) common.ItemDefinition {
+	key := item.DefinitionKey
+	if key == "" {
+		key = common.ItemDefinitionKey(item.ItemType, item.Properties)
+	}
+	if def, ok := Lookup(db, key); ok {
+		return def
+	}
+	return fallbackDefinition(key, item)
+}
+
+// Attach - doplní Definition všetkým itemom
+func Attach(db *gorm.DB, inventory []common.InventoryItem) {
+	for i := range inventory {
+		def := Resolve(db, inventory[i])
+		inventory[i].Definition = &def
+		inventory[i].Durability = DurabilityOf(inventory[i], def)
+	}
+}
+
+func fallbackDefinition(key string, item common.InventoryItem) common.ItemDefinition {
+	typ := common.ItemTypeMisc
+	if _, t, ok := strings.Cut(key, ":"); ok && t != "" {
+		typ = t
+	}
+	name, _ := item.Properties["name"].(string)
+	if name == "" {
+		name = typ
+	}
+
+	stackSize := gearStackSize
+	if item.ItemType == common.ItemCategoryArtifact {
+		stackSize = artifactStackSize
+	}
+
+	return common.ItemDefinition{
+		Key:       key,
+		ItemType:  item.ItemType,
+		Type:      typ,
+		Category:  item.ItemType,
+		Name:      name,
+		Stats:     common.JSONB{},
+		StackSize: stackSize,
+		IconKey:   typ,
+		IsActive:  true,
+	}
+}
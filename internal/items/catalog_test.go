@@ -0,0 +1,64 @@
+package items
+
+import (
+	"testing"
+
+	"geoanomaly/internal/common"
+)
+
+func TestDefaultDefinitionsAreTyped(t *testing.T) {
+	slots := map[string]bool{
+		common.SlotHead: true, common.SlotBody: true, common.SlotHands: true,
+		common.SlotFeet: true, common.SlotTool: true, common.SlotDetector: true,
+	}
+
+	seen := make(map[string]bool, len(defaultDefinitions))
+	for _, def := range defaultDefinitions {
+		if seen[def.Key] {
+			t.Errorf("duplicate definition key %s", def.Key)
+		}
+		seen[def.Key] = true
+
+		if want := common.ItemDefinitionKey(def.ItemType, common.JSONB{"type": def.Type}); def.Key != want {
+			t.Errorf("definition key %s, want %s", def.Key, want)
+		}
+		if def.StackSize < 1 {
+			t.Errorf("%s: stack size %d", def.Key, def.StackSize)
+		}
+		if def.Category == common.ItemCategoryGear && def.Type != common.ItemTypeMisc && !slots[def.Slot] {
+			t.Errorf("%s: gear without valid slot %q", def.Key, def.Slot)
+		}
+		if def.Category != common.ItemCategoryGear && def.Slot != "" {
+			t.Errorf("%s: %s must not have a slot", def.Key, def.Category)
+		}
+	}
+
+	// Artefakt a gear s rovnakým typom sú rôzne definície
+	if !seen["artifact:hazmat_suit"] || !seen["gear:hazmat_suit"] {
+		t.Error("hazmat_suit must exist as both artifact and gear")
+	}
+}
+
+func TestFallbackDefinition(t *testing.T) {
+	item := common.InventoryItem{
+		ItemType:   "artifact",
+		Properties: common.JSONB{"type": "Winter_Relic", "name": "Winter Relic"},
+	}
+
+	key := common.ItemDefinitionKey(item.ItemType, item.Properties)
+	if key != "artifact:winter_relic" {
+		t.Fatalf("key = %s", key)
+	}
+
+	def := fallbackDefinition(key, item)
+	if def.Type != "winter_relic" || def.Name != "Winter Relic" || def.StackSize != artifactStackSize {
+		t.Errorf("unexpected fallback %+v", def)
+	}
+	if def.Equippable() {
+		t.Error("fallback artifact must not be equippable")
+	}
+
+	if got := common.ItemDefinitionKey("gear", common.JSONB{}); got != "gear:misc" {
+		t.Errorf("key without type = %s", got)
+	}
+}
@@ -0,0 +1,273 @@
+package items
+
+import (
+	"log"
+
+	"geoanomaly/internal/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Predvolené veľkosti stackov podľa kategórie
+const (
+	artifactStackSize   = 50
+	gearStackSize       = 1
+	consumableStackSize = 10
+)
+
+// Predvolený katalóg - typy artefaktov a gearu z biome templates.
+// Zapíše sa do item_definitions, ak tam ešte nie je; úpravy (staty, sloty) sa robia priamo v DB.
+var defaultDefinitions = []common.ItemDefinition{
+	// Forest
+	artifact("mushroom_sample", "Mutant Mushroom Sample", "A glowing fungus that grows only near anomalies"),
+	artifact("tree_resin", "Amber Tree Resin", "Hardened resin with something trapped inside"),
+	artifact("animal_bones", "Predator Bones", "Remains of a mutated forest predator"),
+	artifact("herbal_extract", "Healing Herb Extract", "Concentrated extract of anomalous herbs"),
+	artifact("old_coin", "Old Coin", "A coin from before the anomaly appeared"),
+	artifact("dewdrop_pearl", "Dewdrop Pearl", "A pearl that forms from morning dew in anomalous groves"),
+	gear("hunting_knife", "Survival Hunting Knife", common.SlotTool, "Keeps wild animals at bay",
+		common.JSONB{"xp_bonus": 0.03, "resist": map[string]interface{}{"wild_animals": 0.4}}),
+	gear("leather_boots", "Leather Combat Boots", common.SlotFeet, "Sturdy boots with good grip",
+		common.JSONB{"armor": 2, "resist": map[string]interface{}{"slippery_terrain": 0.3}}),
+	gear("wooden_bow", "Wooden Hunting Bow", common.SlotTool, "Lets you deal with predators from a distance",
+		common.JSONB{"resist": map[string]interface{}{"wild_animals": 0.6}}),
+
+	// Mountain
+	artifact("mineral_ore", "Rare Mineral Ore", "Ore with an unusual crystalline structure"),
+	artifact("crystal_shard", "Energy Crystal Shard", "A shard humming with stored energy"),
+	artifact("stone_tablet", "Ancient Stone Tablet", "Carved tablet with unreadable symbols"),
+	artifact("mountain_herb", "Alpine Medicinal Herb", "A rare herb growing above the tree line"),
+	artifact("ice_crystal", "Frozen Ice Crystal", "Ice that never melts"),
+	gear("climbing_gear", "Mountain Climbing Gear", common.SlotHands, "Ropes and grips for unstable slopes",
+		common.JSONB{"collect_radius": 2, "resist": map[string]interface{}{"unstable_terrain": 0.6, "altitude_sickness": 0.2}}),
+	gear("winter_coat", "Insulated Winter Coat", common.SlotBody, "Protects against freezing weather",
+		common.JSONB{"armor": 3, "inventory_slots": 5, "resist": map[string]interface{}{"cold_weather": 0.7}}),
+	gear("pickaxe", "Mining Pickaxe", common.SlotTool, "Helps to keep your footing on loose rock",
+		common.JSONB{"xp_bonus": 0.05, "resist": map[string]interface{}{"unstable_terrain": 0.3}}),
+
+	// Industrial
+	artifact("steel_ingot", "Steel Ingot", "High quality steel from an abandoned mill"),
+	artifact("chemical_sample", "Unknown Chemical Sample", "An unlabeled vial of something reactive"),
+	artifact("machinery_parts", "Industrial Machinery Parts", "Salvaged parts of heavy machinery"),
+	artifact("electronic_component", "Advanced Electronic Component", "A circuit board that still works"),
+	artifact("toxic_waste", "Toxic Waste Container", "Sealed container of industrial waste"),
+	artifact("rusty_gear", "Rusty Gear Relic", "A cog from a long stopped machine"),
+	gear("hard_hat", "Industrial Hard Hat", common.SlotHead, "Protects against falling debris",
+		common.JSONB{"armor": 3, "resist": map[string]interface{}{"unstable_buildings": 0.5, "structural_damage": 0.3}}),
+	gear("safety_gloves", "Chemical Safety Gloves", common.SlotHands, "Resistant to acids and burns",
+		common.JSONB{"resist": map[string]interface{}{"chemical_burns": 0.3, "corrosive_damage": 0.3}}),
+	gear("welding_mask", "Protective Welding Mask", common.SlotHead, "Filters some of the toxic fumes",
+		common.JSONB{"armor": 1, "resist": map[string]interface{}{"toxic_air": 0.3}}),
+
+	// Urban
+	artifact("old_documents", "Pre-War Documents", "Papers from the time before the anomaly"),
+	artifact("medical_supplies", "Medical Emergency Kit", "Sealed supplies from an abandoned hospital"),
+	artifact("electronics", "Salvaged Electronics", "Working electronics from the ruins"),
+	artifact("urban_artifact", "City Historical Artifact", "A relic of the old city"),
+	artifact("cash_register", "Cash Register", "Old register, still full of worthless money"),
+	artifact("pocket_radio", "Pocket Radio Receiver", "Picks up strange broadcasts"),
+	usable(gear("flashlight", "Tactical Flashlight", common.SlotTool, "Lights up dark places and widens your scan",
+		common.JSONB{"scan_range": 0.1, "resist": map[string]interface{}{"darkness": 0.7}}),
+		common.JSONB{common.EffectReveal: effect(25, 120)}, 600),
+	consumable("first_aid_kit", "Combat First Aid Kit", "Restores health",
+		common.JSONB{common.EffectHeal: effect(30, 0)}, 30),
+	gear("crowbar", "Steel Crowbar", common.SlotTool, "Clears debris out of your way",
+		common.JSONB{"collect_radius": 3, "resist": map[string]interface{}{"debris": 0.5}}),
+
+	// Water
+	artifact("water_sample", "Contaminated Water Sample", "Water from an anomalous source"),
+	artifact("aquatic_plant", "Mutant Aquatic Plant", "A plant that thrives in polluted water"),
+	artifact("filtered_water", "Purified Water Container", "Clean water, a rare find"),
+	artifact("swamp_gas", "Swamp Gas Canister", "Captured methane from the marshes"),
+	artifact("algae_biomass", "Toxic Algae Biomass", "A sample of glowing algae"),
+	artifact("abyss_pearl", "Abyss Pearl", "A dark pearl from the deepest waters"),
+	gear("waders", "Waterproof Waders", common.SlotFeet, "Keeps you dry in contaminated water",
+		common.JSONB{"resist": map[string]interface{}{"contaminated_water": 0.5, "slippery_terrain": 0.5}}),
+	gear("fishing_gear", "Survival Fishing Kit", common.SlotTool, "Helps to spot things under the surface",
+		common.JSONB{"scan_range": 0.05, "collect_radius": 4}),
+	gear("water_purifier", "Portable Water Purifier", common.SlotTool, "Neutralizes contaminated water",
+		common.JSONB{"resist": map[string]interface{}{"contaminated_water": 0.7}}),
+
+	// Radioactive
+	artifact("uranium_ore", "Uranium Ore Fragment", "Radioactive ore, handle with care"),
+	artifact("radiation_detector", "Geiger Counter Device", "An old but working radiation detector"),
+	artifact("contaminated_soil", "Radioactive Soil Sample", "Soil from the exclusion zone"),
+	artifact("atomic_battery", "Nuclear Battery Cell", "A battery that never runs out"),
+	artifact("nuclear_fuel", "Spent Nuclear Fuel", "Still warm"),
+	artifact("plutonium_core", "Plutonium Reactor Core", "An extremely rare reactor core"),
+	artifact("reactor_fragment", "Reactor Core Fragment", "A piece of the destroyed reactor"),
+	artifact("control_rod", "Nuclear Control Rod", "A control rod from the reactor hall"),
+	gear("hazmat_suit", "Full Hazmat Suit", common.SlotBody, "Full body protection against radiation",
+		common.JSONB{"armor": 4, "inventory_slots": 5, "resist": map[string]interface{}{"radiation_high": 0.5, "radiation_low": 0.7, "decontamination": 0.4, "toxic_gas": 0.3}}),
+	usable(gear("geiger_counter", "Radiation Detector", common.SlotDetector, "Detects hidden artifacts and radiation",
+		common.JSONB{"detection_range": 0.25, "scan_range": 0.1}),
+		common.JSONB{common.EffectDetectionBoost: effect(0.5, 300)}, 900),
+	consumable("radiation_pills", "Anti-Radiation Pills", "Reduce radiation exposure for a while",
+		common.JSONB{common.EffectHazardResist: resist(0.5, 900, "radiation_low", "radiation_high", "mutation_risk")}, 60),
+
+	// Chemical
+	artifact("chemical_compound", "Experimental Chemical Compound", "A compound with unknown properties"),
+	artifact("lab_equipment", "Laboratory Equipment", "Intact equipment from a sealed lab"),
+	artifact("toxic_sample", "Hazardous Toxic Sample", "A highly toxic sample"),
+	artifact("hazmat_suit", "Professional Hazmat Suit", "A collector's hazmat suit, too damaged to wear"),
+	artifact("catalyst", "Chemical Catalyst", "Speeds up any reaction"),
+	artifact("pure_toxin", "Pure Concentrated Toxin", "The deadliest substance in the zone"),
+	artifact("experimental_serum", "Experimental Bio-Serum", "A serum from forbidden experiments"),
+	artifact("bio_weapon", "Biological Weapon Sample", "Never open this"),
+	gear("gas_mask", "Military Gas Mask", common.SlotHead, "Filters toxic gases",
+		common.JSONB{"armor": 1, "resist": map[string]interface{}{"toxic_gas": 0.6, "toxic_air": 0.6, "methane_gas": 0.5}}),
+	gear("chemical_suit", "Chemical Protection Suit", common.SlotBody, "Resistant to chemical burns",
+		common.JSONB{"armor": 4, "inventory_slots": 5, "resist": map[string]interface{}{"chemical_burns": 0.6, "corrosive_damage": 0.5, "toxic_gas": 0.3}}),
+	consumable("neutralizer", "Chemical Neutralizer", "Neutralizes chemicals on your skin",
+		common.JSONB{common.EffectHazardResist: resist(0.5, 600, "toxic_gas", "chemical_burns", "corrosive_damage")}, 60),
+
+	// Itemy bez typu (napr. staršie odmeny)
+	{Key: "artifact:" + common.ItemTypeMisc, ItemType: "artifact", Type: common.ItemTypeMisc, Category: common.ItemCategoryArtifact,
+		Name: "Unknown Artifact", StackSize: artifactStackSize, IconKey: common.ItemTypeMisc, Stats: common.JSONB{}, IsActive: true},
+	{Key: "gear:" + common.ItemTypeMisc, ItemType: "gear", Type: common.ItemTypeMisc, Category: common.ItemCategoryGear,
+		Name: "Unknown Gear", StackSize: gearStackSize, IconKey: common.ItemTypeMisc, Stats: common.JSONB{}, IsActive: true},
+}
+
+func artifact(typ, name, description string) common.ItemDefinition {
+	return common.ItemDefinition{
+		Key:         "artifact:" + typ,
+		ItemType:    "artifact",
+		Type:        typ,
+		Category:    common.ItemCategoryArtifact,
+		Name:        name,
+		Description: description,
+		Stats:       common.JSONB{},
+		StackSize:   artifactStackSize,
+		IconKey:     typ,
+		IsActive:    true,
+	}
+}
+
+func gear(typ, name, slot, description string, stats common.JSONB) common.ItemDefinition {
+	return common.ItemDefinition{
+		Key:         "gear:" + typ,
+		ItemType:    "gear",
+		Type:        typ,
+		Category:    common.ItemCategoryGear,
+		Slot:        slot,
+		Name:        name,
+		Description: description,
+		Stats:       stats,
+		StackSize:   gearStackSize,
+		IconKey:     typ,
+		IsActive:    true,
+	}
+}
+
+// Spotrebné predmety sa spawnujú ako gear, ale nedajú sa vybaviť
+func consumable(typ, name, description string, effects common.JSONB, cooldownSeconds int) common.ItemDefinition {
+	return common.ItemDefinition{
+		Key:             "gear:" + typ,
+		ItemType:        "gear",
+		Type:            typ,
+		Category:        common.ItemCategoryConsumable,
+		Name:            name,
+		Description:     description,
+		Stats:           common.JSONB{},
+		StackSize:       consumableStackSize,
+		IconKey:         typ,
+		IsActive:        true,
+		Effects:         effects,
+		CooldownSeconds: cooldownSeconds,
+	}
+}
+
+// usable - vybaviteľný nástroj s efektom, použitím sa nespotrebuje
+func usable(def common.ItemDefinition, effects common.JSONB, cooldownSeconds int) common.ItemDefinition {
+	def.Effects = effects
+	def.CooldownSeconds = cooldownSeconds
+	def.KeepOnUse = true
+	return def
+}
+
+// effect - hodnota a trvanie (0 = okamžitý efekt)
+func effect(value float64, durationSeconds int) map[string]interface{} {
+	return map[string]interface{}{"value": value, "duration_seconds": durationSeconds}
+}
+
+func resist(value float64, durationSeconds int, hazards ...string) map[string]interface{} {
+	spec := effect(value, durationSeconds)
+	list := make([]interface{}, len(hazards))
+	for i, hazard := range hazards {
+		list[i] = hazard
+	}
+	spec["hazards"] = list
+	return spec
+}
+
+// SeedDefinitions - doplní chýbajúce predvolené definície (existujúce nemení)
+func SeedDefinitions(db *gorm.DB) error {
+	for _, def := range defaultDefinitions {
+		def := def
+		result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).Create(&def)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("📦 Seeded item definition: %s", def.Key)
+		}
+
+		// Definície zapísané pred zavedením efektov ich dostanú (upravené efekty sa neprepisujú)
+		if def.Usable() {
+			if err := db.Model(&common.ItemDefinition{}).
+				Where("key = ? AND (effects IS NULL OR effects = '{}'::jsonb)", def.Key).
+				Updates(map[string]interface{}{
+					"effects":          def.Effects,
+					"cooldown_seconds": def.CooldownSeconds,
+					"keep_on_use":      def.KeepOnUse,
+				}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	Invalidate()
+	return nil
+}
+
+// BackfillInventory - doplní definition_key existujúcim itemom v inventári.
+// Typy, ktoré katalóg nepozná (napr. sezónne artefakty), dostanú generickú definíciu.
+func BackfillInventory(db *gorm.DB) error {
+	result := db.Exec(`
+		UPDATE inventory_items
+		SET definition_key = LOWER(TRIM(item_type)) || ':' || LOWER(COALESCE(NULLIF(TRIM(properties->>'type'), ''), ?))
+		WHERE definition_key IS NULL OR definition_key = ''
+	`, common.ItemTypeMisc)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("📦 Backfilled definition_key for %d inventory items", result.RowsAffected)
+	}
+
+	result = db.Exec(`
+		INSERT INTO item_definitions (key, item_type, type, category, name, stats, stack_size, icon_key, is_active, created_at, updated_at)
+		SELECT i.definition_key,
+			MIN(LOWER(TRIM(i.item_type))),
+			split_part(i.definition_key, ':', 2),
+			MIN(LOWER(TRIM(i.item_type))),
+			COALESCE(MIN(i.properties->>'name'), split_part(i.definition_key, ':', 2)),
+			'{}'::jsonb,
+			CASE WHEN MIN(LOWER(TRIM(i.item_type))) = 'artifact' THEN ? ELSE ? END,
+			split_part(i.definition_key, ':', 2), true, NOW(), NOW()
+		FROM inventory_items i
+		LEFT JOIN item_definitions d ON d.key = i.definition_key
+		WHERE d.id IS NULL AND i.definition_key <> ''
+		GROUP BY i.definition_key
+		ON CONFLICT (key) DO NOTHING
+	`, artifactStackSize, gearStackSize)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("📦 Created %d generic item definitions for unknown inventory types", result.RowsAffected)
+		Invalidate()
+	}
+
+	return nil
+}
@@ -0,0 +1,337 @@
+package items
+
+import (
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/wallet"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrItemBroken        = errors.New("item is broken, repair it first")
+	ErrNotRepairable     = errors.New("item has no durability")
+	ErrNotDamaged        = errors.New("item is not damaged")
+	ErrNoRepairMaterials = errors.New("no artifacts to repair with")
+	ErrInvalidMaterial   = errors.New("repair material must be an owned artifact that is not a favorite")
+)
+
+// Opotrebenie gearu
+const (
+	WearPerAction = 1 // tool pri zbere, detektor pri detekcii
+	WearPerUse    = 5 // použitie nástroja s KeepOnUse (napr. flashlight)
+	WearPerHazard = 2 // gear, ktorý chráni pred hazardom alebo pochádza z jeho biómu
+)
+
+// Body opravy za jeden artefakt podľa rarity; artefakt z biómu gearu sa počíta dvojnásobne
+var repairPoints = map[string]int{
+	"common":    10,
+	"rare":      25,
+	"epic":      50,
+	"legendary": 100,
+}
+
+// ✅ NEW: Cena opravy za jeden bod durability podľa meny (mena ide do system:repair)
+var repairCostPerPoint = map[string]float64{
+	common.CurrencyCredits:  5,
+	common.CurrencyCrystals: 0.1,
+}
+
+const wornDurabilityThreshold = common.MaxGearDurability / 4
+
+// WearResult - gear, ktorý stratil durability
+type WearResult struct {
+	ItemID     uuid.UUID `json:"item_id"`
+	Name       string    `json:"name"`
+	Slot       string    `json:"slot"`
+	Durability int       `json:"durability"`
+	Broken     bool      `json:"broken"`
+}
+
+// RepairMaterial - artefakt spotrebovaný pri oprave
+type RepairMaterial struct {
+	ItemID uuid.UUID `json:"item_id"`
+	Type   string    `json:"type"`
+	Rarity string    `json:"rarity"`
+	Points int       `json:"points"`
+}
+
+// RepairResult - výsledok opravy
+type RepairResult struct {
+	ItemID           uuid.UUID             `json:"item_id"`
+	Name             string                `json:"name"`
+	DurabilityBefore int                   `json:"durability_before"`
+	Durability       common.ItemDurability `json:"durability"`
+	Restored         int                   `json:"restored"`
+	Consumed         []RepairMaterial      `json:"consumed"`
+	Unused           []uuid.UUID           `json:"unused,omitempty"`
+	Paid             *RepairPayment        `json:"paid,omitempty"`
+}
+
+// RepairPayment - mena zaplatená za opravu
+type RepairPayment struct {
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+}
+
+// Durability - aktuálna durability itemu (bez záznamu = nový gear)
+func Durability(item common.InventoryItem) int {
+	raw, ok := item.Properties["durability"]
+	if !ok {
+		return common.MaxGearDurability
+	}
+	return min(max(int(numeric(raw)), 0), common.MaxGearDurability)
+}
+
+// IsBroken - gear s nulovou durability nedáva bonusy
+func IsBroken(item common.InventoryItem) bool {
+	return Durability(item) <= 0
+}
+
+// DurabilityOf - stav opotrebenia pre klienta (nil = item nemá durability)
+func DurabilityOf(item common.InventoryItem, def common.ItemDefinition) *common.ItemDurability {
+	if !def.Equippable() {
+		return nil
+	}
+	current := Durability(item)
+	return &common.ItemDurability{
+		Current: current,
+		Max:     common.MaxGearDurability,
+		State:   durabilityState(current),
+		Broken:  current <= 0,
+	}
+}
+
+func durabilityState(current int) string {
+	switch {
+	case current <= 0:
+		return common.DurabilityBroken
+	case current <= wornDurabilityThreshold:
+		return common.DurabilityWorn
+	default:
+		return common.DurabilityIntact
+	}
+}
+
+// Wear - atomicky zníži durability itemu a vráti novú hodnotu
+func Wear(tx *gorm.DB, itemID uuid.UUID, amount int) (int, error) {
+	var durability int
+	err := tx.Raw(`
+		UPDATE inventory_items
+		SET properties = COALESCE(properties, '{}'::jsonb) || jsonb_build_object('durability',
+			GREATEST(COALESCE((properties->>'durability')::numeric, ?) - ?, 0)::int)
+		WHERE id = ?
+		RETURNING (properties->>'durability')::int
+	`, common.MaxGearDurability, amount, itemID).Scan(&durability).Error
+	return durability, err
+}
+
+// WearEquipped - opotrebí vybavený (nerozbitý) gear, ktorý vyhovuje filtru (nil = všetok)
+func WearEquipped(db *gorm.DB, userID uuid.UUID, amount int, match func(LoadoutSlot) bool) []WearResult {
+	var worn []WearResult
+	if amount <= 0 {
+		return worn
+	}
+
+	for _, slot := range Loadout(db, userID) {
+		if slot.Item == nil || IsBroken(*slot.Item) {
+			continue
+		}
+		if match != nil && !match(slot) {
+			continue
+		}
+
+		durability, err := Wear(db, slot.Item.ID, amount)
+		if err != nil {
+			log.Printf("❌ Failed to wear item %s: %v", slot.Item.ID, err)
+			continue
+		}
+		worn = append(worn, WearResult{
+			ItemID:     slot.Item.ID,
+			Name:       itemName(*slot.Item, *slot.Definition),
+			Slot:       slot.Slot,
+			Durability: durability,
+			Broken:     durability <= 0,
+		})
+	}
+	return worn
+}
+
+// WearSlot - opotrebí gear v danom slote (napr. tool pri zbere)
+func WearSlot(db *gorm.DB, userID uuid.UUID, slot string, amount int) []WearResult {
+	return WearEquipped(db, userID, amount, func(equipped LoadoutSlot) bool {
+		return equipped.Slot == slot
+	})
+}
+
+// Repair - opraví gear spotrebovaním artefaktov v jednej transakcii.
+// Artefakty sa spotrebúvajú v poradí požiadavky, kým nie je gear opravený; zvyšné ostanú v inventári.
+func Repair(db *gorm.DB, userID, itemID uuid.UUID, materialIDs []uuid.UUID) (*RepairResult, error) {
+	if len(materialIDs) == 0 {
+		return nil, ErrNoRepairMaterials
+	}
+
+	var result *RepairResult
+	err := db.Transaction(func(tx *gorm.DB) error {
+		item, def, err := lockRepairable(tx, userID, itemID)
+		if err != nil {
+			return err
+		}
+
+		before := Durability(*item)
+		result = &RepairResult{ItemID: item.ID, Name: itemName(*item, def), DurabilityBefore: before}
+		gearBiome, _ := item.Properties["biome"].(string)
+		durability := before
+		now := time.Now()
+
+		for i, materialID := range materialIDs {
+			if durability >= common.MaxGearDurability {
+				result.Unused = materialIDs[i:]
+				break
+			}
+			if materialID == item.ID {
+				return ErrInvalidMaterial
+			}
+
+			var material common.InventoryItem
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND user_id = ? AND item_type = ? AND deleted_at IS NULL", materialID, userID, common.ItemCategoryArtifact).
+				First(&material).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrInvalidMaterial
+				}
+				return err
+			}
+			if favorite, _ := material.Properties["favorite"].(bool); favorite {
+				return ErrInvalidMaterial
+			}
+			if InEscrow(tx, material.ID) {
+				return ErrItemInEscrow
+			}
+
+			consumed := repairMaterial(material, gearBiome)
+			if _, err := consumeOne(tx, material, now); err != nil {
+				return err
+			}
+			durability = min(durability+consumed.Points, common.MaxGearDurability)
+			result.Consumed = append(result.Consumed, consumed)
+		}
+
+		return finishRepair(tx, *item, def, result, durability, now)
+	})
+
+	return result, err
+}
+
+// ✅ NEW: RepairWithCurrency - plná oprava gearu za kredity alebo kryštály
+func RepairWithCurrency(db *gorm.DB, userID, itemID uuid.UUID, currency string) (*RepairResult, error) {
+	rate, ok := repairCostPerPoint[currency]
+	if !ok {
+		return nil, wallet.ErrInvalidCurrency
+	}
+
+	var result *RepairResult
+	err := db.Transaction(func(tx *gorm.DB) error {
+		item, def, err := lockRepairable(tx, userID, itemID)
+		if err != nil {
+			return err
+		}
+
+		before := Durability(*item)
+		cost := repairCost(common.MaxGearDurability-before, rate)
+		if _, err := wallet.Post(tx, wallet.Posting{
+			Currency:      currency,
+			Reason:        wallet.ReasonRepair,
+			ReferenceType: "inventory_item",
+			ReferenceID:   &item.ID,
+			Entries:       wallet.Move(wallet.UserAccount(userID), common.AccountRepair, cost),
+		}); err != nil {
+			return err
+		}
+
+		result = &RepairResult{
+			ItemID:           item.ID,
+			Name:             itemName(*item, def),
+			DurabilityBefore: before,
+			Paid:             &RepairPayment{Currency: currency, Amount: cost},
+		}
+		return finishRepair(tx, *item, def, result, common.MaxGearDurability, time.Now())
+	})
+
+	return result, err
+}
+
+func repairCost(points int, rate float64) int64 {
+	return max(1, int64(math.Ceil(float64(points)*rate)))
+}
+
+// lockRepairable - vlastnený, poškodený gear mimo escrow (FOR UPDATE)
+func lockRepairable(tx *gorm.DB, userID, itemID uuid.UUID) (*common.InventoryItem, common.ItemDefinition, error) {
+	var item common.InventoryItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", itemID, userID).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ItemDefinition{}, ErrItemNotFound
+		}
+		return nil, common.ItemDefinition{}, err
+	}
+
+	def := Resolve(tx, item)
+	if !def.Equippable() {
+		return nil, def, ErrNotRepairable
+	}
+	if InEscrow(tx, item.ID) {
+		return nil, def, ErrItemInEscrow
+	}
+	if Durability(item) >= common.MaxGearDurability {
+		return nil, def, ErrNotDamaged
+	}
+	return &item, def, nil
+}
+
+// finishRepair - zapíše novú durability a počítadlo opráv
+func finishRepair(tx *gorm.DB, item common.InventoryItem, def common.ItemDefinition, result *RepairResult, durability int, now time.Time) error {
+	result.Restored = durability - result.DurabilityBefore
+	result.Durability = *DurabilityOf(common.InventoryItem{Properties: common.JSONB{"durability": durability}}, def)
+
+	repairs := int(numeric(item.Properties["repairs"])) + 1
+	patch := common.JSONB{"durability": durability, "repairs": repairs, "repaired_at": now.Format(time.RFC3339)}
+	return tx.Model(&common.InventoryItem{}).Where("id = ?", item.ID).
+		Update("properties", gorm.Expr("COALESCE(properties, '{}'::jsonb) || ?::jsonb", patch)).Error
+}
+
+func repairMaterial(material common.InventoryItem, gearBiome string) RepairMaterial {
+	rarity, _ := material.Properties["rarity"].(string)
+	typ, _ := material.Properties["type"].(string)
+
+	points, ok := repairPoints[rarity]
+	if !ok {
+		points = repairPoints["common"]
+	}
+	if biome, _ := material.Properties["biome"].(string); biome != "" && biome == gearBiome {
+		points *= 2
+	}
+	return RepairMaterial{ItemID: material.ID, Type: typ, Rarity: rarity, Points: points}
+}
+
+// consumeOne - spotrebuje jeden kus zo stacku (posledný kus item zmaže), vráti zvyšné množstvo
+func consumeOne(tx *gorm.DB, item common.InventoryItem, now time.Time) (int, error) {
+	if item.Quantity > 1 {
+		remaining := item.Quantity - 1
+		return remaining, tx.Model(&item).Update("quantity", remaining).Error
+	}
+	return 0, tx.Model(&item).Update("deleted_at", now).Error
+}
+
+func itemName(item common.InventoryItem, def common.ItemDefinition) string {
+	if name, ok := item.Properties["name"].(string); ok && name != "" {
+		return name
+	}
+	return def.Name
+}
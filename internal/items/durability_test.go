@@ -0,0 +1,64 @@
+package items
+
+import (
+	"testing"
+
+	"geoanomaly/internal/common"
+)
+
+func TestDurabilityOf(t *testing.T) {
+	gasMask := common.ItemDefinition{Category: common.ItemCategoryGear, Slot: common.SlotHead}
+	artifact := common.ItemDefinition{Category: common.ItemCategoryArtifact}
+
+	cases := []struct {
+		name       string
+		properties common.JSONB
+		want       int
+		state      string
+	}{
+		{"new gear", common.JSONB{}, common.MaxGearDurability, common.DurabilityIntact},
+		{"from db", common.JSONB{"durability": float64(60)}, 60, common.DurabilityIntact},
+		{"worn", common.JSONB{"durability": 20}, 20, common.DurabilityWorn},
+		{"broken", common.JSONB{"durability": float64(0)}, 0, common.DurabilityBroken},
+		{"below zero", common.JSONB{"durability": float64(-5)}, 0, common.DurabilityBroken},
+	}
+	for _, tc := range cases {
+		item := common.InventoryItem{Properties: tc.properties}
+		got := DurabilityOf(item, gasMask)
+		if got == nil || got.Current != tc.want || got.State != tc.state || got.Broken != (tc.want == 0) {
+			t.Errorf("%s: got %+v, want %d (%s)", tc.name, got, tc.want, tc.state)
+		}
+		if IsBroken(item) != (tc.want == 0) {
+			t.Errorf("%s: IsBroken = %v", tc.name, IsBroken(item))
+		}
+	}
+
+	if got := DurabilityOf(common.InventoryItem{}, artifact); got != nil {
+		t.Errorf("artifacts have no durability, got %+v", got)
+	}
+}
+
+func TestRepairMaterial(t *testing.T) {
+	material := func(rarity, biome string) common.InventoryItem {
+		return common.InventoryItem{Properties: common.JSONB{"type": "steel_ingot", "rarity": rarity, "biome": biome}}
+	}
+
+	if got := repairMaterial(material("rare", "urban"), "industrial").Points; got != 25 {
+		t.Errorf("rare from other biome = %d, want 25", got)
+	}
+	if got := repairMaterial(material("rare", "industrial"), "industrial").Points; got != 50 {
+		t.Errorf("rare from gear biome = %d, want 50", got)
+	}
+	if got := repairMaterial(material("", ""), "").Points; got != repairPoints["common"] {
+		t.Errorf("unknown rarity = %d, want common points", got)
+	}
+}
+
+func TestRepairCost(t *testing.T) {
+	if got := repairCost(40, repairCostPerPoint[common.CurrencyCredits]); got != 200 {
+		t.Errorf("40 points for credits = %d, want 200", got)
+	}
+	if got := repairCost(5, repairCostPerPoint[common.CurrencyCrystals]); got != 1 {
+		t.Errorf("small repair for crystals = %d, want minimum 1", got)
+	}
+}
@@ -0,0 +1,300 @@
+package items
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrItemNotFound   = errors.New("item not found")
+	ErrItemNotUsable  = errors.New("item cannot be used")
+	ErrItemOnCooldown = errors.New("item is on cooldown")
+	ErrNotInZone      = errors.New("item can only be used inside a zone")
+	ErrFullHealth     = errors.New("already at full health")
+)
+
+// Effect - jeden efekt z ItemDefinition.Effects
+type Effect struct {
+	Kind     string        `json:"effect"`
+	Value    float64       `json:"value"`
+	Duration time.Duration `json:"-"`
+	Hazards  []string      `json:"hazards,omitempty"`
+}
+
+// Timed - dočasný efekt (buff), inak okamžitý
+func (e Effect) Timed() bool {
+	return e.Duration > 0
+}
+
+// EffectResult - výsledok aplikovania efektu
+type EffectResult struct {
+	Effect      string     `json:"effect"`
+	Value       float64    `json:"value"`
+	Instant     bool       `json:"instant"`
+	HealthAfter *int       `json:"health_after,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Hazards     []string   `json:"hazards,omitempty"`
+}
+
+// UseResult - výsledok použitia itemu
+type UseResult struct {
+	ItemID            uuid.UUID      `json:"item_id"`
+	DefinitionKey     string         `json:"definition_key"`
+	Name              string         `json:"name"`
+	Effects           []EffectResult `json:"effects"`
+	Consumed          bool           `json:"consumed"`
+	RemainingQuantity int            `json:"remaining_quantity"`
+	CooldownSeconds   int            `json:"cooldown_seconds"`
+	ReadyAt           *time.Time     `json:"ready_at,omitempty"`
+	Durability        *int           `json:"durability,omitempty"` // nástroj po opotrebení
+}
+
+// ParseEffects - efekty definície zoradené podľa druhu; neznáme druhy sa ignorujú
+func ParseEffects(raw common.JSONB) []Effect {
+	effects := make([]Effect, 0, len(raw))
+	for kind, value := range raw {
+		switch kind {
+		case common.EffectHeal, common.EffectHazardResist, common.EffectDetectionBoost, common.EffectReveal:
+		default:
+			continue
+		}
+
+		spec, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		effect := Effect{Kind: kind}
+		effect.Value, _ = spec["value"].(float64)
+		if seconds, ok := spec["duration_seconds"].(float64); ok && seconds > 0 {
+			effect.Duration = time.Duration(seconds) * time.Second
+		}
+		switch hazards := spec["hazards"].(type) {
+		case []interface{}:
+			for _, hazard := range hazards {
+				if h, ok := hazard.(string); ok {
+					effect.Hazards = append(effect.Hazards, h)
+				}
+			}
+		case []string:
+			effect.Hazards = append(effect.Hazards, hazards...)
+		}
+
+		if effect.Value > 0 {
+			effects = append(effects, effect)
+		}
+	}
+
+	sort.Slice(effects, func(i, j int) bool { return effects[i].Kind < effects[j].Kind })
+	return effects
+}
+
+// UseItem - spoločná implementácia pre POST /inventory/:id/use aj /game/items/use/:id.
+// Overí kontext (zóna, zdravie, cooldown), aplikuje efekty a item spotrebuje v jednej transakcii.
+func UseItem(db *gorm.DB, userID, itemID uuid.UUID) (*UseResult, error) {
+	var result *UseResult
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var item common.InventoryItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ? AND deleted_at IS NULL", itemID, userID).First(&item).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrItemNotFound
+			}
+			return err
+		}
+
+		// ✅ NEW: Item v obchode sa nedá použiť
+		if InEscrow(tx, item.ID) {
+			return ErrItemInEscrow
+		}
+
+		def := Resolve(tx, item)
+		effects := ParseEffects(def.Effects)
+		if len(effects) == 0 {
+			return ErrItemNotUsable
+		}
+		// ✅ NEW: Rozbitý nástroj (napr. geiger_counter) sa nedá použiť
+		if def.Equippable() && IsBroken(item) {
+			return ErrItemBroken
+		}
+
+		result = &UseResult{
+			ItemID:            item.ID,
+			DefinitionKey:     def.Key,
+			Name:              def.Name,
+			RemainingQuantity: item.Quantity,
+			CooldownSeconds:   def.CooldownSeconds,
+		}
+
+		now := time.Now()
+		if readyAt := cooldownReadyAt(tx, userID, def, now); readyAt != nil {
+			result.ReadyAt = readyAt
+			return ErrItemOnCooldown
+		}
+
+		var user common.User
+		if err := tx.Select("id", "health").First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		zoneID := currentZone(tx, userID)
+		if err := validateEffects(effects, user, zoneID); err != nil {
+			return err
+		}
+
+		applied := make(common.JSONB, len(effects))
+		for _, effect := range effects {
+			effectResult, err := applyEffect(tx, userID, def.Key, effect, now)
+			if err != nil {
+				return err
+			}
+			result.Effects = append(result.Effects, effectResult)
+			applied[effect.Kind] = effectResult
+		}
+
+		if !def.KeepOnUse {
+			result.Consumed = true
+			remaining, err := consumeOne(tx, item, now)
+			if err != nil {
+				return err
+			}
+			result.RemainingQuantity = remaining
+		} else if def.Equippable() {
+			// Nástroje sa použitím opotrebúvajú
+			durability, err := Wear(tx, item.ID, WearPerUse)
+			if err != nil {
+				return err
+			}
+			result.Durability = &durability
+		}
+
+		if def.CooldownSeconds > 0 {
+			readyAt := now.Add(time.Duration(def.CooldownSeconds) * time.Second)
+			result.ReadyAt = &readyAt
+		}
+
+		return tx.Create(&common.ItemUse{
+			UserID:        userID,
+			DefinitionKey: def.Key,
+			ItemID:        item.ID,
+			ZoneID:        zoneID,
+			Effects:       applied,
+		}).Error
+	})
+
+	return result, err
+}
+
+// cooldownReadyAt - kedy sa dá item daného typu znova použiť (nil = hneď)
+func cooldownReadyAt(tx *gorm.DB, userID uuid.UUID, def common.ItemDefinition, now time.Time) *time.Time {
+	if def.CooldownSeconds <= 0 {
+		return nil
+	}
+
+	var last common.ItemUse
+	if err := tx.Where("user_id = ? AND definition_key = ?", userID, def.Key).
+		Order("created_at DESC").First(&last).Error; err != nil {
+		return nil
+	}
+
+	readyAt := last.CreatedAt.Add(time.Duration(def.CooldownSeconds) * time.Second)
+	if !readyAt.After(now) {
+		return nil
+	}
+	return &readyAt
+}
+
+// currentZone - zóna, v ktorej hráč práve je (nil = mimo zóny)
+func currentZone(tx *gorm.DB, userID uuid.UUID) *uuid.UUID {
+	var session common.PlayerSession
+	if err := tx.Where("user_id = ? AND current_zone IS NOT NULL", userID).First(&session).Error; err != nil {
+		return nil
+	}
+	return session.CurrentZone
+}
+
+func validateEffects(effects []Effect, user common.User, zoneID *uuid.UUID) error {
+	for _, effect := range effects {
+		switch effect.Kind {
+		case common.EffectHeal:
+			// Item len s liečením pri plnom zdraví nemá zmysel použiť
+			if user.Health >= common.MaxPlayerHealth && len(effects) == 1 {
+				return ErrFullHealth
+			}
+		case common.EffectReveal:
+			if zoneID == nil {
+				return ErrNotInZone
+			}
+		}
+	}
+	return nil
+}
+
+func applyEffect(tx *gorm.DB, userID uuid.UUID, sourceKey string, effect Effect, now time.Time) (EffectResult, error) {
+	result := EffectResult{
+		Effect:  effect.Kind,
+		Value:   effect.Value,
+		Instant: !effect.Timed(),
+		Hazards: effect.Hazards,
+	}
+
+	if !effect.Timed() {
+		switch effect.Kind {
+		case common.EffectHeal:
+			health, err := heal(tx, userID, int(math.Round(effect.Value)))
+			if err != nil {
+				return result, err
+			}
+			result.HealthAfter = &health
+			return result, nil
+		default:
+			return result, fmt.Errorf("effect %s requires a duration", effect.Kind)
+		}
+	}
+
+	// Rovnaký efekt sa nesčítava - nový buff nahradí aktívny
+	if err := tx.Where("user_id = ? AND effect = ? AND expires_at > ?", userID, effect.Kind, now).
+		Delete(&common.PlayerBuff{}).Error; err != nil {
+		return result, err
+	}
+
+	expiresAt := now.Add(effect.Duration)
+	properties := common.JSONB{}
+	if len(effect.Hazards) > 0 {
+		properties["hazards"] = effect.Hazards
+	}
+	if err := tx.Create(&common.PlayerBuff{
+		UserID:     userID,
+		Effect:     effect.Kind,
+		Value:      effect.Value,
+		Properties: properties,
+		SourceKey:  sourceKey,
+		ExpiresAt:  expiresAt,
+	}).Error; err != nil {
+		return result, err
+	}
+
+	result.ExpiresAt = &expiresAt
+	return result, nil
+}
+
+func heal(tx *gorm.DB, userID uuid.UUID, amount int) (int, error) {
+	if err := tx.Model(&common.User{}).Where("id = ?", userID).
+		Update("health", gorm.Expr("LEAST(COALESCE(health, 0) + ?, ?)", amount, common.MaxPlayerHealth)).Error; err != nil {
+		return 0, err
+	}
+
+	var user common.User
+	if err := tx.Select("health").First(&user, "id = ?", userID).Error; err != nil {
+		return 0, err
+	}
+	return user.Health, nil
+}
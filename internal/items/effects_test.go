@@ -0,0 +1,67 @@
+package items
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+)
+
+func TestParseEffects(t *testing.T) {
+	raw := common.JSONB{
+		common.EffectHazardResist: map[string]interface{}{
+			"value": 0.5, "duration_seconds": float64(900), "hazards": []interface{}{"radiation_high"},
+		},
+		common.EffectHeal: map[string]interface{}{"value": float64(30)},
+		"teleport":        map[string]interface{}{"value": float64(1)}, // neznámy efekt
+	}
+
+	effects := ParseEffects(raw)
+	if len(effects) != 2 {
+		t.Fatalf("got %d effects, want 2", len(effects))
+	}
+
+	resist, heal := effects[0], effects[1]
+	if heal.Kind != common.EffectHeal || heal.Timed() || heal.Value != 30 {
+		t.Errorf("unexpected heal effect %+v", heal)
+	}
+	if resist.Kind != common.EffectHazardResist || resist.Duration != 15*time.Minute ||
+		len(resist.Hazards) != 1 || resist.Hazards[0] != "radiation_high" {
+		t.Errorf("unexpected resist effect %+v", resist)
+	}
+
+	// Predvolený katalóg musí mať efekty, ktoré engine pozná
+	for _, def := range defaultDefinitions {
+		if def.Usable() && len(ParseEffects(def.Effects)) != len(def.Effects) {
+			t.Errorf("%s has effects the engine does not understand", def.Key)
+		}
+	}
+}
+
+func TestValidateEffects(t *testing.T) {
+	zoneID := uuid.New()
+	heal := []Effect{{Kind: common.EffectHeal, Value: 30}}
+	reveal := []Effect{{Kind: common.EffectReveal, Value: 25, Duration: time.Minute}}
+
+	cases := []struct {
+		name    string
+		effects []Effect
+		health  int
+		zone    *uuid.UUID
+		want    error
+	}{
+		{"heal when hurt", heal, 40, nil, nil},
+		{"heal at full health", heal, common.MaxPlayerHealth, nil, ErrFullHealth},
+		{"reveal outside zone", reveal, 100, nil, ErrNotInZone},
+		{"reveal inside zone", reveal, 100, &zoneID, nil},
+	}
+	for _, tc := range cases {
+		err := validateEffects(tc.effects, common.User{Health: tc.health}, tc.zone)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package items
+
+import (
+	"errors"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrItemInEscrow = errors.New("item is locked in a pending trade or market listing")
+
+// NotInEscrow - podmienka pre dotazy nad inventory_items (voľné itemy)
+const NotInEscrow = "id NOT IN (SELECT item_id FROM item_escrows)"
+
+// InEscrow - item je zamknutý v escrow
+func InEscrow(tx *gorm.DB, itemID uuid.UUID) bool {
+	var count int64
+	tx.Model(&common.ItemEscrow{}).Where("item_id = ?", itemID).Count(&count)
+	return count > 0
+}
+
+// LockEscrow - zamkne itemy pre danú referenciu (ponuku obchodu, ...); item môže byť len v jednom escrow
+func LockEscrow(tx *gorm.DB, source string, referenceID uuid.UUID, itemIDs []uuid.UUID) error {
+	for _, itemID := range itemIDs {
+		if InEscrow(tx, itemID) {
+			return ErrItemInEscrow
+		}
+		if err := tx.Create(&common.ItemEscrow{ItemID: itemID, Source: source, ReferenceID: referenceID}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleaseEscrow - uvoľní všetky itemy danej referencie
+func ReleaseEscrow(tx *gorm.DB, source string, referenceID uuid.UUID) error {
+	return tx.Where("source = ? AND reference_id = ?", source, referenceID).Delete(&common.ItemEscrow{}).Error
+}
+
+// Transfer - zmena vlastníka (obchod, trh); príznaky predchádzajúceho vlastníka (obľúbené, vybavené) sa zrušia
+func Transfer(tx *gorm.DB, itemID, fromID, toID uuid.UUID, now time.Time) error {
+	patch := common.JSONB{"traded_from": fromID.String(), "traded_at": now.Unix()}
+	result := tx.Model(&common.InventoryItem{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", itemID, fromID).
+		Updates(map[string]interface{}{
+			"user_id": toID,
+			"properties": gorm.Expr(
+				"(COALESCE(properties, '{}'::jsonb) - 'favorite' - 'equipped' - 'equipped_at' - 'slot') || ?::jsonb", patch),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrItemNotFound
+	}
+	return ReleaseItem(tx, itemID)
+}
@@ -0,0 +1,77 @@
+package items
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrUnknownDefinition = errors.New("item definition not found")
+
+// Grant - vytvorí itemy z katalógu {definitionKey: množstvo}; stackovateľné sa pripočítajú k existujúcemu
+// stacku (alebo vzniknú ako jeden riadok s Quantity), ostatné po kuse. extra sa pridá do properties nových riadkov.
+// Kapacitu nekontroluje - výstupy craftingu a rozobratia nahrádzajú spotrebované itemy.
+func Grant(tx *gorm.DB, userID uuid.UUID, quantities map[string]int, extra common.JSONB, now time.Time) ([]common.InventoryItem, error) {
+	keys := make([]string, 0, len(quantities))
+	for key := range quantities {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var created []common.InventoryItem
+	for _, key := range keys {
+		def, ok := Lookup(tx, key)
+		if !ok {
+			return nil, ErrUnknownDefinition
+		}
+
+		rows, quantity := quantities[key], 1
+		if def.StackSize > 1 {
+			rows, quantity = 1, quantities[key]
+		}
+		for i := 0; i < rows; i++ {
+			properties := common.JSONB{
+				"name":        def.Name,
+				"type":        def.Type,
+				"acquired_at": now.Unix(),
+			}
+			for k, v := range extra {
+				properties[k] = v
+			}
+			if def.Equippable() {
+				properties["level"] = 1
+			}
+
+			item := common.InventoryItem{
+				UserID:        userID,
+				ItemType:      def.ItemType,
+				ItemID:        uuid.New(),
+				Quantity:      quantity,
+				Properties:    properties,
+				DefinitionKey: def.Key,
+			}
+			stack, err := findStack(tx, item, def)
+			if err != nil {
+				return nil, err
+			}
+			if stack != nil {
+				if err := addToStack(tx, stack, quantity); err != nil {
+					return nil, err
+				}
+				created = append(created, *stack)
+				continue
+			}
+			if err := tx.Create(&item).Error; err != nil {
+				return nil, err
+			}
+			created = append(created, item)
+		}
+	}
+
+	return created, nil
+}
@@ -0,0 +1,355 @@
+package items
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/wallet"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// GetCatalog - GET /game/items/catalog?category=gear&slot=head
+func (h *Handler) GetCatalog(c *gin.Context) {
+	category := c.Query("category")
+	slot := c.Query("slot")
+
+	definitions := make([]common.ItemDefinition, 0)
+	for _, def := range catalog.get(h.db) {
+		if category != "" && def.Category != category {
+			continue
+		}
+		if slot != "" && def.Slot != slot {
+			continue
+		}
+		definitions = append(definitions, def)
+	}
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Key < definitions[j].Key })
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":     definitions,
+		"count":     len(definitions),
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// UseItem - POST /inventory/:id/use a POST /game/items/use/:id
+func (h *Handler) UseItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item UUID"})
+		return
+	}
+
+	result, err := UseItem(h.db, userID.(uuid.UUID), itemID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrItemNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		case errors.Is(err, ErrItemOnCooldown):
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":    "Item is on cooldown",
+				"ready_at": result.ReadyAt,
+				"retry_in": int(time.Until(*result.ReadyAt).Seconds()) + 1,
+			})
+		case errors.Is(err, ErrItemNotUsable), errors.Is(err, ErrNotInZone), errors.Is(err, ErrFullHealth),
+			errors.Is(err, ErrItemBroken):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrItemInEscrow):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("❌ Failed to use item %s: %v", itemID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to use item"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"message":      fmt.Sprintf("%s used", result.Name),
+		"item_id":      result.ItemID,
+		"new_quantity": result.RemainingQuantity,
+		"result":       result,
+		"timestamp":    time.Now().Format(time.RFC3339),
+	})
+}
+
+// RepairItem - POST /inventory/:id/repair {"artifact_ids": ["..."]} alebo {"currency": "credits" | "crystals"}
+// Oprava gearu za artefakty (z biómu gearu opravia dvojnásobne) alebo plná oprava za menu
+func (h *Handler) RepairItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item UUID"})
+		return
+	}
+
+	var req struct {
+		ArtifactIDs []uuid.UUID `json:"artifact_ids"`
+		Currency    string      `json:"currency"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var result *RepairResult
+	if req.Currency != "" {
+		result, err = RepairWithCurrency(h.db, userID.(uuid.UUID), itemID, req.Currency)
+	} else {
+		result, err = Repair(h.db, userID.(uuid.UUID), itemID, req.ArtifactIDs)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrItemNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		case errors.Is(err, ErrNotRepairable), errors.Is(err, ErrNotDamaged),
+			errors.Is(err, ErrNoRepairMaterials), errors.Is(err, ErrInvalidMaterial),
+			errors.Is(err, wallet.ErrInvalidCurrency):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, wallet.ErrInsufficientFunds):
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrItemInEscrow):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("❌ Failed to repair item %s: %v", itemID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to repair item"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   fmt.Sprintf("%s repaired (+%d durability)", result.Name, result.Restored),
+		"result":    result,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// GetOverflow - GET /inventory/overflow
+// ✅ NEW: Itemy, ktoré sa pri zbere nezmestili do inventára
+func (h *Handler) GetOverflow(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	stashed, err := OverflowItems(h.db, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch overflow items"})
+		return
+	}
+	capacity, err := CapacityOf(h.db, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch inventory capacity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"overflow":  stashed,
+		"capacity":  capacity,
+		"ttl_hours": OverflowTTL.Hours(),
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// ClaimOverflow - POST /inventory/overflow/:id/claim
+// ✅ NEW: Presun z overflow do inventára, keď je miesto
+func (h *Handler) ClaimOverflow(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	overflowID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid overflow item UUID"})
+		return
+	}
+
+	placement, err := ClaimOverflow(h.db, userID.(uuid.UUID), overflowID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrOverflowNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case CapacityCode(err) != "":
+			capacity, _ := CapacityOf(h.db, userID.(uuid.UUID))
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": CapacityCode(err), "capacity": capacity})
+		default:
+			log.Printf("❌ Failed to claim overflow item %s: %v", overflowID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim overflow item"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Item moved to inventory",
+		"result":    placement,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// GetActiveEffects - GET /user/effects
+// Aktívne dočasné efekty hráča
+func (h *Handler) GetActiveEffects(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	buffs := ActiveBuffs(h.db, userID.(uuid.UUID))
+	effects := make([]gin.H, 0, len(buffs))
+	for _, buff := range buffs {
+		effects = append(effects, gin.H{
+			"effect":     buff.Effect,
+			"value":      buff.Value,
+			"properties": buff.Properties,
+			"source_key": buff.SourceKey,
+			"expires_at": buff.ExpiresAt,
+			"expires_in": int(time.Until(buff.ExpiresAt).Seconds()),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"effects":   effects,
+		"count":     len(effects),
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// GetLoadout - GET /user/loadout
+// Sloty loadoutu a efektívne staty z vybaveného gearu
+func (h *Handler) GetLoadout(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	h.respondLoadout(c, userID.(uuid.UUID), nil)
+}
+
+// EquipLoadoutItem - PUT /user/loadout {"item_id": "..."}
+// Slot sa určí z katalógu; obsadený slot sa vymení v jednej transakcii
+func (h *Handler) EquipLoadoutItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var req struct {
+		ItemID string `json:"item_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.equip(c, userID.(uuid.UUID), req.ItemID)
+}
+
+// EquipItem - PUT /inventory/:id/equip
+func (h *Handler) EquipItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	h.equip(c, userID.(uuid.UUID), c.Param("id"))
+}
+
+// UnequipSlot - DELETE /user/loadout/:slot
+func (h *Handler) UnequipSlot(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	change, err := Unequip(h.db, userID.(uuid.UUID), c.Param("slot"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidSlot):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid slot", "slots": common.LoadoutSlots})
+		case errors.Is(err, ErrSlotEmpty):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Nothing equipped in this slot"})
+		default:
+			log.Printf("❌ Failed to unequip slot %s: %v", c.Param("slot"), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unequip item"})
+		}
+		return
+	}
+
+	h.respondLoadout(c, userID.(uuid.UUID), change)
+}
+
+func (h *Handler) equip(c *gin.Context, userID uuid.UUID, rawItemID string) {
+	itemID, err := uuid.Parse(rawItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item UUID"})
+		return
+	}
+
+	change, err := Equip(h.db, userID, itemID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrItemNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		case errors.Is(err, ErrNotEquippable):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Item cannot be equipped"})
+		case errors.Is(err, ErrItemInEscrow):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("❌ Failed to equip item %s: %v", itemID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to equip item"})
+		}
+		return
+	}
+
+	h.respondLoadout(c, userID, change)
+}
+
+func (h *Handler) respondLoadout(c *gin.Context, userID uuid.UUID, change *LoadoutChange) {
+	response := gin.H{
+		"success":   true,
+		"loadout":   Loadout(h.db, userID),
+		"stats":     Stats(h.db, userID),
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+	if change != nil {
+		response["change"] = change
+	}
+	c.JSON(http.StatusOK, response)
+}
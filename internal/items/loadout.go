@@ -0,0 +1,302 @@
+package items
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrNotEquippable = errors.New("item cannot be equipped")
+	ErrInvalidSlot   = errors.New("invalid loadout slot")
+	ErrSlotEmpty     = errors.New("nothing equipped in this slot")
+)
+
+// PlayerStats - efektívne staty z vybaveného gearu
+type PlayerStats struct {
+	Armor          int                `json:"armor"`           // -poškodenie zdravia z hazardov
+	HazardResist   map[string]float64 `json:"hazard_resist"`   // typ hazardu -> podiel zníženia poškodenia
+	DetectionRange float64            `json:"detection_range"` // +podiel dosahu detektora
+	ScanRange      float64            `json:"scan_range"`      // +podiel viditeľnosti zón pri skene
+	CollectRadius  float64            `json:"collect_radius"`  // +metre na zber skrytých artefaktov
+	XPBonus        float64            `json:"xp_bonus"`        // +podiel XP
+	InventorySlots int                `json:"inventory_slots"` // +miesta v inventári
+}
+
+// Resist - odolnosť voči typu hazardu (0..1)
+func (s PlayerStats) Resist(hazardType string) float64 {
+	return s.HazardResist[hazardType]
+}
+
+// LoadoutSlot - slot s vybaveným itemom (Item nil = prázdny)
+type LoadoutSlot struct {
+	Slot       string                 `json:"slot"`
+	Item       *common.InventoryItem  `json:"item,omitempty"`
+	Definition *common.ItemDefinition `json:"definition,omitempty"`
+	EquippedAt *time.Time             `json:"equipped_at,omitempty"`
+}
+
+// LoadoutChange - výsledok equip/unequip
+type LoadoutChange struct {
+	Slot       string     `json:"slot"`
+	Equipped   *uuid.UUID `json:"equipped,omitempty"`
+	Unequipped *uuid.UUID `json:"unequipped,omitempty"`
+}
+
+// Loadout - všetky sloty hráča (aj prázdne) v poradí common.LoadoutSlots
+func Loadout(db *gorm.DB, userID uuid.UUID) []LoadoutSlot {
+	rows, inventory := equippedItems(db, userID)
+
+	bySlot := make(map[string]LoadoutSlot, len(rows))
+	for _, row := range rows {
+		item, ok := inventory[row.ItemID]
+		if !ok {
+			continue
+		}
+		def := Resolve(db, item)
+		item.Definition = &def
+		item.Durability = DurabilityOf(item, def)
+		equippedAt := row.EquippedAt
+		bySlot[row.Slot] = LoadoutSlot{Slot: row.Slot, Item: &item, Definition: &def, EquippedAt: &equippedAt}
+	}
+
+	slots := make([]LoadoutSlot, 0, len(common.LoadoutSlots))
+	for _, slot := range common.LoadoutSlots {
+		if equipped, ok := bySlot[slot]; ok {
+			slots = append(slots, equipped)
+			continue
+		}
+		slots = append(slots, LoadoutSlot{Slot: slot})
+	}
+	return slots
+}
+
+// EquippedDefinitions - definície vybaveného gearu podľa slotu; rozbitý gear sa nepočíta
+func EquippedDefinitions(db *gorm.DB, userID uuid.UUID) map[string]common.ItemDefinition {
+	rows, inventory := equippedItems(db, userID)
+
+	definitions := make(map[string]common.ItemDefinition, len(rows))
+	for _, row := range rows {
+		if item, ok := inventory[row.ItemID]; ok && !IsBroken(item) {
+			definitions[row.Slot] = Resolve(db, item)
+		}
+	}
+	return definitions
+}
+
+// Stats - efektívne staty hráča z loadoutu
+func Stats(db *gorm.DB, userID uuid.UUID) PlayerStats {
+	definitions := make([]common.ItemDefinition, 0, len(common.LoadoutSlots))
+	for _, def := range EquippedDefinitions(db, userID) {
+		definitions = append(definitions, def)
+	}
+	return aggregateStats(definitions)
+}
+
+func equippedItems(db *gorm.DB, userID uuid.UUID) ([]common.PlayerLoadout, map[uuid.UUID]common.InventoryItem) {
+	var rows []common.PlayerLoadout
+	db.Where("user_id = ?", userID).Find(&rows)
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ItemID
+	}
+
+	var equipped []common.InventoryItem
+	db.Where("id IN ? AND user_id = ? AND deleted_at IS NULL", ids, userID).Find(&equipped)
+
+	inventory := make(map[uuid.UUID]common.InventoryItem, len(equipped))
+	for _, item := range equipped {
+		inventory[item.ID] = item
+	}
+	return rows, inventory
+}
+
+// aggregateStats - číselné staty sa sčítajú, odolnosti sa skladajú (1 - (1-a)(1-b))
+func aggregateStats(definitions []common.ItemDefinition) PlayerStats {
+	stats := PlayerStats{HazardResist: map[string]float64{}}
+
+	for _, def := range definitions {
+		stats.Armor += int(statValue(def.Stats, "armor"))
+		stats.DetectionRange += statValue(def.Stats, "detection_range")
+		stats.ScanRange += statValue(def.Stats, "scan_range")
+		stats.CollectRadius += statValue(def.Stats, "collect_radius")
+		stats.XPBonus += statValue(def.Stats, "xp_bonus")
+		stats.InventorySlots += int(statValue(def.Stats, "inventory_slots"))
+
+		resist, _ := def.Stats["resist"].(map[string]interface{})
+		for hazard, raw := range resist {
+			value := min(max(numeric(raw), 0), 1)
+			stats.HazardResist[hazard] = 1 - (1-stats.HazardResist[hazard])*(1-value)
+		}
+	}
+
+	return stats
+}
+
+func statValue(stats common.JSONB, key string) float64 {
+	return numeric(stats[key])
+}
+
+// numeric - hodnota zo JSONB (z DB float64, z predvoleného katalógu aj int)
+func numeric(raw interface{}) float64 {
+	switch v := raw.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	default:
+		return 0
+	}
+}
+
+// Equip - vybaví item do slotu z katalógu; obsadený slot sa atomicky vymení
+func Equip(db *gorm.DB, userID, itemID uuid.UUID) (*LoadoutChange, error) {
+	var change *LoadoutChange
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var item common.InventoryItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ? AND deleted_at IS NULL", itemID, userID).First(&item).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrItemNotFound
+			}
+			return err
+		}
+
+		def := Resolve(tx, item)
+		if !def.Equippable() {
+			return ErrNotEquippable
+		}
+		if InEscrow(tx, item.ID) {
+			return ErrItemInEscrow
+		}
+		change = &LoadoutChange{Slot: def.Slot, Equipped: &item.ID}
+
+		var current common.PlayerLoadout
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND slot = ?", userID, def.Slot).First(&current).Error
+		switch {
+		case err == nil && current.ItemID == item.ID:
+			return nil // už je vybavený
+		case err == nil:
+			if err := setEquippedFlag(tx, current.ItemID, false, ""); err != nil {
+				return err
+			}
+			change.Unequipped = &current.ItemID
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		// Item mohol byť v inom slote (ak sa slot v katalógu zmenil)
+		if err := tx.Where("(user_id = ? AND slot = ?) OR item_id = ?", userID, def.Slot, item.ID).
+			Delete(&common.PlayerLoadout{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&common.PlayerLoadout{
+			UserID:     userID,
+			Slot:       def.Slot,
+			ItemID:     item.ID,
+			EquippedAt: time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		return setEquippedFlag(tx, item.ID, true, def.Slot)
+	})
+
+	return change, err
+}
+
+// Unequip - vyprázdni slot
+func Unequip(db *gorm.DB, userID uuid.UUID, slot string) (*LoadoutChange, error) {
+	if !common.IsValidSlot(slot) {
+		return nil, ErrInvalidSlot
+	}
+
+	var change *LoadoutChange
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var current common.PlayerLoadout
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND slot = ?", userID, slot).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSlotEmpty
+			}
+			return err
+		}
+
+		if err := tx.Delete(&current).Error; err != nil {
+			return err
+		}
+		change = &LoadoutChange{Slot: slot, Unequipped: &current.ItemID}
+		return setEquippedFlag(tx, current.ItemID, false, "")
+	})
+
+	return change, err
+}
+
+// ReleaseItem - odstráni item z loadoutu (pri zmazaní, predaji, ...)
+func ReleaseItem(tx *gorm.DB, itemID uuid.UUID) error {
+	return tx.Where("item_id = ?", itemID).Delete(&common.PlayerLoadout{}).Error
+}
+
+// setEquippedFlag - properties.equipped ostáva kvôli zobrazeniu v inventári, pravdou je player_loadouts
+func setEquippedFlag(tx *gorm.DB, itemID uuid.UUID, equipped bool, slot string) error {
+	patch := common.JSONB{"equipped": equipped}
+	if equipped {
+		patch["equipped_at"] = time.Now().Format(time.RFC3339)
+		patch["slot"] = slot
+	}
+	return tx.Model(&common.InventoryItem{}).Where("id = ?", itemID).
+		Update("properties", gorm.Expr("COALESCE(properties, '{}'::jsonb) || ?::jsonb", patch)).Error
+}
+
+// BackfillLoadouts - gear označený properties.equipped sa presunie do player_loadouts.
+// Pri viacerých itemoch na jednom slote ostane vybavený najnovšie vybavený, ostatným sa príznak zruší.
+func BackfillLoadouts(db *gorm.DB) error {
+	var equipped []common.InventoryItem
+	if err := db.Where("deleted_at IS NULL AND properties->>'equipped' = 'true' AND id NOT IN (SELECT item_id FROM player_loadouts)").
+		Order("properties->>'equipped_at' DESC NULLS LAST").Find(&equipped).Error; err != nil {
+		return err
+	}
+
+	moved := 0
+	for _, item := range equipped {
+		def := Resolve(db, item)
+		if !def.Equippable() {
+			continue
+		}
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&common.PlayerLoadout{
+			UserID:     item.UserID,
+			Slot:       def.Slot,
+			ItemID:     item.ID,
+			EquippedAt: item.UpdatedAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		moved += int(result.RowsAffected)
+	}
+
+	if err := db.Exec(`
+		UPDATE inventory_items
+		SET properties = properties || '{"equipped": false}'::jsonb
+		WHERE properties->>'equipped' = 'true' AND id NOT IN (SELECT item_id FROM player_loadouts)
+	`).Error; err != nil {
+		return err
+	}
+
+	if moved > 0 {
+		log.Printf("🎽 Moved %d equipped items to player loadouts", moved)
+	}
+	return nil
+}
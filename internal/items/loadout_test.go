@@ -0,0 +1,46 @@
+package items
+
+import (
+	"math"
+	"testing"
+
+	"geoanomaly/internal/common"
+)
+
+func TestAggregateStats(t *testing.T) {
+	var gasMask, hazmat, geiger common.ItemDefinition
+	for _, def := range defaultDefinitions {
+		switch def.Key {
+		case "gear:gas_mask":
+			gasMask = def
+		case "gear:hazmat_suit":
+			hazmat = def
+		case "gear:geiger_counter":
+			geiger = def
+		}
+	}
+
+	stats := aggregateStats([]common.ItemDefinition{gasMask, hazmat, geiger})
+
+	if stats.Armor != 5 {
+		t.Errorf("armor = %d, want 5", stats.Armor)
+	}
+	if stats.InventorySlots != 5 {
+		t.Errorf("inventory slots = %d, want 5", stats.InventorySlots)
+	}
+	if stats.DetectionRange != 0.25 || stats.ScanRange != 0.1 {
+		t.Errorf("detection %.2f scan %.2f", stats.DetectionRange, stats.ScanRange)
+	}
+
+	// toxic_gas: maska 0.6 a oblek 0.3 sa skladajú na 1 - 0.4*0.7 = 0.72
+	if got := stats.Resist("toxic_gas"); math.Abs(got-0.72) > 1e-9 {
+		t.Errorf("toxic_gas resist = %.4f, want 0.72", got)
+	}
+	if got := stats.Resist("wild_animals"); got != 0 {
+		t.Errorf("wild_animals resist = %.2f, want 0", got)
+	}
+
+	if empty := aggregateStats(nil); empty.Armor != 0 || empty.Resist("toxic_gas") != 0 {
+		t.Errorf("empty loadout must have zero stats, got %+v", empty)
+	}
+}
@@ -3,12 +3,17 @@ package location
 import (
 	"context"
 	"fmt"
+	"log"
 	"math"
 	"net/http"
 	"strconv"
 	"time"
 
+	"geoanomaly/internal/achievements"
 	"geoanomaly/internal/common"
+	"geoanomaly/internal/game"
+	"geoanomaly/internal/quests"
+	"geoanomaly/internal/xp"
 	"geoanomaly/pkg/redis"
 
 	"github.com/gin-gonic/gin"
@@ -99,6 +104,10 @@ func (h *Handler) UpdateLocation(c *gin.Context) {
 	// Nájdi aktuálnu zónu
 	currentZone := h.findCurrentZone(req.Latitude, req.Longitude)
 
+	// ✅ NEW: Prejdená vzdialenosť od poslednej polohy (pred prepísaním session)
+	walked := h.distanceSinceLastUpdate(userID.(uuid.UUID), req.Latitude, req.Longitude)
+	exposure := h.timeInZoneSinceLastUpdate(userID.(uuid.UUID), currentZone)
+
 	// Aktualizuj player session
 	h.updatePlayerSession(userID.(uuid.UUID), username.(string), currentZone, location, req.Speed, req.Heading)
 
@@ -114,6 +123,40 @@ func (h *Handler) UpdateLocation(c *gin.Context) {
 		"timestamp":    time.Now().Unix(),
 	}
 
+	// ✅ NEW: Hazardy v zóne (zdravie, durability, strata itemu)
+	if currentZone != nil {
+		hits := game.NewHandler(h.db, h.redis).CheckHazards(userID.(uuid.UUID), *currentZone, req.Latitude, req.Longitude)
+		if len(hits) > 0 {
+			response["hazard_hits"] = hits
+		}
+
+		// ✅ NEW: Pobyt v nebezpečnej zóne opotrebúva vybavený gear
+		if worn := game.NewHandler(h.db, h.redis).ApplyZoneExposure(userID.(uuid.UUID), *currentZone, exposure); len(worn) > 0 {
+			response["worn_gear"] = worn
+		}
+	}
+
+	// ✅ NEW: XP za prejdené kilometre + walk questy
+	if walked >= 1 && walked <= xp.MaxWalkSegmentMeters {
+		if completed := quests.NewHandler(h.db).Track(userID.(uuid.UUID), quests.Event{
+			Type:   quests.EventWalk,
+			Amount: int(math.Round(walked)),
+		}); len(completed) > 0 {
+			response["quests_completed"] = completed
+		}
+
+		results, err := xp.NewHandler(h.db).RecordDistance(userID.(uuid.UUID), walked)
+		if err != nil {
+			log.Printf("❌ Failed to record distance: %v", err)
+		}
+		if len(results) > 0 {
+			response["distance_xp"] = results
+			if unlocked := achievements.NewHandler(h.db).TrackXPResults(userID.(uuid.UUID), results...); len(unlocked) > 0 {
+				response["achievements_unlocked"] = unlocked
+			}
+		}
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -265,10 +308,16 @@ func (h *Handler) findCurrentZone(lat, lng float64) *uuid.UUID {
 		return nil
 	}
 
-	// Manual distance filtering
+	return zoneAt(zones, lat, lng, time.Now())
+}
+
+// zoneAt - zóna, v ktorej hráč stojí; pohyblivé a zmenšujúce sa zóny podľa aktuálnej geometrie
+// (uložená poloha je z posledného ticku)
+func zoneAt(zones []common.Zone, lat, lng float64, now time.Time) *uuid.UUID {
 	for _, zone := range zones {
-		distance := calculateDistance(lat, lng, zone.Location.Latitude, zone.Location.Longitude)
-		if distance <= float64(zone.RadiusMeters) {
+		geometry := game.CurrentZoneGeometry(zone, now)
+		distance := calculateDistance(lat, lng, geometry.Center.Latitude, geometry.Center.Longitude)
+		if distance <= float64(geometry.RadiusMeters) {
 			return &zone.ID
 		}
 	}
@@ -304,6 +353,54 @@ func (h *Handler) updatePlayerSession(userID uuid.UUID, username string, current
 	h.updateRedisPlayerSession(userID, username, currentZone, location, speed, heading)
 }
 
+// ✅ NEW: Vzdialenosť od poslednej známej polohy; staršie polohy (pauza v hre) sa nepočítajú
+// Započíta sa len pešia rýchlosť (xp.CreditedDistance), inak by sa kilometre nabehali autom
+func (h *Handler) distanceSinceLastUpdate(userID uuid.UUID, lat, lng float64) float64 {
+	var session common.PlayerSession
+	if err := h.db.Where("user_id = ?", userID).First(&session).Error; err != nil {
+		return 0
+	}
+	if session.LastLocationTimestamp.IsZero() || time.Since(session.LastLocationTimestamp) > 10*time.Minute {
+		return 0
+	}
+	if session.LastLocationLatitude == 0 && session.LastLocationLongitude == 0 {
+		return 0
+	}
+
+	distance := calculateDistance(session.LastLocationLatitude, session.LastLocationLongitude, lat, lng)
+	return xp.CreditedDistance(distance, time.Since(session.LastLocationTimestamp))
+}
+
+// ✅ NEW: Čas od poslednej polohy, ak hráč zostal v tej istej zóne (pred prepísaním session)
+func (h *Handler) timeInZoneSinceLastUpdate(userID uuid.UUID, zoneID *uuid.UUID) time.Duration {
+	if zoneID == nil {
+		return 0
+	}
+
+	var session common.PlayerSession
+	if err := h.db.Where("user_id = ? AND current_zone = ?", userID, *zoneID).First(&session).Error; err != nil {
+		return 0
+	}
+	if session.LastLocationTimestamp.IsZero() {
+		return 0
+	}
+	return exposureWindow(time.Since(session.LastLocationTimestamp))
+}
+
+// Dlhšia medzera medzi update-mi polohy (app na pozadí) sa počíta najviac ako toto okno -
+// hráč v zóne zostal, takže expozícia nesmie spadnúť na nulu
+const maxExposureWindow = 10 * time.Minute
+
+func exposureWindow(elapsed time.Duration) time.Duration {
+	if elapsed < 0 {
+		return 0
+	}
+	if elapsed > maxExposureWindow {
+		return maxExposureWindow
+	}
+	return elapsed
+}
+
 // ✅ OPRAVENÉ: updateRedisPlayerSession s LocationWithAccuracy
 func (h *Handler) updateRedisPlayerSession(userID uuid.UUID, username string, currentZone *uuid.UUID, location common.LocationWithAccuracy, speed, heading float64) {
 	if h.redis == nil {
@@ -0,0 +1,45 @@
+package location
+
+import (
+	"testing"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/game"
+
+	"github.com/google/uuid"
+)
+
+func TestExposureWindow(t *testing.T) {
+	if got := exposureWindow(3 * time.Minute); got != 3*time.Minute {
+		t.Errorf("short gap = %v, want 3m", got)
+	}
+	if got := exposureWindow(45 * time.Minute); got != maxExposureWindow {
+		t.Errorf("long gap in the same zone = %v, want capped %v", got, maxExposureWindow)
+	}
+	if got := exposureWindow(-time.Second); got != 0 {
+		t.Errorf("clock skew = %v, want 0", got)
+	}
+}
+
+func TestZoneAtUsesMovingZoneGeometry(t *testing.T) {
+	now := time.Unix(1_700_000_600, 0)
+	zone := common.Zone{
+		BaseModel:    common.BaseModel{ID: uuid.New()},
+		Location:     common.Location{Latitude: 48.0, Longitude: 17.0},
+		RadiusMeters: 100,
+		Properties: common.JSONB{
+			"movement": game.ZoneMovement{Type: game.MovementTypePath, SpeedMPS: 2, StartedAt: now.Unix() - 300,
+				OriginLat: 48.0, OriginLng: 17.0, Path: [][2]float64{{48.0, 17.05}}},
+		},
+	}
+	live := game.CurrentZoneGeometry(zone, now).Center
+
+	if got := zoneAt([]common.Zone{zone}, live.Latitude, live.Longitude, now); got == nil || *got != zone.ID {
+		t.Errorf("player at the live center should be in the zone, got %v", got)
+	}
+	// Zóna sa za 5 min posunula o ~600m - uložený stred je už mimo
+	if got := zoneAt([]common.Zone{zone}, 48.0, 17.0, now); got != nil {
+		t.Errorf("player at the stale stored center should be outside, got %v", got)
+	}
+}
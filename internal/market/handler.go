@@ -0,0 +1,372 @@
+package market
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+	"geoanomaly/internal/wallet"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// BrowseListings - GET /market/listings?item_type=&definition_key=&rarity=&biome=&kind=&min_price=&max_price=&sort=&limit=&offset=
+func (h *Handler) BrowseListings(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(DefaultBrowsePage)))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > MaxBrowsePage {
+		limit = DefaultBrowsePage
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	minPrice, _ := strconv.ParseInt(c.Query("min_price"), 10, 64)
+	maxPrice, _ := strconv.ParseInt(c.Query("max_price"), 10, 64)
+
+	filter := BrowseFilter{
+		ItemType:      c.Query("item_type"),
+		DefinitionKey: c.Query("definition_key"),
+		Rarity:        c.Query("rarity"),
+		Biome:         c.Query("biome"),
+		Kind:          c.Query("kind"),
+		MinPrice:      minPrice,
+		MaxPrice:      maxPrice,
+		Sort:          c.Query("sort"),
+		Limit:         limit,
+		Offset:        offset,
+	}
+
+	listings, total, err := Browse(h.db, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch market listings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"listings": listings,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// GetListing - GET /market/listings/:id
+func (h *Handler) GetListing(c *gin.Context) {
+	listingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid listing UUID"})
+		return
+	}
+
+	var listing common.MarketListing
+	if err := h.db.First(&listing, "id = ?", listingID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Market listing not found"})
+		return
+	}
+
+	response := gin.H{"listing": listing}
+	if listing.Kind == common.ListingKindAuction && listing.Status == common.ListingStatusActive {
+		response["min_next_bid"] = minNextBid(listing)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMyListings - GET /market/my/listings?status=
+func (h *Handler) GetMyListings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	query := h.db.Where("seller_id = ?", userID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var listings []common.MarketListing
+	if err := query.Order("created_at DESC").Limit(100).Find(&listings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch market listings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"listings": listings,
+		"count":    len(listings),
+	})
+}
+
+// CreateListing - POST /market/listings {"item_id", "kind": "fixed" | "auction", "price", "duration_hours"}
+func (h *Handler) CreateListing(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var req ListingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	listing, err := CreateListing(h.db, userID.(uuid.UUID), req)
+	if err != nil {
+		respondMarketError(c, err, "create market listing")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":   true,
+		"message":   "Item listed on the market",
+		"listing":   listing,
+		"fee":       listing.ListingFee,
+		"balance":   wallet.Balance(h.db, listing.SellerID, common.CurrencyCredits),
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// CancelListing - DELETE /market/listings/:id
+func (h *Handler) CancelListing(c *gin.Context) {
+	userID, listingID, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	listing, err := Cancel(h.db, userID, listingID)
+	if err != nil {
+		respondMarketError(c, err, "cancel market listing")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Listing cancelled, item returned to inventory",
+		"listing": listing,
+	})
+}
+
+// BuyListing - POST /market/listings/:id/buy
+func (h *Handler) BuyListing(c *gin.Context) {
+	userID, listingID, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	result, err := Buy(h.db, userID, listingID)
+	if err != nil {
+		respondMarketError(c, err, "buy market listing")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Item purchased",
+		"listing":   result.Listing,
+		"sale":      result.Sale,
+		"balance":   wallet.Balance(h.db, userID, common.CurrencyCredits),
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// PlaceBid - POST /market/listings/:id/bid {"amount"}
+func (h *Handler) PlaceBid(c *gin.Context) {
+	userID, listingID, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	var req BidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	listing, err := Bid(h.db, userID, listingID, req.Amount)
+	if err != nil {
+		if errors.Is(err, ErrBidTooLow) {
+			response := gin.H{"error": err.Error()}
+			var current common.MarketListing
+			if h.db.First(&current, "id = ?", listingID).Error == nil {
+				response["min_next_bid"] = minNextBid(current)
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+		respondMarketError(c, err, "place bid")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"message":      "Bid placed",
+		"listing":      listing,
+		"min_next_bid": minNextBid(*listing),
+		"balance":      wallet.Balance(h.db, userID, common.CurrencyCredits),
+	})
+}
+
+// GetPriceHistory - GET /market/prices/:key?days=30
+func (h *Handler) GetPriceHistory(c *gin.Context) {
+	key := c.Param("key")
+	days, _ := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(DefaultHistoryDays)))
+	if days <= 0 || days > MaxHistoryDays {
+		days = DefaultHistoryDays
+	}
+
+	points, err := PriceHistory(h.db, key, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch price history"})
+		return
+	}
+
+	response := gin.H{
+		"definition_key": key,
+		"days":           days,
+		"history":        points,
+	}
+	var last common.MarketSale
+	if h.db.Where("definition_key = ? AND rolled_back_at IS NULL", key).Order("created_at DESC").First(&last).Error == nil {
+		response["last_price"] = last.Price / int64(max(last.Quantity, 1))
+		response["last_sold_at"] = last.CreatedAt
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSales - GET /admin/market/sales?user_id=&rolled_back=true
+func (h *Handler) GetSales(c *gin.Context) {
+	query := h.db.Model(&common.MarketSale{})
+	if raw := c.Query("user_id"); raw != "" {
+		userID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user UUID"})
+			return
+		}
+		query = query.Where("seller_id = ? OR buyer_id = ?", userID, userID)
+	}
+	if c.Query("rolled_back") == "true" {
+		query = query.Where("rolled_back_at IS NOT NULL")
+	}
+
+	var sales []common.MarketSale
+	if err := query.Order("created_at DESC").Limit(200).Find(&sales).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch market sales"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sales": sales,
+		"count": len(sales),
+	})
+}
+
+// DelistListing - POST /admin/market/listings/:id/delist {"reason"}
+func (h *Handler) DelistListing(c *gin.Context) {
+	adminID, listingID, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	var req AdminActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	listing, err := Delist(h.db, adminID, listingID, req.Reason)
+	if err != nil {
+		respondMarketError(c, err, "delist market listing")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Listing delisted",
+		"listing": listing,
+	})
+}
+
+// RollbackSale - POST /admin/market/sales/:id/rollback {"reason"}
+func (h *Handler) RollbackSale(c *gin.Context) {
+	adminID, saleID, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	var req AdminActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sale, err := RollbackSale(h.db, adminID, saleID, req.Reason)
+	if err != nil {
+		if errors.Is(err, wallet.ErrInsufficientFunds) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Seller no longer has the sale proceeds"})
+			return
+		}
+		respondMarketError(c, err, "roll back market sale")
+		return
+	}
+
+	response := gin.H{
+		"message": "Sale rolled back",
+		"sale":    sale,
+	}
+	if sale.RollbackShortfall > 0 {
+		response["message"] = "Sale rolled back, seller no longer had all of the proceeds"
+		response["shortfall"] = sale.RollbackShortfall
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// params - prihlásený hráč a :id z cesty
+func (h *Handler) params(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID.(uuid.UUID), id, true
+}
+
+func respondMarketError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, ErrListingNotFound), errors.Is(err, ErrSaleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrListingExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case errors.Is(err, wallet.ErrInsufficientFunds):
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrItemUnavailable), errors.Is(err, ErrItemEquipped), errors.Is(err, ErrHasBids),
+		errors.Is(err, ErrTooManyListings), errors.Is(err, ErrAlreadyRolledBack), errors.Is(err, ErrItemNotWithBuyer),
+		errors.Is(err, items.ErrItemInEscrow):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrOwnListing), errors.Is(err, ErrNotFixedPrice), errors.Is(err, ErrNotAuction),
+		errors.Is(err, ErrBidTooLow), errors.Is(err, ErrInvalidKind), errors.Is(err, ErrInvalidPrice),
+		errors.Is(err, ErrInvalidDuration):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("❌ Failed to %s: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + action})
+	}
+}
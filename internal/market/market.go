@@ -0,0 +1,524 @@
+package market
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+	"geoanomaly/internal/wallet"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateListing - vystaví item za pevnú cenu alebo do aukcie; poplatok sa stiahne hneď, item ide do escrow
+func CreateListing(db *gorm.DB, sellerID uuid.UUID, req ListingRequest) (*common.MarketListing, error) {
+	kind := req.Kind
+	if kind == "" {
+		kind = common.ListingKindFixed
+	}
+	if kind != common.ListingKindFixed && kind != common.ListingKindAuction {
+		return nil, ErrInvalidKind
+	}
+	if req.Price <= 0 || req.Price > MaxPrice {
+		return nil, ErrInvalidPrice
+	}
+	duration, err := listingDuration(kind, req.DurationHours)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing common.MarketListing
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var active int64
+		tx.Model(&common.MarketListing{}).Where("seller_id = ? AND status = ?", sellerID, common.ListingStatusActive).Count(&active)
+		if active >= MaxActiveListings {
+			return ErrTooManyListings
+		}
+
+		item, err := listableItem(tx, sellerID, req.ItemID)
+		if err != nil {
+			return err
+		}
+
+		def := items.Resolve(tx, *item)
+		name := def.Name
+		if custom, ok := item.Properties["name"].(string); ok && custom != "" {
+			name = custom
+		}
+		rarity, _ := item.Properties["rarity"].(string)
+		biome, _ := item.Properties["biome"].(string)
+
+		listing = common.MarketListing{
+			SellerID:      sellerID,
+			ItemID:        item.ID,
+			Kind:          kind,
+			Status:        common.ListingStatusActive,
+			ExpiresAt:     time.Now().Add(duration),
+			Price:         req.Price,
+			StartingPrice: req.Price,
+			ListingFee:    listingFee(req.Price),
+			DefinitionKey: def.Key,
+			ItemType:      item.ItemType,
+			Name:          name,
+			Rarity:        rarity,
+			Biome:         biome,
+			Quantity:      item.Quantity,
+		}
+
+		if err := tx.Create(&listing).Error; err != nil {
+			return err
+		}
+		if err := pay(tx, reasonListingFee, listing.ID,
+			wallet.Move(wallet.UserAccount(sellerID), common.AccountMarketFees, listing.ListingFee)...); err != nil {
+			return err
+		}
+		return items.LockEscrow(tx, common.EscrowSourceMarket, listing.ID, []uuid.UUID{item.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🏷️ %s listed %s (%s) for %d credits", sellerID, listing.Name, listing.Kind, listing.Price)
+	return &listing, nil
+}
+
+// Buy - okamžitý nákup; mena, item a záznam predaja sa zmenia v jednej transakcii
+func Buy(db *gorm.DB, buyerID, listingID uuid.UUID) (*SaleResult, error) {
+	var result *SaleResult
+	err := db.Transaction(func(tx *gorm.DB) error {
+		listing, err := lockActiveListing(tx, listingID)
+		if err != nil {
+			return err
+		}
+		if listing.Kind != common.ListingKindFixed {
+			return ErrNotFixedPrice
+		}
+		if listing.SellerID == buyerID {
+			return ErrOwnListing
+		}
+
+		result, err = settle(tx, listing, buyerID, wallet.UserAccount(buyerID), time.Now())
+		return err
+	})
+	return result, err
+}
+
+// Bid - príhoz v aukcii; suma sa stiahne hneď a predchádzajúci najvyšší príhoz sa vráti
+func Bid(db *gorm.DB, bidderID, listingID uuid.UUID, amount int64) (*common.MarketListing, error) {
+	var listing *common.MarketListing
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		listing, err = lockActiveListing(tx, listingID)
+		if err != nil {
+			return err
+		}
+		if listing.Kind != common.ListingKindAuction {
+			return ErrNotAuction
+		}
+		if listing.SellerID == bidderID {
+			return ErrOwnListing
+		}
+		if amount < minNextBid(*listing) || amount > MaxPrice {
+			return ErrBidTooLow
+		}
+
+		if err := refundHighBid(tx, listing); err != nil {
+			return err
+		}
+		if err := pay(tx, reasonBid, listing.ID,
+			wallet.Move(wallet.UserAccount(bidderID), common.AccountMarketEscrow, amount)...); err != nil {
+			return err
+		}
+		if err := tx.Create(&common.MarketBid{ListingID: listing.ID, BidderID: bidderID, Amount: amount}).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"price":          amount,
+			"bid_count":      gorm.Expr("bid_count + 1"),
+			"high_bidder_id": bidderID,
+		}
+		if extended := time.Now().Add(AuctionExtendWindow); listing.ExpiresAt.Before(extended) {
+			updates["expires_at"] = extended
+			listing.ExpiresAt = extended
+		}
+		if err := tx.Model(listing).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		listing.Price = amount
+		listing.BidCount++
+		listing.HighBidderID = &bidderID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
+
+// Cancel - predajca stiahne ponuku (aukciu len bez príhozov); poplatok sa nevracia
+func Cancel(db *gorm.DB, sellerID, listingID uuid.UUID) (*common.MarketListing, error) {
+	var listing *common.MarketListing
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		listing, err = lockActiveListing(tx, listingID)
+		if err != nil && !errors.Is(err, ErrListingExpired) {
+			return err
+		}
+		if listing.SellerID != sellerID {
+			return ErrListingNotFound
+		}
+		if listing.BidCount > 0 {
+			return ErrHasBids
+		}
+		return closeListing(tx, listing, common.ListingStatusCancelled, nil, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
+
+// Delist - admin stiahne aktívnu ponuku; najvyšší príhoz sa vráti, item sa vráti predajcovi
+func Delist(db *gorm.DB, adminID, listingID uuid.UUID, reason string) (*common.MarketListing, error) {
+	var listing *common.MarketListing
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		listing, err = lockActiveListing(tx, listingID)
+		if err != nil && !errors.Is(err, ErrListingExpired) {
+			return err
+		}
+		if err := refundHighBid(tx, listing); err != nil {
+			return err
+		}
+		return closeListing(tx, listing, common.ListingStatusDelisted, &adminID, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🚫 Listing %s delisted by %s: %s", listingID, adminID, reason)
+	return listing, nil
+}
+
+// RollbackSale - admin zruší podvodný predaj: item sa vráti predajcovi, predajcovi sa stiahne výnos
+// (koľko ešte má) a kupec dostane stiahnutú sumu plus daň z trhu; chýbajúca časť sa zapíše ako shortfall
+func RollbackSale(db *gorm.DB, adminID, saleID uuid.UUID, reason string) (*common.MarketSale, error) {
+	var sale common.MarketSale
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sale, "id = ?", saleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSaleNotFound
+			}
+			return err
+		}
+		if sale.RolledBackAt != nil {
+			return ErrAlreadyRolledBack
+		}
+
+		var item common.InventoryItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ? AND deleted_at IS NULL", sale.ItemID, sale.BuyerID).
+			First(&item).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrItemNotWithBuyer
+			}
+			return err
+		}
+		if items.InEscrow(tx, item.ID) {
+			return items.ErrItemInEscrow
+		}
+
+		// Zámok na peňaženke predajcu - zostatok sa medzi čítaním a stiahnutím nezmení
+		var sellerWallet common.Wallet
+		tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", sale.SellerID).First(&sellerWallet)
+		recovered, shortfall := rollbackRecovery(sale.Price-sale.Tax, sellerWallet.Credits)
+		sale.RollbackShortfall = shortfall
+
+		if recovered+sale.Tax > 0 {
+			if err := pay(tx, reasonRollback, sale.ListingID,
+				wallet.Entry{Account: wallet.UserAccount(sale.SellerID), Amount: -recovered},
+				wallet.Entry{Account: common.AccountMarketTax, Amount: -sale.Tax},
+				wallet.Entry{Account: wallet.UserAccount(sale.BuyerID), Amount: recovered + sale.Tax},
+			); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		if err := items.Transfer(tx, item.ID, sale.BuyerID, sale.SellerID, now); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&sale).Updates(map[string]interface{}{
+			"rolled_back_at":     now,
+			"rolled_back_by":     adminID,
+			"rollback_shortfall": shortfall,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&common.MarketListing{}).Where("id = ?", sale.ListingID).Updates(map[string]interface{}{
+			"status":       common.ListingStatusRolledBack,
+			"closed_by":    adminID,
+			"close_reason": reason,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if sale.RollbackShortfall > 0 {
+		log.Printf("⚠️ Market sale %s rolled back with shortfall %d (seller %s spent the proceeds)", saleID, sale.RollbackShortfall, sale.SellerID)
+	}
+	log.Printf("↩️ Market sale %s rolled back by %s: %s", saleID, adminID, reason)
+	return &sale, nil
+}
+
+// rollbackRecovery - koľko z výnosu sa dá predajcovi stiahnuť a koľko chýba
+func rollbackRecovery(proceeds, sellerBalance int64) (recovered, shortfall int64) {
+	recovered = min(proceeds, max(sellerBalance, 0))
+	return recovered, proceeds - recovered
+}
+
+// SettleExpired - skončené aukcie s príhozom sa predajú, ostatné ponuky expirujú (volá scheduler)
+func SettleExpired(db *gorm.DB) (sold int, expired int) {
+	var ids []uuid.UUID
+	if err := db.Model(&common.MarketListing{}).
+		Where("status = ? AND expires_at <= ?", common.ListingStatusActive, time.Now()).
+		Limit(expiredSettleBatch).Pluck("id", &ids).Error; err != nil {
+		log.Printf("❌ Failed to load expired market listings: %v", err)
+		return 0, 0
+	}
+
+	for _, id := range ids {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			listing, err := lockActiveListing(tx, id)
+			if !errors.Is(err, ErrListingExpired) {
+				return err // medzitým uzavretá alebo predĺžená
+			}
+			if listing.Kind == common.ListingKindAuction && listing.HighBidderID != nil {
+				if _, err := settle(tx, listing, *listing.HighBidderID, common.AccountMarketEscrow, time.Now()); err != nil {
+					return err
+				}
+				sold++
+				return nil
+			}
+			if err := closeListing(tx, listing, common.ListingStatusExpired, nil, ""); err != nil {
+				return err
+			}
+			expired++
+			return nil
+		})
+		if err != nil {
+			log.Printf("❌ Failed to settle market listing %s: %v", id, err)
+		}
+	}
+	return sold, expired
+}
+
+// Browse - aktívne ponuky s filtrami
+func Browse(db *gorm.DB, filter BrowseFilter) ([]common.MarketListing, int64, error) {
+	query := db.Model(&common.MarketListing{}).
+		Where("status = ? AND expires_at > ?", common.ListingStatusActive, time.Now())
+	if filter.ItemType != "" {
+		query = query.Where("item_type = ?", filter.ItemType)
+	}
+	if filter.DefinitionKey != "" {
+		query = query.Where("definition_key = ?", filter.DefinitionKey)
+	}
+	if filter.Rarity != "" {
+		query = query.Where("rarity = ?", filter.Rarity)
+	}
+	if filter.Biome != "" {
+		query = query.Where("biome = ?", filter.Biome)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.MinPrice > 0 {
+		query = query.Where("price >= ?", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		query = query.Where("price <= ?", filter.MaxPrice)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "price ASC"
+	switch filter.Sort {
+	case "price_desc":
+		order = "price DESC"
+	case "ending_soon":
+		order = "expires_at ASC"
+	case "newest":
+		order = "created_at DESC"
+	}
+
+	var listings []common.MarketListing
+	err := query.Order(order).Limit(filter.Limit).Offset(filter.Offset).Find(&listings).Error
+	return listings, total, err
+}
+
+// PriceHistory - denné ceny za kus pre typ itemu (bez zrušených predajov)
+func PriceHistory(db *gorm.DB, definitionKey string, days int) ([]PricePoint, error) {
+	var points []PricePoint
+	err := db.Model(&common.MarketSale{}).
+		Select(`TO_CHAR(DATE(created_at), 'YYYY-MM-DD') AS day,
+			COUNT(*) AS sales,
+			COALESCE(SUM(quantity), 0) AS volume,
+			MIN(price / GREATEST(quantity, 1)) AS min_price,
+			MAX(price / GREATEST(quantity, 1)) AS max_price,
+			AVG(price::float / GREATEST(quantity, 1)) AS avg_price`).
+		Where("definition_key = ? AND rolled_back_at IS NULL AND created_at > ?",
+			definitionKey, time.Now().AddDate(0, 0, -days)).
+		Group("DATE(created_at)").
+		Order("day").
+		Scan(&points).Error
+	return points, err
+}
+
+// listableItem - vlastnený, nezmazaný, nevybavený a nezamknutý item (FOR UPDATE)
+func listableItem(tx *gorm.DB, sellerID, itemID uuid.UUID) (*common.InventoryItem, error) {
+	var item common.InventoryItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", itemID, sellerID).
+		First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrItemUnavailable
+		}
+		return nil, err
+	}
+
+	var equipped int64
+	tx.Model(&common.PlayerLoadout{}).Where("item_id = ?", item.ID).Count(&equipped)
+	if equipped > 0 {
+		return nil, ErrItemEquipped
+	}
+	if items.InEscrow(tx, item.ID) {
+		return nil, items.ErrItemInEscrow
+	}
+	return &item, nil
+}
+
+// lockActiveListing - aktívna ponuka FOR UPDATE; po ExpiresAt vráti ponuku spolu s ErrListingExpired
+func lockActiveListing(tx *gorm.DB, listingID uuid.UUID) (*common.MarketListing, error) {
+	var listing common.MarketListing
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND status = ?", listingID, common.ListingStatusActive).
+		First(&listing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrListingNotFound
+		}
+		return nil, err
+	}
+	if !time.Now().Before(listing.ExpiresAt) {
+		return &listing, ErrListingExpired
+	}
+	return &listing, nil
+}
+
+// settle - predaj za listing.Price: item kupcovi, výnos bez dane predajcovi, záznam do histórie cien.
+// payer je účet kupca (Buy) alebo escrow trhu, kde je zadržaný víťazný príhoz (aukcia).
+func settle(tx *gorm.DB, listing *common.MarketListing, buyerID uuid.UUID, payer string, now time.Time) (*SaleResult, error) {
+	price := listing.Price
+	tax := saleTax(price)
+	if err := pay(tx, reasonPurchase, listing.ID,
+		wallet.Entry{Account: payer, Amount: -price},
+		wallet.Entry{Account: wallet.UserAccount(listing.SellerID), Amount: price - tax},
+		wallet.Entry{Account: common.AccountMarketTax, Amount: tax},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := items.Transfer(tx, listing.ItemID, listing.SellerID, buyerID, now); err != nil {
+		if errors.Is(err, items.ErrItemNotFound) {
+			return nil, ErrItemUnavailable
+		}
+		return nil, err
+	}
+	if err := items.ReleaseEscrow(tx, common.EscrowSourceMarket, listing.ID); err != nil {
+		return nil, err
+	}
+
+	sale := common.MarketSale{
+		ListingID:     listing.ID,
+		ItemID:        listing.ItemID,
+		SellerID:      listing.SellerID,
+		BuyerID:       buyerID,
+		Kind:          listing.Kind,
+		DefinitionKey: listing.DefinitionKey,
+		Rarity:        listing.Rarity,
+		Biome:         listing.Biome,
+		Quantity:      listing.Quantity,
+		Price:         price,
+		Tax:           tax,
+	}
+	if err := tx.Create(&sale).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(listing).Updates(map[string]interface{}{
+		"status":    common.ListingStatusSold,
+		"buyer_id":  buyerID,
+		"closed_at": now,
+	}).Error; err != nil {
+		return nil, err
+	}
+	listing.Status = common.ListingStatusSold
+	listing.BuyerID = &buyerID
+	listing.ClosedAt = &now
+
+	log.Printf("💰 Listing %s sold to %s for %d credits (tax %d)", listing.ID, buyerID, price, tax)
+	return &SaleResult{Listing: listing, Sale: &sale}, nil
+}
+
+// refundHighBid - vráti menu aktuálnemu najvyššiemu príhozcovi
+func refundHighBid(tx *gorm.DB, listing *common.MarketListing) error {
+	if listing.HighBidderID == nil || listing.BidCount == 0 {
+		return nil
+	}
+	if err := pay(tx, reasonBidRefund, listing.ID,
+		wallet.Move(common.AccountMarketEscrow, wallet.UserAccount(*listing.HighBidderID), listing.Price)...); err != nil {
+		return err
+	}
+	return tx.Model(&common.MarketBid{}).
+		Where("listing_id = ? AND bidder_id = ? AND amount = ? AND refunded = false", listing.ID, *listing.HighBidderID, listing.Price).
+		Update("refunded", true).Error
+}
+
+// pay - transakcia ledgeru v kreditoch s odkazom na ponuku
+func pay(tx *gorm.DB, reason string, listingID uuid.UUID, entries ...wallet.Entry) error {
+	_, err := wallet.Post(tx, wallet.Posting{
+		Currency:      common.CurrencyCredits,
+		Reason:        reason,
+		ReferenceType: "market_listing",
+		ReferenceID:   &listingID,
+		Entries:       entries,
+	})
+	return err
+}
+
+// closeListing - ukončí ponuku bez predaja a uvoľní item z escrow
+func closeListing(tx *gorm.DB, listing *common.MarketListing, status string, closedBy *uuid.UUID, reason string) error {
+	now := time.Now()
+	if err := tx.Model(listing).Updates(map[string]interface{}{
+		"status":       status,
+		"closed_at":    now,
+		"closed_by":    closedBy,
+		"close_reason": reason,
+	}).Error; err != nil {
+		return err
+	}
+	listing.Status = status
+	listing.ClosedAt = &now
+	listing.ClosedBy = closedBy
+	listing.CloseReason = reason
+	return items.ReleaseEscrow(tx, common.EscrowSourceMarket, listing.ID)
+}
@@ -0,0 +1,65 @@
+package market
+
+import (
+	"testing"
+	"time"
+
+	"geoanomaly/internal/common"
+)
+
+func TestFeesAndTax(t *testing.T) {
+	if got := listingFee(10); got != MinListingFee {
+		t.Errorf("listingFee(10) = %d, want minimum %d", got, MinListingFee)
+	}
+	if got := listingFee(1000); got != 20 {
+		t.Errorf("listingFee(1000) = %d, want 20", got)
+	}
+	if got := saleTax(1000); got != 50 {
+		t.Errorf("saleTax(1000) = %d, want 50", got)
+	}
+	if got := saleTax(19); got != 0 {
+		t.Errorf("saleTax(19) = %d, cheap sales round down to 0", got)
+	}
+}
+
+func TestMinNextBid(t *testing.T) {
+	listing := common.MarketListing{Price: 100, StartingPrice: 100}
+	if got := minNextBid(listing); got != 100 {
+		t.Errorf("first bid minimum = %d, want starting price", got)
+	}
+
+	listing.BidCount = 1
+	listing.Price = 200
+	if got := minNextBid(listing); got != 210 {
+		t.Errorf("next bid minimum = %d, want 210", got)
+	}
+
+	listing.Price = 3
+	if got := minNextBid(listing); got != 4 {
+		t.Errorf("next bid minimum on cheap auction = %d, want +1", got)
+	}
+}
+
+func TestListingDuration(t *testing.T) {
+	if d, err := listingDuration(common.ListingKindFixed, 0); err != nil || d != DefaultListingTTL {
+		t.Errorf("default duration = %s, %v", d, err)
+	}
+	if _, err := listingDuration(common.ListingKindAuction, 1000); err != ErrInvalidDuration {
+		t.Errorf("duration over %s should be rejected, got %v", MaxListingDuration, err)
+	}
+	if d, err := listingDuration(common.ListingKindAuction, 1); err != nil || d != time.Hour {
+		t.Errorf("one hour auction = %s, %v", d, err)
+	}
+}
+
+func TestRollbackRecovery(t *testing.T) {
+	if recovered, shortfall := rollbackRecovery(950, 2000); recovered != 950 || shortfall != 0 {
+		t.Errorf("seller with enough credits: recovered %d, shortfall %d", recovered, shortfall)
+	}
+	if recovered, shortfall := rollbackRecovery(950, 300); recovered != 300 || shortfall != 650 {
+		t.Errorf("seller who spent the proceeds: recovered %d, shortfall %d; want 300/650", recovered, shortfall)
+	}
+	if recovered, shortfall := rollbackRecovery(950, 0); recovered != 0 || shortfall != 950 {
+		t.Errorf("empty wallet: recovered %d, shortfall %d", recovered, shortfall)
+	}
+}
@@ -0,0 +1,132 @@
+package market
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrListingNotFound   = errors.New("market listing not found")
+	ErrListingExpired    = errors.New("market listing has expired")
+	ErrOwnListing        = errors.New("cannot buy or bid on your own listing")
+	ErrNotFixedPrice     = errors.New("listing is an auction, place a bid instead")
+	ErrNotAuction        = errors.New("listing is not an auction")
+	ErrBidTooLow         = errors.New("bid is lower than the minimum next bid")
+	ErrHasBids           = errors.New("auction already has bids and cannot be cancelled")
+	ErrInvalidKind       = errors.New("listing kind must be fixed or auction")
+	ErrInvalidPrice      = errors.New("price is out of range")
+	ErrInvalidDuration   = errors.New("listing duration is out of range")
+	ErrTooManyListings   = errors.New("too many active market listings")
+	ErrItemUnavailable   = errors.New("item is not available for listing")
+	ErrItemEquipped      = errors.New("unequip the item before listing it")
+	ErrSaleNotFound      = errors.New("market sale not found")
+	ErrAlreadyRolledBack = errors.New("sale was already rolled back")
+	ErrItemNotWithBuyer  = errors.New("item is no longer with the buyer")
+)
+
+const (
+	ListingFeeRate      = 0.02 // z ceny pri vystavení, nevracia sa
+	MinListingFee       = 1
+	SaleTaxRate         = 0.05 // z ceny predaja, predajca dostane zvyšok
+	MinBidIncrementRate = 0.05
+	MaxActiveListings   = 20
+	MaxPrice            = 1_000_000_000
+	DefaultListingTTL   = 72 * time.Hour
+	MinAuctionDuration  = time.Hour
+	MaxListingDuration  = 7 * 24 * time.Hour
+	AuctionExtendWindow = 5 * time.Minute // príhoz tesne pred koncom aukciu predĺži (anti-sniping)
+	DefaultHistoryDays  = 30
+	MaxHistoryDays      = 365
+	DefaultBrowsePage   = 50
+	MaxBrowsePage       = 200
+	expiredSettleBatch  = 500
+)
+
+// Dôvody transakcií ledgeru
+const (
+	reasonListingFee = "market_listing_fee"
+	reasonPurchase   = "market_purchase"
+	reasonBid        = "market_bid"
+	reasonBidRefund  = "market_bid_refund"
+	reasonRollback   = "market_rollback"
+)
+
+// ListingRequest - POST /market/listings
+type ListingRequest struct {
+	ItemID        uuid.UUID `json:"item_id" binding:"required"`
+	Kind          string    `json:"kind"` // fixed (default), auction
+	Price         int64     `json:"price" binding:"required"`
+	DurationHours int       `json:"duration_hours"`
+}
+
+// BidRequest - POST /market/listings/:id/bid
+type BidRequest struct {
+	Amount int64 `json:"amount" binding:"required"`
+}
+
+// AdminActionRequest - delist a rollback
+type AdminActionRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// BrowseFilter - GET /market/listings
+type BrowseFilter struct {
+	ItemType      string
+	DefinitionKey string
+	Rarity        string
+	Biome         string
+	Kind          string
+	MinPrice      int64
+	MaxPrice      int64
+	Sort          string // price_asc (default), price_desc, ending_soon, newest
+	Limit         int
+	Offset        int
+}
+
+// PricePoint - denná štatistika predajov typu itemu (cena za kus)
+type PricePoint struct {
+	Day      string  `json:"day"`
+	Sales    int     `json:"sales"`
+	Volume   int     `json:"volume"`
+	MinPrice int64   `json:"min_price"`
+	MaxPrice int64   `json:"max_price"`
+	AvgPrice float64 `json:"avg_price"`
+}
+
+// SaleResult - výsledok nákupu / vyhodnotenia aukcie
+type SaleResult struct {
+	Listing *common.MarketListing `json:"listing"`
+	Sale    *common.MarketSale    `json:"sale"`
+}
+
+func listingFee(price int64) int64 {
+	return max(MinListingFee, int64(math.Ceil(float64(price)*ListingFeeRate)))
+}
+
+func saleTax(price int64) int64 {
+	return int64(math.Floor(float64(price) * SaleTaxRate))
+}
+
+// minNextBid - prvý príhoz aspoň vyvolávacia cena, ďalšie o MinBidIncrementRate viac
+func minNextBid(listing common.MarketListing) int64 {
+	if listing.BidCount == 0 {
+		return listing.StartingPrice
+	}
+	return listing.Price + max(1, int64(math.Ceil(float64(listing.Price)*MinBidIncrementRate)))
+}
+
+func listingDuration(kind string, hours int) (time.Duration, error) {
+	if hours <= 0 {
+		return DefaultListingTTL, nil
+	}
+	duration := time.Duration(hours) * time.Hour
+	if duration > MaxListingDuration || (kind == common.ListingKindAuction && duration < MinAuctionDuration) {
+		return 0, ErrInvalidDuration
+	}
+	return duration, nil
+}
@@ -0,0 +1,131 @@
+package merchant
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+	"geoanomaly/internal/wallet"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// QuoteSell - POST /inventory/sell/quote {"item_ids"} alebo {"rarity", "item_type", "biome", "definition_key"}
+func (h *Handler) QuoteSell(c *gin.Context) {
+	h.preview(c, common.VendorActionSell)
+}
+
+// QuoteSalvage - POST /inventory/salvage/quote
+func (h *Handler) QuoteSalvage(c *gin.Context) {
+	h.preview(c, common.VendorActionSalvage)
+}
+
+// Sell - POST /inventory/sell {..., "confirm_token"}
+func (h *Handler) Sell(c *gin.Context) {
+	h.execute(c, common.VendorActionSell)
+}
+
+// Salvage - POST /inventory/salvage {..., "confirm_token"}
+func (h *Handler) Salvage(c *gin.Context) {
+	h.execute(c, common.VendorActionSalvage)
+}
+
+func (h *Handler) preview(c *gin.Context, action string) {
+	userID, sel, ok := bindSelection(c)
+	if !ok {
+		return
+	}
+
+	quote, err := Preview(h.db, userID, action, sel)
+	if err != nil {
+		respondError(c, action, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"quote":     quote,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+func (h *Handler) execute(c *gin.Context, action string) {
+	userID, sel, ok := bindSelection(c)
+	if !ok {
+		return
+	}
+
+	var result *Result
+	var err error
+	if action == common.VendorActionSell {
+		result, err = Sell(h.db, userID, sel)
+	} else {
+		result, err = Salvage(h.db, userID, sel)
+	}
+	if errors.Is(err, ErrConfirmationRequired) {
+		c.JSON(http.StatusPreconditionRequired, gin.H{
+			"error": err.Error(),
+			"code":  "CONFIRMATION_REQUIRED",
+			"quote": result.Quote,
+		})
+		return
+	}
+	if err != nil {
+		respondError(c, action, err)
+		return
+	}
+
+	response := gin.H{
+		"success":   true,
+		"result":    result,
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+	if action == common.VendorActionSell {
+		response["message"] = "Items sold"
+		response["balance"] = wallet.Balance(h.db, userID, common.CurrencyCredits)
+	} else {
+		response["message"] = "Items salvaged"
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func bindSelection(c *gin.Context) (uuid.UUID, Selection, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return uuid.Nil, Selection{}, false
+	}
+
+	var sel Selection
+	if err := c.ShouldBindJSON(&sel); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return uuid.Nil, Selection{}, false
+	}
+	return userID.(uuid.UUID), sel, true
+}
+
+func respondError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, ErrNoSelection), errors.Is(err, ErrTooManyItems):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrItemNotFound), errors.Is(err, ErrNothingSelected):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrFavoriteItem), errors.Is(err, ErrItemEquipped),
+		errors.Is(err, ErrNotSalvageable), errors.Is(err, items.ErrItemInEscrow):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("❌ Vendor %s failed: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + action + " items"})
+	}
+}
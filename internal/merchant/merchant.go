@@ -0,0 +1,302 @@
+package merchant
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+	"geoanomaly/internal/wallet"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Preview - náhľad predaja alebo rozobratia bez zmien; pri vzácnych itemoch vráti potvrdzovací token
+func Preview(db *gorm.DB, userID uuid.UUID, action string, sel Selection) (*Quote, error) {
+	selected, hasMore, err := selectItems(db, userID, action, sel, false)
+	if err != nil {
+		return nil, err
+	}
+	quote := buildQuote(db, action, selected, time.Now())
+	quote.HasMore = hasMore
+	if quote.ConfirmationRequired {
+		issueToken(quote, userID)
+	}
+	return quote, nil
+}
+
+// Sell - predá itemy obchodníkovi za kredity (celé stacky)
+func Sell(db *gorm.DB, userID uuid.UUID, sel Selection) (*Result, error) {
+	return execute(db, userID, common.VendorActionSell, sel)
+}
+
+// Salvage - rozoberie gear a artefakty na materiál na crafting
+func Salvage(db *gorm.DB, userID uuid.UUID, sel Selection) (*Result, error) {
+	return execute(db, userID, common.VendorActionSalvage, sel)
+}
+
+// execute - výber, ocenenie, zmazanie itemov a výplata v jednej transakcii.
+// Bez platného tokenu pri vzácnych itemoch vráti náhľad s novým tokenom a ErrConfirmationRequired.
+func execute(db *gorm.DB, userID uuid.UUID, action string, sel Selection) (*Result, error) {
+	now := time.Now()
+	result := &Result{BatchID: uuid.New()}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		selected, hasMore, err := selectItems(tx, userID, action, sel, true)
+		if err != nil {
+			return err
+		}
+		result.Quote = buildQuote(tx, action, selected, now)
+		result.HasMore = hasMore
+		// Token podpisuje náhľad - nové ocenenie s inými množstvami alebo sumou ho zneplatní
+		if result.ConfirmationRequired && !validToken(sel.ConfirmToken, userID, result.Quote, now) {
+			issueToken(result.Quote, userID)
+			return ErrConfirmationRequired
+		}
+
+		if err := tx.Model(&common.InventoryItem{}).
+			Where("id IN ? AND deleted_at IS NULL", itemIDs(selected)).
+			Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+
+		records := make([]common.VendorTransaction, 0, len(result.Items))
+		for _, line := range result.Items {
+			outputs := common.JSONB{}
+			for key, quantity := range line.Outputs {
+				outputs[key] = quantity
+			}
+			records = append(records, common.VendorTransaction{
+				BatchID:       result.BatchID,
+				UserID:        userID,
+				ItemID:        line.ItemID,
+				Action:        action,
+				DefinitionKey: line.DefinitionKey,
+				Rarity:        line.Rarity,
+				Biome:         line.Biome,
+				Quantity:      line.Quantity,
+				UnitPrice:     line.UnitPrice,
+				Total:         line.Total,
+				Outputs:       outputs,
+			})
+		}
+		if err := tx.Create(&records).Error; err != nil {
+			return err
+		}
+
+		if action == common.VendorActionSalvage {
+			result.Materials, err = items.Grant(tx, userID, result.Outputs,
+				common.JSONB{"salvaged": true, "salvage_batch": result.BatchID.String()}, now)
+			return err
+		}
+
+		_, err = wallet.Post(tx, wallet.Posting{
+			Currency:      common.CurrencyCredits,
+			Reason:        reasonVendorSell,
+			ReferenceType: referenceBatch,
+			ReferenceID:   &result.BatchID,
+			Entries:       wallet.Move(common.AccountVendor, wallet.UserAccount(userID), result.Total),
+		})
+		return err
+	})
+	if errors.Is(err, ErrConfirmationRequired) {
+		return result, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if action == common.VendorActionSell {
+		log.Printf("💰 %s sold %d items to vendor for %d credits", userID, result.ItemCount, result.Total)
+	} else {
+		log.Printf("🔧 %s salvaged %d items into %v", userID, result.ItemCount, result.Outputs)
+	}
+	return result, nil
+}
+
+// selectItems - itemy hráča podľa výberu, zoradené podľa ID (stabilné poradie zámkov a ocenenia).
+// Explicitne zvolený obľúbený, vybavený alebo zamknutý item je chyba; hromadný výber ich vynechá.
+func selectItems(tx *gorm.DB, userID uuid.UUID, action string, sel Selection, lock bool) ([]common.InventoryItem, bool, error) {
+	if sel.empty() {
+		return nil, false, ErrNoSelection
+	}
+	if len(sel.ItemIDs) > MaxBulkItems {
+		return nil, false, ErrTooManyItems
+	}
+
+	query := tx.Where("user_id = ? AND deleted_at IS NULL", userID).Order("id")
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var selected []common.InventoryItem
+	if !sel.bulk() {
+		ids := uniqueIDs(sel.ItemIDs)
+		if err := query.Where("id IN ?", ids).Find(&selected).Error; err != nil {
+			return nil, false, err
+		}
+		if len(selected) != len(ids) {
+			return nil, false, ErrItemNotFound
+		}
+		for _, item := range selected {
+			if err := checkExplicit(tx, action, item); err != nil {
+				return nil, false, err
+			}
+		}
+		return selected, false, nil
+	}
+
+	query = query.
+		Where("COALESCE(properties->>'favorite', 'false') <> 'true'").
+		Where("id NOT IN (SELECT item_id FROM player_loadouts)").
+		Where(items.NotInEscrow)
+	if sel.Rarity != "" {
+		query = query.Where("properties->>'rarity' = ?", sel.Rarity)
+	}
+	if sel.ItemType != "" {
+		query = query.Where("item_type = ?", sel.ItemType)
+	}
+	if sel.Biome != "" {
+		query = query.Where("COALESCE(properties->>'biome', properties->>'zone_biome') = ?", sel.Biome)
+	}
+	if sel.DefinitionKey != "" {
+		query = query.Where("definition_key = ?", sel.DefinitionKey)
+	}
+	if err := query.Limit(MaxBulkItems + 1).Find(&selected).Error; err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(selected) > MaxBulkItems
+	if hasMore {
+		selected = selected[:MaxBulkItems]
+	}
+	if action == common.VendorActionSalvage {
+		salvageable := selected[:0]
+		for _, item := range selected {
+			if _, ok := salvageOutputs(item, items.Resolve(tx, item)); ok {
+				salvageable = append(salvageable, item)
+			}
+		}
+		selected = salvageable
+	}
+	if len(selected) == 0 {
+		return nil, false, ErrNothingSelected
+	}
+	return selected, hasMore, nil
+}
+
+func checkExplicit(tx *gorm.DB, action string, item common.InventoryItem) error {
+	if favorite, _ := item.Properties["favorite"].(bool); favorite {
+		return ErrFavoriteItem
+	}
+	var equipped int64
+	tx.Model(&common.PlayerLoadout{}).Where("item_id = ?", item.ID).Count(&equipped)
+	if equipped > 0 {
+		return ErrItemEquipped
+	}
+	if items.InEscrow(tx, item.ID) {
+		return items.ErrItemInEscrow
+	}
+	if action == common.VendorActionSalvage {
+		if _, ok := salvageOutputs(item, items.Resolve(tx, item)); !ok {
+			return ErrNotSalvageable
+		}
+	}
+	return nil
+}
+
+// buildQuote - ocení výber; dopyt sa započíta aj za kusy predané skôr v tom istom výbere
+func buildQuote(tx *gorm.DB, action string, selected []common.InventoryItem, now time.Time) *Quote {
+	quote := &Quote{Action: action, ItemCount: len(selected), Outputs: map[string]int{}}
+
+	var sold map[string]int
+	if action == common.VendorActionSell {
+		sold = soldRecently(tx, selected, now)
+	}
+
+	for _, item := range selected {
+		def := items.Resolve(tx, item)
+		name := def.Name
+		if custom := stringProperty(item, "name"); custom != "" {
+			name = custom
+		}
+		line := QuoteLine{
+			ItemID:        item.ID,
+			DefinitionKey: def.Key,
+			Name:          name,
+			Rarity:        stringProperty(item, "rarity"),
+			Biome:         itemBiome(item),
+			Quantity:      max(1, item.Quantity),
+			NeedsConfirm:  needsConfirm(item),
+		}
+
+		if action == common.VendorActionSell {
+			line.UnitPrice, line.Demand = unitPrice(basePrice(item, def), sold[def.Key], line.Quantity)
+			line.Total = line.UnitPrice * int64(line.Quantity)
+			sold[def.Key] += line.Quantity
+			quote.Total += line.Total
+		} else {
+			line.Outputs, _ = salvageOutputs(item, def)
+			for key, quantity := range line.Outputs {
+				quote.Outputs[key] += quantity
+			}
+		}
+
+		quote.ConfirmationRequired = quote.ConfirmationRequired || line.NeedsConfirm
+		quote.Items = append(quote.Items, line)
+	}
+	return quote
+}
+
+// soldRecently - kusy predané obchodníkovi za DemandWindow podľa typu (všetci hráči)
+func soldRecently(tx *gorm.DB, selected []common.InventoryItem, now time.Time) map[string]int {
+	keys := make([]string, 0, len(selected))
+	for _, item := range selected {
+		keys = append(keys, items.Resolve(tx, item).Key)
+	}
+
+	var rows []struct {
+		DefinitionKey string
+		Sold          int
+	}
+	tx.Model(&common.VendorTransaction{}).
+		Select("definition_key, SUM(quantity) AS sold").
+		Where("action = ? AND created_at > ? AND definition_key IN ?", common.VendorActionSell, now.Add(-DemandWindow), keys).
+		Group("definition_key").
+		Scan(&rows)
+
+	sold := make(map[string]int, len(rows))
+	for _, row := range rows {
+		sold[row.DefinitionKey] = row.Sold
+	}
+	return sold
+}
+
+func issueToken(quote *Quote, userID uuid.UUID) {
+	expiresAt := time.Now().Add(ConfirmTokenTTL)
+	quote.ConfirmToken = confirmToken(userID, quote, expiresAt)
+	quote.ConfirmExpiresAt = &expiresAt
+}
+
+func itemIDs(selected []common.InventoryItem) []uuid.UUID {
+	ids := make([]uuid.UUID, len(selected))
+	for i, item := range selected {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,108 @@
+package merchant
+
+import (
+	"testing"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+)
+
+func TestBasePrice(t *testing.T) {
+	artifact := common.ItemDefinition{Key: "artifact:uranium_ore", Category: common.ItemCategoryArtifact}
+	plain := common.InventoryItem{Properties: common.JSONB{"rarity": "common", "biome": "forest"}}
+	if got := basePrice(plain, artifact); got != 10 {
+		t.Errorf("common forest artifact = %v, want 10", got)
+	}
+
+	legendary := common.InventoryItem{Properties: common.JSONB{"rarity": "legendary", "biome": "radioactive", "zone_tier": float64(2)}}
+	if got := basePrice(legendary, artifact); got != 600*1.5*1.2 {
+		t.Errorf("legendary radioactive tier 2 artifact = %v, want %v", got, 600*1.5*1.2)
+	}
+
+	gear := common.ItemDefinition{Key: "gear:gas_mask", Category: common.ItemCategoryGear, Slot: common.SlotHead}
+	intact := common.InventoryItem{Properties: common.JSONB{"level": 2}}
+	broken := common.InventoryItem{Properties: common.JSONB{"level": 2, "durability": float64(0)}}
+	if got := basePrice(intact, gear); got != 40 {
+		t.Errorf("level 2 gear = %v, want 40", got)
+	}
+	if got := basePrice(broken, gear); got != 20 {
+		t.Errorf("broken level 2 gear = %v, want 20", got)
+	}
+}
+
+func TestDemandLowersPrice(t *testing.T) {
+	fresh, demand := unitPrice(100, 0, 1)
+	if fresh != 100 || demand < 0.99 {
+		t.Errorf("price without recent sales = %d (demand %.2f), want ~100", fresh, demand)
+	}
+	flooded, _ := unitPrice(100, 100, 1)
+	if flooded >= fresh {
+		t.Errorf("price after 100 sales = %d, should drop below %d", flooded, fresh)
+	}
+	if floor, _ := unitPrice(100, 1_000_000, 1); floor != int64(100*MinDemandFactor) {
+		t.Errorf("flooded price = %d, want floor %d", floor, int64(100*MinDemandFactor))
+	}
+	if cheap, _ := unitPrice(0.4, 0, 1); cheap != 1 {
+		t.Errorf("minimum price = %d, want 1", cheap)
+	}
+}
+
+func TestSalvageOutputs(t *testing.T) {
+	ore := common.ItemDefinition{Key: "artifact:crystal_shard", Category: common.ItemCategoryArtifact}
+	outputs, ok := salvageOutputs(common.InventoryItem{Quantity: 2, Properties: common.JSONB{"rarity": "epic", "biome": "mountain"}}, ore)
+	if !ok || outputs["artifact:mineral_ore"] != 6 {
+		t.Errorf("2x epic mountain artifact = %v, %v; want 6 mineral ore", outputs, ok)
+	}
+
+	material := common.ItemDefinition{Key: "artifact:mineral_ore", Category: common.ItemCategoryArtifact}
+	if _, ok := salvageOutputs(common.InventoryItem{Properties: common.JSONB{"biome": "mountain"}}, material); ok {
+		t.Error("material must not salvage into itself")
+	}
+
+	consumable := common.ItemDefinition{Key: "gear:first_aid_kit", Category: common.ItemCategoryConsumable}
+	if _, ok := salvageOutputs(common.InventoryItem{}, consumable); ok {
+		t.Error("consumables cannot be salvaged")
+	}
+
+	detector := common.ItemDefinition{Key: "gear:geiger_counter", Category: common.ItemCategoryGear, Slot: common.SlotDetector}
+	outputs, ok = salvageOutputs(common.InventoryItem{Properties: common.JSONB{"level": 5}}, detector)
+	if !ok || outputs["artifact:electronic_component"] != 3 || outputs["artifact:machinery_parts"] != 3 {
+		t.Errorf("level 5 detector = %v, %v", outputs, ok)
+	}
+}
+
+func TestConfirmToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	userID := uuid.New()
+	a := QuoteLine{ItemID: uuid.New(), Quantity: 1}
+	b := QuoteLine{ItemID: uuid.New(), Quantity: 5}
+	quote := func(total int64, lines ...QuoteLine) *Quote {
+		return &Quote{Action: common.VendorActionSell, Items: lines, Total: total}
+	}
+	now := time.Now()
+	token := confirmToken(userID, quote(500, a, b), now.Add(ConfirmTokenTTL))
+
+	if !validToken(token, userID, quote(500, b, a), now) {
+		t.Error("token should not depend on item order")
+	}
+	if validToken(token, userID, &Quote{Action: common.VendorActionSalvage, Items: []QuoteLine{a, b}, Total: 500}, now) {
+		t.Error("sell token accepted for salvage")
+	}
+	if validToken(token, userID, quote(500, a), now) {
+		t.Error("token accepted for a different selection")
+	}
+	if validToken(token, userID, quote(500, a, QuoteLine{ItemID: b.ItemID, Quantity: 50}), now) {
+		t.Error("token accepted after the stack quantity changed")
+	}
+	if validToken(token, userID, quote(350, a, b), now) {
+		t.Error("token accepted for a different quoted total")
+	}
+	if validToken(token, uuid.New(), quote(500, a, b), now) {
+		t.Error("token accepted for another user")
+	}
+	if validToken(token, userID, quote(500, a, b), now.Add(ConfirmTokenTTL+time.Second)) {
+		t.Error("expired token accepted")
+	}
+}
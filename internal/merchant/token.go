@@ -0,0 +1,48 @@
+package merchant
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Potvrdzovací token je bezstavový: HMAC nad hráčom, akciou, itemami s množstvami, ocenenou sumou a expiráciou.
+// Execute výber znova ocení - ak sa výber, množstvo alebo cena medzi náhľadom a vykonaním zmení,
+// token neplatí a hráč dostane nový náhľad.
+
+func confirmToken(userID uuid.UUID, quote *Quote, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expires + "." + signature(userID, quote, expires)
+}
+
+func validToken(token string, userID uuid.UUID, quote *Quote, now time.Time) bool {
+	expires, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > unix {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(signature(userID, quote, expires)))
+}
+
+func signature(userID uuid.UUID, quote *Quote, expires string) string {
+	lines := make([]string, len(quote.Items))
+	for i, line := range quote.Items {
+		lines[i] = fmt.Sprintf("%s:%d", line.ItemID, line.Quantity)
+	}
+	sort.Strings(lines)
+
+	mac := hmac.New(sha256.New, []byte(os.Getenv("JWT_SECRET")))
+	fmt.Fprintf(mac, "%s|%s|%s|%d|%s", userID, quote.Action, strings.Join(lines, ","), quote.Total, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
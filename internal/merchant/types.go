@@ -0,0 +1,237 @@
+package merchant
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoSelection          = errors.New("select items by id or by rarity, item_type, biome or definition_key")
+	ErrNothingSelected      = errors.New("no matching items to sell or salvage")
+	ErrTooManyItems         = errors.New("too many items in one request")
+	ErrItemNotFound         = errors.New("item not found")
+	ErrFavoriteItem         = errors.New("item is marked as favorite")
+	ErrItemEquipped         = errors.New("unequip the item first")
+	ErrNotSalvageable       = errors.New("item cannot be salvaged")
+	ErrConfirmationRequired = errors.New("selection contains rare items, confirm with confirm_token")
+)
+
+const (
+	MaxBulkItems     = 200
+	ConfirmTokenTTL  = 5 * time.Minute
+	DemandWindow     = 24 * time.Hour
+	DemandSaturation = 100.0 // pri toľkých predaných kusoch za DemandWindow klesne cena na polovicu
+	MinDemandFactor  = 0.2
+)
+
+const (
+	reasonVendorSell = "vendor_sell"
+	referenceBatch   = "vendor_batch"
+)
+
+// Základná výkupná cena artefaktu podľa rarity (kredity za kus)
+var rarityPrices = map[string]float64{
+	"common":    10,
+	"rare":      40,
+	"epic":      150,
+	"legendary": 600,
+}
+
+const (
+	gearPricePerLevel = 20
+	consumablePrice   = 15
+	tierPriceBonus    = 0.1 // +10 % za každý tier zóny, v ktorej bol item nájdený
+	brokenGearFactor  = 0.5
+)
+
+// Nebezpečnejšie biómy platia viac
+var biomePriceMultipliers = map[string]float64{
+	"forest":      1.0,
+	"mountain":    1.1,
+	"urban":       1.1,
+	"water":       1.2,
+	"industrial":  1.2,
+	"chemical":    1.4,
+	"radioactive": 1.5,
+}
+
+// Rozobratie: kusy materiálu podľa rarity artefaktu
+var rarityYields = map[string]int{
+	"common":    1,
+	"rare":      2,
+	"epic":      3,
+	"legendary": 5,
+}
+
+// Materiál, na ktorý sa rozoberie artefakt daného biómu
+var biomeMaterials = map[string]string{
+	"forest":      "artifact:herbal_extract",
+	"mountain":    "artifact:mineral_ore",
+	"industrial":  "artifact:machinery_parts",
+	"urban":       "artifact:electronics",
+	"water":       "artifact:filtered_water",
+	"radioactive": "artifact:uranium_ore",
+	"chemical":    "artifact:chemical_compound",
+}
+
+// Rarity, ktoré sa predajú / rozoberú až po potvrdení
+var confirmRarities = map[string]bool{
+	"rare":      true,
+	"epic":      true,
+	"legendary": true,
+}
+
+// Selection - výber itemov: konkrétne item_ids, alebo hromadne podľa filtra ("predaj všetky common")
+type Selection struct {
+	ItemIDs       []uuid.UUID `json:"item_ids"`
+	Rarity        string      `json:"rarity"`
+	ItemType      string      `json:"item_type"`
+	Biome         string      `json:"biome"`
+	DefinitionKey string      `json:"definition_key"`
+	ConfirmToken  string      `json:"confirm_token"`
+}
+
+func (s Selection) bulk() bool {
+	return len(s.ItemIDs) == 0
+}
+
+func (s Selection) empty() bool {
+	return s.bulk() && s.Rarity == "" && s.ItemType == "" && s.Biome == "" && s.DefinitionKey == ""
+}
+
+// QuoteLine - jeden item v náhľade
+type QuoteLine struct {
+	ItemID        uuid.UUID      `json:"item_id"`
+	DefinitionKey string         `json:"definition_key"`
+	Name          string         `json:"name"`
+	Rarity        string         `json:"rarity,omitempty"`
+	Biome         string         `json:"biome,omitempty"`
+	Quantity      int            `json:"quantity"`
+	UnitPrice     int64          `json:"unit_price,omitempty"`
+	Total         int64          `json:"total,omitempty"`
+	Demand        float64        `json:"demand,omitempty"` // 1.0 = plná cena, klesá s predajmi typu
+	Outputs       map[string]int `json:"outputs,omitempty"`
+	NeedsConfirm  bool           `json:"needs_confirmation"`
+}
+
+// Quote - náhľad predaja / rozobratia; ConfirmToken je vyplnený len ak výber obsahuje vzácne itemy
+type Quote struct {
+	Action               string         `json:"action"`
+	Items                []QuoteLine    `json:"items"`
+	ItemCount            int            `json:"item_count"`
+	Total                int64          `json:"total,omitempty"`
+	Outputs              map[string]int `json:"outputs,omitempty"`
+	HasMore              bool           `json:"has_more"` // hromadný výber bol orezaný na MaxBulkItems
+	ConfirmationRequired bool           `json:"confirmation_required"`
+	ConfirmToken         string         `json:"confirm_token,omitempty"`
+	ConfirmExpiresAt     *time.Time     `json:"confirm_expires_at,omitempty"`
+}
+
+// Result - vykonaný predaj / rozobratie
+type Result struct {
+	*Quote
+	BatchID   uuid.UUID              `json:"batch_id"`
+	Materials []common.InventoryItem `json:"materials,omitempty"`
+}
+
+// basePrice - cena za kus pred započítaním dopytu
+func basePrice(item common.InventoryItem, def common.ItemDefinition) float64 {
+	var price float64
+	switch def.Category {
+	case common.ItemCategoryConsumable:
+		price = consumablePrice
+	case common.ItemCategoryGear:
+		price = gearPricePerLevel * float64(max(1, intProperty(item, "level")))
+		if items.IsBroken(item) {
+			price *= brokenGearFactor
+		}
+	default:
+		price = rarityPrices["common"]
+		if p, ok := rarityPrices[stringProperty(item, "rarity")]; ok {
+			price = p
+		}
+	}
+
+	if multiplier, ok := biomePriceMultipliers[itemBiome(item)]; ok {
+		price *= multiplier
+	}
+	return price * (1 + tierPriceBonus*float64(max(0, intProperty(item, "zone_tier"))))
+}
+
+// demandFactor - čím viac kusov typu sa nedávno predalo, tým nižšia cena (nikdy pod MinDemandFactor)
+func demandFactor(sold float64) float64 {
+	return math.Max(MinDemandFactor, 1/(1+math.Max(0, sold)/DemandSaturation))
+}
+
+// unitPrice - stack sa oceňuje dopytom v jeho strede, takže predaj po kusoch ani naraz nie je výhodnejší
+func unitPrice(base float64, soldBefore, quantity int) (int64, float64) {
+	demand := demandFactor(float64(soldBefore) + float64(quantity)/2)
+	return max(1, int64(math.Round(base*demand))), demand
+}
+
+// salvageOutputs - materiál z rozobratia itemu; false = item sa rozobrať nedá
+func salvageOutputs(item common.InventoryItem, def common.ItemDefinition) (map[string]int, bool) {
+	quantity := max(1, item.Quantity)
+	switch def.Category {
+	case common.ItemCategoryGear:
+		units := max(1, (intProperty(item, "level")+1)/2)
+		if items.IsBroken(item) {
+			units = max(1, units/2)
+		}
+		secondary := "artifact:steel_ingot"
+		if def.Slot == common.SlotDetector {
+			secondary = "artifact:electronic_component"
+		}
+		return map[string]int{"artifact:machinery_parts": units * quantity, secondary: units * quantity}, true
+	case common.ItemCategoryArtifact:
+		material, ok := biomeMaterials[itemBiome(item)]
+		if !ok || material == def.Key {
+			return nil, false
+		}
+		units, ok := rarityYields[stringProperty(item, "rarity")]
+		if !ok {
+			units = 1
+		}
+		return map[string]int{material: units * quantity}, true
+	}
+	return nil, false
+}
+
+func needsConfirm(item common.InventoryItem) bool {
+	return confirmRarities[stringProperty(item, "rarity")]
+}
+
+func itemBiome(item common.InventoryItem) string {
+	if biome := stringProperty(item, "biome"); biome != "" {
+		return biome
+	}
+	return stringProperty(item, "zone_biome")
+}
+
+func stringProperty(item common.InventoryItem, key string) string {
+	value, _ := item.Properties[key].(string)
+	return value
+}
+
+func intProperty(item common.InventoryItem, key string) int {
+	return int(number(item.Properties[key]))
+}
+
+// number - JSON čísla prichádzajú ako float64, čerstvo nastavené properties ako int
+func number(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
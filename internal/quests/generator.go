@@ -0,0 +1,302 @@
+package quests
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Predvolené šablóny - zapíšu sa do quest_templates, ak tam ešte nie sú
+var defaultTemplates = []common.QuestTemplate{
+	// Denné
+	{Key: "daily_collect_biome", Title: "Collect 5 artifacts in {biome} zones", Period: PeriodDaily, Event: EventCollect,
+		Filter: common.JSONB{"item_type": "artifact", "biome": "*"}, Target: 5, RewardXP: 60, Weight: 3},
+	{Key: "daily_collect_items", Title: "Collect 8 items", Period: PeriodDaily, Event: EventCollect,
+		Target: 8, RewardXP: 50, Weight: 2},
+	{Key: "daily_collect_gear", Title: "Collect 3 gear items", Period: PeriodDaily, Event: EventCollect,
+		Filter: common.JSONB{"item_type": "gear"}, Target: 3, RewardXP: 40, Weight: 1},
+	{Key: "daily_walk", Title: "Walk 3 km", Period: PeriodDaily, Event: EventWalk,
+		Target: 3000, RewardXP: 50, Weight: 3},
+	{Key: "daily_enter_zones", Title: "Enter 3 zones", Period: PeriodDaily, Event: EventEnterZone,
+		Target: 3, RewardXP: 40, Weight: 2},
+	{Key: "daily_clear_zone", Title: "Clear a zone", Period: PeriodDaily, Event: EventZoneClear,
+		Target: 1, RewardXP: 70, Weight: 1},
+
+	// Týždenné
+	{Key: "weekly_collect_biome", Title: "Collect 25 artifacts in {biome} zones", Period: PeriodWeekly, Event: EventCollect,
+		Filter: common.JSONB{"item_type": "artifact", "biome": "*"}, Target: 25, RewardXP: 300, Weight: 2},
+	{Key: "weekly_walk", Title: "Walk 20 km", Period: PeriodWeekly, Event: EventWalk,
+		Target: 20000, RewardXP: 300, Weight: 2},
+	{Key: "weekly_clear_zones", Title: "Clear 3 zones", Period: PeriodWeekly, Event: EventZoneClear,
+		Target: 3, RewardXP: 250, Weight: 2},
+	{Key: "weekly_clear_tier2", Title: "Clear a tier 2 zone", Period: PeriodWeekly, Event: EventZoneClear,
+		Filter: common.JSONB{"min_zone_tier": 2}, Target: 1, MinTier: 2, RewardXP: 350, Weight: 2},
+	{Key: "weekly_epic_hunter", Title: "Find 3 epic artifacts", Period: PeriodWeekly, Event: EventCollect,
+		Filter: common.JSONB{"item_type": "artifact", "rarity": "epic"}, Target: 3, MinTier: 1, RewardXP: 400, Weight: 1},
+}
+
+// SeedTemplates - doplní chýbajúce predvolené šablóny (existujúce nemení)
+func SeedTemplates(db *gorm.DB) error {
+	for _, template := range defaultTemplates {
+		template := template
+		template.IsActive = true
+		result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).Create(&template)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("📜 Seeded quest template: %s", template.Key)
+		}
+	}
+	return nil
+}
+
+// userLocation - časové pásmo hráča (neplatné/prázdne = UTC)
+func userLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// periodWindow - kľúč a koniec aktuálneho obdobia v čase hráča (týždeň začína pondelkom)
+func periodWindow(period string, now time.Time, loc *time.Location) (string, time.Time) {
+	local := now.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	if period == PeriodWeekly {
+		year, week := local.ISOWeek()
+		daysSinceMonday := (int(local.Weekday()) + 6) % 7
+		weekStart := midnight.AddDate(0, 0, -daysSinceMonday)
+		return fmt.Sprintf("%d-W%02d", year, week), weekStart.AddDate(0, 0, 7)
+	}
+
+	return local.Format("2006-01-02"), midnight.AddDate(0, 0, 1)
+}
+
+// questPeriod - kľúč a koniec obdobia, pre ktoré má hráč vygenerované questy
+type questPeriod struct {
+	Key       string
+	ExpiresAt time.Time
+}
+
+// Vygenerované obdobia hráčov (user_id:period) - ensureQuests beží pri každej udalosti,
+// takže bez cache by každý update polohy robil COUNT nad player_quests
+var generatedPeriods = struct {
+	sync.RWMutex
+	periods map[string]questPeriod
+}{periods: make(map[string]questPeriod)}
+
+func cachedPeriod(userID uuid.UUID, period string, now time.Time) (questPeriod, bool) {
+	generatedPeriods.RLock()
+	defer generatedPeriods.RUnlock()
+	cached, ok := generatedPeriods.periods[userID.String()+":"+period]
+	if !ok || !now.Before(cached.ExpiresAt) {
+		return questPeriod{}, false
+	}
+	return cached, true
+}
+
+func rememberPeriod(userID uuid.UUID, period string, current questPeriod, now time.Time) {
+	generatedPeriods.Lock()
+	defer generatedPeriods.Unlock()
+	if len(generatedPeriods.periods) >= periodCacheSweepSize {
+		for key, cached := range generatedPeriods.periods {
+			if !now.Before(cached.ExpiresAt) {
+				delete(generatedPeriods.periods, key)
+			}
+		}
+	}
+	generatedPeriods.periods[userID.String()+":"+period] = current
+}
+
+// currentPeriod - obdobie s už vygenerovanými questami, ak ešte neskončilo; inak nové okno v čase hráča.
+// Obdobie je pripnuté k pásmu z času generovania - zmena timezone neotvorí druhú sadu questov v ten istý deň.
+func (h *Handler) currentPeriod(user common.User, period string, now time.Time) (questPeriod, bool, error) {
+	if cached, ok := cachedPeriod(user.ID, period, now); ok {
+		return cached, true, nil
+	}
+
+	var latest common.PlayerQuest
+	err := h.db.Select("period_key", "expires_at").
+		Where("user_id = ? AND period = ? AND expires_at > ?", user.ID, period, now).
+		Order("expires_at DESC").
+		First(&latest).Error
+	if err == nil {
+		current := questPeriod{Key: latest.PeriodKey, ExpiresAt: latest.ExpiresAt}
+		rememberPeriod(user.ID, period, current, now)
+		return current, true, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return questPeriod{}, false, err
+	}
+
+	key, expiresAt := periodWindow(period, now, userLocation(user.Timezone))
+	current := questPeriod{Key: key, ExpiresAt: expiresAt}
+
+	// Rovnaký kľúč mohol patriť sade z iného pásma, ktorá už vypršala
+	var existing int64
+	if err := h.db.Model(&common.PlayerQuest{}).
+		Where("user_id = ? AND period = ? AND period_key = ?", user.ID, period, key).
+		Count(&existing).Error; err != nil {
+		return questPeriod{}, false, err
+	}
+	return current, existing > 0, nil
+}
+
+// ensureQuests - vygeneruje questy pre aktuálne denné a týždenné obdobie, ak ešte neexistujú
+func (h *Handler) ensureQuests(user common.User, now time.Time) error {
+	for _, period := range []string{PeriodDaily, PeriodWeekly} {
+		current, generated, err := h.currentPeriod(user, period, now)
+		if err != nil {
+			return err
+		}
+		if generated {
+			continue
+		}
+		periodKey, expiresAt := current.Key, current.ExpiresAt
+
+		var templates []common.QuestTemplate
+		if err := h.db.Where("period = ? AND is_active = true AND min_tier <= ?", period, user.ProgressionTier()).
+			Order("key ASC").Find(&templates).Error; err != nil {
+			return err
+		}
+
+		count := DailyQuestCount
+		if period == PeriodWeekly {
+			count = WeeklyQuestCount
+		}
+
+		rng := rand.New(rand.NewSource(questSeed(user.ID, periodKey)))
+		biomes := h.nearbyBiomes(user.ID, user.ProgressionTier())
+
+		for _, template := range pickTemplates(templates, biomes, count, rng) {
+			quest := buildQuest(user.ID, template, period, periodKey, expiresAt, biomes, rng)
+			if err := h.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&quest).Error; err != nil {
+				return err
+			}
+		}
+		rememberPeriod(user.ID, period, current, now)
+	}
+
+	return nil
+}
+
+// Deterministický výber - opakované generovanie v tom istom období dá rovnaké questy
+func questSeed(userID uuid.UUID, periodKey string) int64 {
+	hasher := fnv.New64a()
+	hasher.Write(userID[:])
+	hasher.Write([]byte(periodKey))
+	return int64(hasher.Sum64())
+}
+
+// pickTemplates - vážený výber bez opakovania; "{biome}" šablóny len ak je v okolí nejaký biom
+func pickTemplates(templates []common.QuestTemplate, biomes []string, count int, rng *rand.Rand) []common.QuestTemplate {
+	var pool []common.QuestTemplate
+	for _, template := range templates {
+		if needsBiome(template) && len(biomes) == 0 {
+			continue
+		}
+		pool = append(pool, template)
+	}
+
+	var picked []common.QuestTemplate
+	for len(picked) < count && len(pool) > 0 {
+		totalWeight := 0
+		for _, template := range pool {
+			totalWeight += maxInt(template.Weight, 1)
+		}
+
+		roll := rng.Intn(totalWeight)
+		for i, template := range pool {
+			roll -= maxInt(template.Weight, 1)
+			if roll < 0 {
+				picked = append(picked, template)
+				pool = append(pool[:i], pool[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return picked
+}
+
+func buildQuest(userID uuid.UUID, template common.QuestTemplate, period, periodKey string, expiresAt time.Time, biomes []string, rng *rand.Rand) common.PlayerQuest {
+	filter := common.JSONB{}
+	for key, value := range template.Filter {
+		filter[key] = value
+	}
+
+	title := template.Title
+	if needsBiome(template) {
+		biome := biomes[rng.Intn(len(biomes))]
+		filter["biome"] = biome
+		title = strings.ReplaceAll(title, "{biome}", biome)
+	}
+
+	return common.PlayerQuest{
+		UserID:      userID,
+		TemplateKey: template.Key,
+		Period:      period,
+		PeriodKey:   periodKey,
+		Title:       title,
+		Event:       template.Event,
+		Filter:      filter,
+		Target:      template.Target,
+		RewardXP:    template.RewardXP,
+		RewardItem:  template.RewardItem,
+		Status:      StatusActive,
+		ExpiresAt:   expiresAt,
+	}
+}
+
+func needsBiome(template common.QuestTemplate) bool {
+	biome, _ := template.Filter["biome"].(string)
+	return biome == "*"
+}
+
+// nearbyBiomes - biomy aktívnych zón v okolí poslednej polohy hráča, do ktorých má prístup
+func (h *Handler) nearbyBiomes(userID uuid.UUID, tier int) []string {
+	var session common.PlayerSession
+	if err := h.db.Where("user_id = ?", userID).First(&session).Error; err != nil {
+		return nil
+	}
+	if session.LastLocationLatitude == 0 && session.LastLocationLongitude == 0 {
+		return nil
+	}
+
+	latDelta := NearbyBiomeRadiusMeters / 111320.0
+	lngDelta := latDelta / math.Max(math.Cos(session.LastLocationLatitude*math.Pi/180), 0.01)
+
+	var biomes []string
+	h.db.Model(&common.Zone{}).
+		Where("is_active = true AND tier_required <= ? AND location_latitude BETWEEN ? AND ? AND location_longitude BETWEEN ? AND ?",
+			tier, session.LastLocationLatitude-latDelta, session.LastLocationLatitude+latDelta,
+			session.LastLocationLongitude-lngDelta, session.LastLocationLongitude+lngDelta).
+		Distinct().Order("biome").Pluck("biome", &biomes)
+
+	return biomes
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,92 @@
+package quests
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+)
+
+func TestPeriodWindowUsesPlayerTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+
+	// 2025-07-10 20:00 UTC = 2025-07-11 05:00 v Tokiu
+	now := time.Date(2025, 7, 10, 20, 0, 0, 0, time.UTC)
+
+	key, reset := periodWindow(PeriodDaily, now, tokyo)
+	if key != "2025-07-11" {
+		t.Errorf("daily key = %s; want 2025-07-11", key)
+	}
+	if want := time.Date(2025, 7, 12, 0, 0, 0, 0, tokyo); !reset.Equal(want) {
+		t.Errorf("daily reset = %v; want %v", reset, want)
+	}
+
+	utcKey, _ := periodWindow(PeriodDaily, now, time.UTC)
+	if utcKey != "2025-07-10" {
+		t.Errorf("UTC daily key = %s; want 2025-07-10", utcKey)
+	}
+}
+
+func TestPeriodWindowWeekly(t *testing.T) {
+	// Nedeľa 2025-07-13 → týždeň 28, reset v pondelok 2025-07-14
+	now := time.Date(2025, 7, 13, 22, 0, 0, 0, time.UTC)
+
+	key, reset := periodWindow(PeriodWeekly, now, time.UTC)
+	if key != "2025-W28" {
+		t.Errorf("weekly key = %s; want 2025-W28", key)
+	}
+	if want := time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC); !reset.Equal(want) {
+		t.Errorf("weekly reset = %v; want %v", reset, want)
+	}
+}
+
+func TestPickTemplatesSkipsBiomeQuestsWithoutNearbyBiomes(t *testing.T) {
+	templates := []common.QuestTemplate{
+		{Key: "biome", Filter: common.JSONB{"biome": "*"}, Weight: 10},
+		{Key: "walk", Weight: 1},
+		{Key: "collect", Weight: 1},
+	}
+
+	picked := pickTemplates(templates, nil, 3, rand.New(rand.NewSource(1)))
+	if len(picked) != 2 {
+		t.Fatalf("picked %d templates; want 2", len(picked))
+	}
+	for _, template := range picked {
+		if template.Key == "biome" {
+			t.Error("biome quest must not be picked without nearby biomes")
+		}
+	}
+
+	quest := buildQuest(common.QuestTemplate{}.ID, templates[0], PeriodDaily, "2025-07-10", time.Now(), []string{"urban"}, rand.New(rand.NewSource(1)))
+	if quest.Filter["biome"] != "urban" {
+		t.Errorf("biome filter = %v; want urban", quest.Filter["biome"])
+	}
+}
+
+func TestPeriodCache(t *testing.T) {
+	userID := uuid.New()
+	now := time.Date(2025, 7, 10, 20, 0, 0, 0, time.UTC)
+	pinned := questPeriod{Key: "2025-07-11", ExpiresAt: now.Add(4 * time.Hour)}
+
+	if _, ok := cachedPeriod(userID, PeriodDaily, now); ok {
+		t.Fatal("unknown user must miss the cache")
+	}
+	rememberPeriod(userID, PeriodDaily, pinned, now)
+
+	// Po zmene pásma ostáva pôvodné obdobie, kým neskončí
+	if cached, ok := cachedPeriod(userID, PeriodDaily, now.Add(time.Hour)); !ok || cached.Key != pinned.Key {
+		t.Errorf("cached period = %+v, %v; want %s", cached, ok, pinned.Key)
+	}
+	if _, ok := cachedPeriod(userID, PeriodWeekly, now); ok {
+		t.Error("daily entry must not answer for the weekly period")
+	}
+	if _, ok := cachedPeriod(userID, PeriodDaily, pinned.ExpiresAt); ok {
+		t.Error("expired period must miss the cache")
+	}
+}
@@ -0,0 +1,230 @@
+package quests
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"geoanomaly/internal/achievements"
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/xp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrQuestNotFound     = errors.New("quest not found")
+	ErrQuestNotCompleted = errors.New("quest is not completed yet")
+	ErrQuestClaimed      = errors.New("quest reward already claimed")
+	ErrQuestExpired      = errors.New("quest has expired")
+)
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// GetQuests - GET /user/quests
+// Aktuálne denné a týždenné questy (vygenerujú sa pri prvom otvorení obdobia)
+func (h *Handler) GetQuests(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var user common.User
+	if err := h.db.Select("id", "tier", "level", "timezone").First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	now := time.Now()
+	if err := h.ensureQuests(user, now); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate quests"})
+		return
+	}
+
+	loc := userLocation(user.Timezone)
+	daily, _, err := h.currentPeriod(user, PeriodDaily, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch quests"})
+		return
+	}
+	weekly, _, err := h.currentPeriod(user, PeriodWeekly, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch quests"})
+		return
+	}
+
+	var quests []common.PlayerQuest
+	h.db.Where("user_id = ? AND ((period = ? AND period_key = ?) OR (period = ? AND period_key = ?))",
+		user.ID, PeriodDaily, daily.Key, PeriodWeekly, weekly.Key).
+		Order("period ASC, created_at ASC").
+		Find(&quests)
+
+	dailyQuests := []common.PlayerQuest{}
+	weeklyQuests := []common.PlayerQuest{}
+	claimable := 0
+	for _, quest := range quests {
+		if quest.Status == StatusCompleted {
+			claimable++
+		}
+		if quest.Period == PeriodDaily {
+			dailyQuests = append(dailyQuests, quest)
+		} else {
+			weeklyQuests = append(weeklyQuests, quest)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"daily":           dailyQuests,
+		"weekly":          weeklyQuests,
+		"claimable":       claimable,
+		"timezone":        loc.String(),
+		"daily_reset_at":  daily.ExpiresAt.Unix(),
+		"weekly_reset_at": weekly.ExpiresAt.Unix(),
+	})
+}
+
+// ClaimQuest - POST /user/quests/:id/claim
+func (h *Handler) ClaimQuest(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	questID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quest ID"})
+		return
+	}
+
+	quest, xpResult, err := h.claim(userID.(uuid.UUID), questID)
+	switch {
+	case errors.Is(err, ErrQuestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrQuestNotCompleted), errors.Is(err, ErrQuestExpired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrQuestClaimed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim quest"})
+		return
+	}
+
+	response := gin.H{
+		"message": fmt.Sprintf("Quest '%s' claimed", quest.Title),
+		"quest":   quest,
+	}
+	if xpResult != nil {
+		response["xp_gained"] = xpResult.XPGained
+		response["total_xp"] = xpResult.TotalXP
+		response["current_level"] = xpResult.CurrentLevel
+		if xpResult.LevelUp {
+			response["level_up"] = true
+			response["level_up_info"] = xpResult.LevelUpInfo
+		}
+		if unlocked := achievements.NewHandler(h.db).TrackXPResults(quest.UserID, xpResult); len(unlocked) > 0 {
+			response["achievements_unlocked"] = unlocked
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// claim - označí quest ako claimed, vydá item odmenu a XP cez ledger v jednej transakcii
+// (source_id = ID questu, takže ani opakovaný claim nepridá XP dvakrát; chyba XP zruší celý claim)
+func (h *Handler) claim(userID, questID uuid.UUID) (*common.PlayerQuest, *xp.XPResult, error) {
+	var quest common.PlayerQuest
+	var xpResult *xp.XPResult
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&quest, "id = ? AND user_id = ?", questID, userID).Error; err != nil {
+			return ErrQuestNotFound
+		}
+
+		switch quest.Status {
+		case StatusClaimed:
+			return ErrQuestClaimed
+		case StatusActive:
+			if time.Now().After(quest.ExpiresAt) {
+				return ErrQuestExpired
+			}
+			return ErrQuestNotCompleted
+		}
+
+		now := time.Now()
+		quest.Status = StatusClaimed
+		quest.ClaimedAt = &now
+		if err := tx.Model(&quest).Updates(map[string]interface{}{
+			"status":     StatusClaimed,
+			"claimed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		if len(quest.RewardItem) > 0 {
+			if err := tx.Create(questRewardItem(userID, quest)).Error; err != nil {
+				return err
+			}
+		}
+
+		if quest.RewardXP <= 0 {
+			return nil
+		}
+		result, err := xp.NewHandler(tx).Grant(xp.XPGrant{
+			UserID:     userID,
+			SourceType: xpSourceQuest,
+			SourceID:   quest.ID.String(),
+			Amount:     quest.RewardXP,
+			Reason:     quest.Title,
+		})
+		if err != nil {
+			return err
+		}
+		xpResult = result
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &quest, xpResult, nil
+}
+
+func questRewardItem(userID uuid.UUID, quest common.PlayerQuest) *common.InventoryItem {
+	itemType, _ := quest.RewardItem["item_type"].(string)
+	if itemType == "" {
+		itemType = "artifact"
+	}
+
+	properties := common.JSONB{}
+	for key, value := range quest.RewardItem {
+		if key != "item_type" {
+			properties[key] = value
+		}
+	}
+	properties["quest_reward"] = quest.TemplateKey
+	properties["acquired_at"] = time.Now().Unix()
+
+	return &common.InventoryItem{
+		UserID:        userID,
+		ItemType:      itemType,
+		ItemID:        uuid.New(),
+		Quantity:      1,
+		Properties:    properties,
+		DefinitionKey: common.ItemDefinitionKey(itemType, properties),
+	}
+}
@@ -0,0 +1,86 @@
+package quests
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Track - posunie aktívne questy hráča, ktoré zodpovedajú udalosti; vráti novo splnené questy
+func (h *Handler) Track(userID uuid.UUID, event Event) []common.PlayerQuest {
+	var user common.User
+	if err := h.db.Select("id", "tier", "level", "timezone").First(&user, "id = ?", userID).Error; err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	if err := h.ensureQuests(user, now); err != nil {
+		log.Printf("❌ Failed to generate quests: %v", err)
+	}
+
+	var active []common.PlayerQuest
+	h.db.Where("user_id = ? AND event = ? AND status = ? AND expires_at > ?", userID, event.Type, StatusActive, now).
+		Find(&active)
+
+	amount := event.Amount
+	if amount <= 0 {
+		amount = 1
+	}
+
+	var completed []common.PlayerQuest
+	for _, quest := range active {
+		if !matchesFilter(quest.Filter, event) {
+			continue
+		}
+
+		// Atomický inkrement - súbežné udalosti sa nestratia
+		result := h.db.Model(&common.PlayerQuest{}).
+			Where("id = ? AND status = ?", quest.ID, StatusActive).
+			Updates(map[string]interface{}{
+				"progress": gorm.Expr("LEAST(progress + ?, target)", amount),
+				"status":   gorm.Expr("CASE WHEN progress + ? >= target THEN ? ELSE status END", amount, StatusCompleted),
+			})
+		if result.Error != nil {
+			log.Printf("❌ Failed to update quest %s: %v", quest.ID, result.Error)
+			continue
+		}
+
+		if quest.Progress+amount >= quest.Target {
+			quest.Progress = quest.Target
+			quest.Status = StatusCompleted
+			completed = append(completed, quest)
+			log.Printf("📜 Quest completed: user %s - %s", userID, quest.Title)
+		}
+	}
+
+	return completed
+}
+
+func matchesFilter(filter common.JSONB, event Event) bool {
+	for key, expected := range filter {
+		switch key {
+		case "min_zone_tier":
+			if minTier, ok := expected.(float64); ok && event.ZoneTier < int(minTier) {
+				return false
+			}
+		case "item_type":
+			if value, _ := expected.(string); !strings.EqualFold(event.ItemType, value) {
+				return false
+			}
+		case "biome":
+			if value, _ := expected.(string); !strings.EqualFold(event.Biome, value) {
+				return false
+			}
+		case "rarity":
+			if value, _ := expected.(string); !strings.EqualFold(event.Rarity, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
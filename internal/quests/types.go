@@ -0,0 +1,45 @@
+package quests
+
+// Rotácie questov
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+)
+
+// Udalosti, ktoré posúvajú quest
+const (
+	EventCollect   = "collect"
+	EventWalk      = "walk" // Amount = metre
+	EventEnterZone = "enter_zone"
+	EventZoneClear = "zone_clear"
+)
+
+const (
+	StatusActive    = "active"
+	StatusCompleted = "completed"
+	StatusClaimed   = "claimed"
+)
+
+const (
+	DailyQuestCount  = 3
+	WeeklyQuestCount = 2
+
+	// Biomy pre "{biome}" questy sa berú zo zón v tomto okruhu od poslednej polohy
+	NearbyBiomeRadiusMeters = 5000.0
+
+	// Od tohto počtu záznamov sa z cache vygenerovaných období mažú skončené
+	periodCacheSweepSize = 10000
+)
+
+// XP source type pre odmeny za questy (xp_ledger.source_type)
+const xpSourceQuest = "quest"
+
+// Event - herná udalosť pre quest progress
+type Event struct {
+	Type     string
+	ItemType string
+	Biome    string
+	Rarity   string
+	ZoneTier int
+	Amount   int // 0 = 1
+}
@@ -0,0 +1,203 @@
+package seasons
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ListSeasons - GET /admin/seasons
+func (h *Handler) ListSeasons(c *gin.Context) {
+	var seasons []common.Season
+	if err := h.db.Where("deleted_at IS NULL").Order("starts_at DESC").Find(&seasons).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch seasons"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"seasons": seasons,
+		"total":   len(seasons),
+	})
+}
+
+// CreateSeason - POST /admin/seasons
+func (h *Handler) CreateSeason(c *gin.Context) {
+	var req CreateSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must be after starts_at"})
+		return
+	}
+
+	// Sezóny sa nesmú prekrývať - XP grant vždy patrí najviac jednej sezóne
+	var overlapping int64
+	h.db.Model(&common.Season{}).
+		Where("deleted_at IS NULL AND snapshot_at IS NULL AND starts_at < ? AND ends_at > ?", req.EndsAt, req.StartsAt).
+		Count(&overlapping)
+	if overlapping > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Season overlaps with an existing season"})
+		return
+	}
+
+	season := common.Season{
+		Name:               req.Name,
+		Description:        req.Description,
+		StartsAt:           req.StartsAt,
+		EndsAt:             req.EndsAt,
+		XPPerLevel:         1000,
+		MaxLevel:           50,
+		PremiumTier:        1,
+		ExclusiveArtifacts: req.ExclusiveArtifacts,
+	}
+	if req.XPPerLevel > 0 {
+		season.XPPerLevel = req.XPPerLevel
+	}
+	if req.MaxLevel > 0 {
+		season.MaxLevel = req.MaxLevel
+	}
+	if req.PremiumTier != nil {
+		season.PremiumTier = *req.PremiumTier
+	}
+	if season.ExclusiveArtifacts == nil {
+		season.ExclusiveArtifacts = common.JSONB{}
+	}
+
+	if err := h.db.Create(&season).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create season"})
+		return
+	}
+
+	log.Printf("🗓️ Season created: %s (%s → %s)", season.Name,
+		season.StartsAt.Format("2006-01-02"), season.EndsAt.Format("2006-01-02"))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Season created",
+		"season":  season,
+	})
+}
+
+// SetSeasonRewards - PUT /admin/seasons/:id/rewards
+// Nahradí celú free + premium dráhu sezóny
+func (h *Handler) SetSeasonRewards(c *gin.Context) {
+	seasonID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	var req SetSeasonRewardsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var season common.Season
+	if err := h.db.First(&season, "id = ? AND deleted_at IS NULL", seasonID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+	if season.SnapshotAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Season already ended"})
+		return
+	}
+
+	seen := make(map[string]bool, len(req.Rewards))
+	rewards := make([]common.SeasonReward, 0, len(req.Rewards))
+	for _, input := range req.Rewards {
+		if input.Level > season.MaxLevel {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":     "Reward level exceeds season max level",
+				"level":     input.Level,
+				"max_level": season.MaxLevel,
+			})
+			return
+		}
+
+		slot := fmt.Sprintf("%s:%d", input.Track, input.Level)
+		if seen[slot] {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Duplicate reward slot",
+				"level": input.Level,
+				"track": input.Track,
+			})
+			return
+		}
+		seen[slot] = true
+
+		item := input.Item
+		if item == nil {
+			item = common.JSONB{}
+		}
+		rewards = append(rewards, common.SeasonReward{
+			SeasonID: seasonID,
+			Level:    input.Level,
+			Track:    input.Track,
+			Name:     input.Name,
+			RewardXP: input.RewardXP,
+			Item:     item,
+		})
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("season_id = ?", seasonID).Delete(&common.SeasonReward{}).Error; err != nil {
+			return err
+		}
+		if len(rewards) == 0 {
+			return nil
+		}
+		return tx.Create(&rewards).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save season rewards"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Season rewards updated",
+		"season":  season.Name,
+		"rewards": rewards,
+		"total":   len(rewards),
+	})
+}
+
+// EndSeason - POST /admin/seasons/:id/end
+// Okamžite ukončí sezónu a uloží konečné poradie
+func (h *Handler) EndSeason(c *gin.Context) {
+	seasonID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	var season common.Season
+	if err := h.db.First(&season, "id = ? AND deleted_at IS NULL", seasonID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+	if season.SnapshotAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Season already ended"})
+		return
+	}
+
+	ranked, err := FinalizeSeason(h.db, seasonID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end season"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Season ended",
+		"season":         season.Name,
+		"players_ranked": ranked,
+	})
+}
@@ -0,0 +1,317 @@
+package seasons
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/xp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrRewardNotFound   = errors.New("season reward not found")
+	ErrRewardNotReached = errors.New("season level not reached")
+	ErrRewardClaimed    = errors.New("season reward already claimed")
+	ErrPremiumLocked    = errors.New("premium track requires a higher tier")
+	ErrClaimClosed      = errors.New("season reward claim window has closed")
+)
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// GetCurrentSeason - GET /user/season
+// Bežiaca sezóna, obe dráhy odmien a postup hráča
+func (h *Handler) GetCurrentSeason(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	season := ActiveSeason(h.db)
+	if season == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"season":  nil,
+			"message": "No active season",
+		})
+		return
+	}
+
+	var user common.User
+	if err := h.db.Select("id", "tier").First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var playerSeason common.PlayerSeason
+	h.db.Where("season_id = ? AND user_id = ?", season.ID, user.ID).First(&playerSeason)
+	progress := seasonProgress(*season, playerSeason.SeasonXP, user.Tier)
+
+	var rewards []common.SeasonReward
+	h.db.Where("season_id = ?", season.ID).Order("level ASC, track ASC").Find(&rewards)
+
+	free := []SeasonRewardView{}
+	premium := []SeasonRewardView{}
+	claimable := 0
+	for _, reward := range rewards {
+		_, claimed := playerSeason.ClaimedRewards[reward.ID.String()]
+		view := SeasonRewardView{
+			SeasonReward: reward,
+			Reached:      progress.Level >= reward.Level,
+			Claimed:      claimed,
+			Locked:       reward.Track == TrackPremium && !progress.PremiumUnlocked,
+		}
+		if view.Reached && !view.Claimed && !view.Locked {
+			claimable++
+		}
+		if reward.Track == TrackPremium {
+			premium = append(premium, view)
+		} else {
+			free = append(free, view)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"season":        season,
+		"progress":      progress,
+		"free_track":    free,
+		"premium_track": premium,
+		"claimable":     claimable,
+		"ends_in":       int64(time.Until(season.EndsAt).Seconds()),
+	})
+}
+
+// ClaimSeasonReward - POST /user/season/rewards/:id/claim
+func (h *Handler) ClaimSeasonReward(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reward ID"})
+		return
+	}
+
+	reward, err := h.claim(userID.(uuid.UUID), rewardID)
+	switch {
+	case errors.Is(err, ErrRewardNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrRewardNotReached), errors.Is(err, ErrPremiumLocked):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrRewardClaimed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrClaimClosed):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim season reward"})
+		return
+	}
+
+	response := gin.H{
+		"message": fmt.Sprintf("Season reward '%s' claimed", reward.Name),
+		"reward":  reward,
+	}
+
+	if reward.RewardXP > 0 {
+		result, err := xp.NewHandler(h.db).Grant(xp.XPGrant{
+			UserID:     userID.(uuid.UUID),
+			SourceType: xp.SourceSeasonReward,
+			SourceID:   reward.ID.String(),
+			Amount:     reward.RewardXP,
+			Reason:     reward.Name,
+		})
+		if err != nil {
+			log.Printf("❌ Failed to grant season reward XP: %v", err)
+		} else {
+			response["xp_gained"] = result.XPGained
+			response["total_xp"] = result.TotalXP
+			response["current_level"] = result.CurrentLevel
+			if result.LevelUp {
+				response["level_up"] = true
+				response["level_up_info"] = result.LevelUpInfo
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// claim - kontrola levelu/tieru a zápis claimu + itemu v jednej transakcii
+func (h *Handler) claim(userID, rewardID uuid.UUID) (*common.SeasonReward, error) {
+	var reward common.SeasonReward
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&reward, "id = ?", rewardID).Error; err != nil {
+			return ErrRewardNotFound
+		}
+
+		var season common.Season
+		if err := tx.First(&season, "id = ? AND deleted_at IS NULL", reward.SeasonID).Error; err != nil {
+			return ErrRewardNotFound
+		}
+		if !claimOpen(season, time.Now()) {
+			return ErrClaimClosed
+		}
+
+		var user common.User
+		if err := tx.Select("id", "tier").First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		var playerSeason common.PlayerSeason
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("season_id = ? AND user_id = ?", season.ID, userID).
+			First(&playerSeason).Error; err != nil {
+			return ErrRewardNotReached
+		}
+
+		progress := seasonProgress(season, playerSeason.SeasonXP, user.Tier)
+		if progress.Level < reward.Level {
+			return ErrRewardNotReached
+		}
+		if reward.Track == TrackPremium && !progress.PremiumUnlocked {
+			return ErrPremiumLocked
+		}
+
+		claimed := playerSeason.ClaimedRewards
+		if claimed == nil {
+			claimed = common.JSONB{}
+		}
+		if _, done := claimed[reward.ID.String()]; done {
+			return ErrRewardClaimed
+		}
+		claimed[reward.ID.String()] = time.Now().Unix()
+
+		if err := tx.Model(&playerSeason).Update("claimed_rewards", claimed).Error; err != nil {
+			return err
+		}
+
+		if len(reward.Item) > 0 {
+			return tx.Create(seasonRewardItem(userID, season, reward)).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reward, nil
+}
+
+// claimOpen - odmeny sezóny sa dajú vyzdvihnúť do ends_at + ClaimGracePeriod
+func claimOpen(season common.Season, now time.Time) bool {
+	return now.Before(season.EndsAt.Add(ClaimGracePeriod))
+}
+
+func seasonRewardItem(userID uuid.UUID, season common.Season, reward common.SeasonReward) *common.InventoryItem {
+	itemType, _ := reward.Item["item_type"].(string)
+	if itemType == "" {
+		itemType = "artifact"
+	}
+
+	properties := common.JSONB{}
+	for key, value := range reward.Item {
+		if key != "item_type" {
+			properties[key] = value
+		}
+	}
+	if _, ok := properties["name"]; !ok {
+		properties["name"] = reward.Name
+	}
+	properties["season_id"] = season.ID
+	properties["season_name"] = season.Name
+	properties["season_track"] = reward.Track
+	properties["acquired_at"] = time.Now().Unix()
+
+	return &common.InventoryItem{
+		UserID:        userID,
+		ItemType:      itemType,
+		ItemID:        uuid.New(),
+		Quantity:      1,
+		Properties:    properties,
+		DefinitionKey: common.ItemDefinitionKey(itemType, properties),
+	}
+}
+
+// GetSeasonRankings - GET /game/seasons/:id/rankings
+// Po skončení sezóny snapshot, inak živé poradie
+func (h *Handler) GetSeasonRankings(c *gin.Context) {
+	seasonID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	var season common.Season
+	if err := h.db.First(&season, "id = ? AND deleted_at IS NULL", seasonID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(liveRankingLimit)))
+	if limit <= 0 || limit > liveRankingLimit {
+		limit = liveRankingLimit
+	}
+
+	if season.SnapshotAt != nil {
+		var rankings []common.SeasonRanking
+		h.db.Where("season_id = ?", seasonID).Order("rank ASC").Limit(limit).Find(&rankings)
+		c.JSON(http.StatusOK, gin.H{
+			"season":   season,
+			"final":    true,
+			"rankings": rankings,
+		})
+		return
+	}
+
+	var rows []struct {
+		UserID   uuid.UUID `json:"user_id"`
+		Username string    `json:"username"`
+		SeasonXP int       `json:"season_xp"`
+	}
+	h.db.Table("player_seasons").
+		Select("player_seasons.user_id, users.username, player_seasons.season_xp").
+		Joins("JOIN users ON users.id = player_seasons.user_id").
+		Where("player_seasons.season_id = ? AND player_seasons.season_xp > 0", seasonID).
+		Order("player_seasons.season_xp DESC, player_seasons.updated_at ASC").
+		Limit(limit).
+		Scan(&rows)
+
+	rankings := make([]common.SeasonRanking, 0, len(rows))
+	for i, row := range rows {
+		rankings = append(rankings, common.SeasonRanking{
+			SeasonID: seasonID,
+			UserID:   row.UserID,
+			Username: row.Username,
+			Rank:     i + 1,
+			SeasonXP: row.SeasonXP,
+			Level:    seasonProgress(season, row.SeasonXP, 0).Level,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"season":   season,
+		"final":    false,
+		"rankings": rankings,
+	})
+}
@@ -0,0 +1,123 @@
+package seasons
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ActiveSeason - práve bežiaca sezóna (nil, ak žiadna)
+func ActiveSeason(db *gorm.DB) *common.Season {
+	now := time.Now()
+	var season common.Season
+	if err := db.Where("starts_at <= ? AND ends_at > ? AND snapshot_at IS NULL AND deleted_at IS NULL", now, now).
+		Order("starts_at DESC").
+		First(&season).Error; err != nil {
+		return nil
+	}
+	return &season
+}
+
+// seasonProgress - sezónny level = celé násobky XPPerLevel (max MaxLevel)
+func seasonProgress(season common.Season, seasonXP int, userTier int) SeasonProgress {
+	xpPerLevel := season.XPPerLevel
+	if xpPerLevel <= 0 {
+		xpPerLevel = 1000
+	}
+
+	level := seasonXP / xpPerLevel
+	progress := SeasonProgress{
+		SeasonXP:        seasonXP,
+		Level:           level,
+		XPIntoLevel:     seasonXP % xpPerLevel,
+		XPToNextLevel:   xpPerLevel - seasonXP%xpPerLevel,
+		PremiumUnlocked: userTier >= season.PremiumTier,
+	}
+	if season.MaxLevel > 0 && level >= season.MaxLevel {
+		progress.Level = season.MaxLevel
+		progress.XPIntoLevel = 0
+		progress.XPToNextLevel = 0
+	}
+	return progress
+}
+
+// FinalizeEndedSeasons - snapshot poradia pre sezóny, ktoré skončili (volá scheduler)
+func FinalizeEndedSeasons(db *gorm.DB) int {
+	var ended []common.Season
+	db.Where("ends_at <= ? AND snapshot_at IS NULL AND deleted_at IS NULL", time.Now()).Find(&ended)
+
+	finalized := 0
+	for _, season := range ended {
+		count, err := FinalizeSeason(db, season.ID)
+		if err != nil {
+			log.Printf("❌ Failed to finalize season %s: %v", season.Name, err)
+			continue
+		}
+		log.Printf("🏁 Season %s finalized: %d players ranked", season.Name, count)
+		finalized++
+	}
+	return finalized
+}
+
+// FinalizeSeason - uzavrie sezónu a uloží konečné poradie (idempotentné cez snapshot_at)
+func FinalizeSeason(db *gorm.DB, seasonID uuid.UUID) (int, error) {
+	ranked := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var season common.Season
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&season, "id = ?", seasonID).Error; err != nil {
+			return err
+		}
+		if season.SnapshotAt != nil {
+			return fmt.Errorf("season already finalized")
+		}
+
+		var rows []struct {
+			UserID   uuid.UUID
+			Username string
+			SeasonXP int
+			Tier     int
+		}
+		if err := tx.Table("player_seasons").
+			Select("player_seasons.user_id, users.username, player_seasons.season_xp, users.tier").
+			Joins("JOIN users ON users.id = player_seasons.user_id").
+			Where("player_seasons.season_id = ? AND player_seasons.season_xp > 0", seasonID).
+			Order("player_seasons.season_xp DESC, player_seasons.updated_at ASC").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		rankings := make([]common.SeasonRanking, 0, len(rows))
+		for i, row := range rows {
+			rankings = append(rankings, common.SeasonRanking{
+				SeasonID: seasonID,
+				UserID:   row.UserID,
+				Username: row.Username,
+				Rank:     i + 1,
+				SeasonXP: row.SeasonXP,
+				Level:    seasonProgress(season, row.SeasonXP, row.Tier).Level,
+				Tier:     row.Tier,
+			})
+		}
+		if len(rankings) > 0 {
+			if err := tx.CreateInBatches(&rankings, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{"snapshot_at": now}
+		if season.EndsAt.After(now) {
+			updates["ends_at"] = now // predčasné ukončenie adminom
+		}
+		ranked = len(rankings)
+		return tx.Model(&season).Updates(updates).Error
+	})
+
+	return ranked, err
+}
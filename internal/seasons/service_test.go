@@ -0,0 +1,40 @@
+package seasons
+
+import (
+	"testing"
+	"time"
+
+	"geoanomaly/internal/common"
+)
+
+func TestSeasonProgress(t *testing.T) {
+	season := common.Season{XPPerLevel: 500, MaxLevel: 10, PremiumTier: 2}
+
+	progress := seasonProgress(season, 1250, 1)
+	if progress.Level != 2 || progress.XPIntoLevel != 250 || progress.XPToNextLevel != 250 {
+		t.Errorf("unexpected progress: %+v", progress)
+	}
+	if progress.PremiumUnlocked {
+		t.Error("tier 1 must not unlock premium track with premium_tier 2")
+	}
+
+	capped := seasonProgress(season, 99999, 2)
+	if capped.Level != 10 || capped.XPToNextLevel != 0 || !capped.PremiumUnlocked {
+		t.Errorf("expected capped premium progress, got %+v", capped)
+	}
+}
+
+func TestClaimOpen(t *testing.T) {
+	endsAt := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	season := common.Season{EndsAt: endsAt}
+
+	if !claimOpen(season, endsAt.Add(-time.Hour)) {
+		t.Error("claims must be open during the season")
+	}
+	if !claimOpen(season, endsAt.Add(ClaimGracePeriod-time.Minute)) {
+		t.Error("claims must stay open during the grace period")
+	}
+	if claimOpen(season, endsAt.Add(ClaimGracePeriod)) {
+		t.Error("claims must close after the grace period")
+	}
+}
@@ -0,0 +1,58 @@
+package seasons
+
+import (
+	"time"
+
+	"geoanomaly/internal/common"
+)
+
+const (
+	TrackFree    = "free"
+	TrackPremium = "premium"
+)
+
+// Veľkosť živého rebríčka (pred snapshotom)
+const liveRankingLimit = 100
+
+// Odmeny sa dajú vyzdvihnúť ešte ClaimGracePeriod po konci sezóny, potom už nie
+const ClaimGracePeriod = 72 * time.Hour
+
+type CreateSeasonRequest struct {
+	Name               string       `json:"name" binding:"required,max=100"`
+	Description        string       `json:"description,omitempty"`
+	StartsAt           time.Time    `json:"starts_at" binding:"required"`
+	EndsAt             time.Time    `json:"ends_at" binding:"required"`
+	XPPerLevel         int          `json:"xp_per_level,omitempty" binding:"omitempty,min=1"`
+	MaxLevel           int          `json:"max_level,omitempty" binding:"omitempty,min=1,max=200"`
+	PremiumTier        *int         `json:"premium_tier,omitempty" binding:"omitempty,min=0,max=4"`
+	ExclusiveArtifacts common.JSONB `json:"exclusive_artifacts,omitempty"`
+}
+
+type SeasonRewardInput struct {
+	Level    int          `json:"level" binding:"required,min=1"`
+	Track    string       `json:"track" binding:"required,oneof=free premium"`
+	Name     string       `json:"name" binding:"required,max=100"`
+	RewardXP int          `json:"reward_xp,omitempty" binding:"omitempty,min=0"`
+	Item     common.JSONB `json:"item,omitempty"`
+}
+
+type SetSeasonRewardsRequest struct {
+	Rewards []SeasonRewardInput `json:"rewards" binding:"required,dive"`
+}
+
+// SeasonRewardView - odmena s jej stavom pre hráča
+type SeasonRewardView struct {
+	common.SeasonReward
+	Reached bool `json:"reached"`
+	Claimed bool `json:"claimed"`
+	Locked  bool `json:"locked"` // premium bez potrebného tieru
+}
+
+// SeasonProgress - postup hráča v sezóne
+type SeasonProgress struct {
+	SeasonXP        int  `json:"season_xp"`
+	Level           int  `json:"level"`
+	XPIntoLevel     int  `json:"xp_into_level"`
+	XPToNextLevel   int  `json:"xp_to_next_level"`
+	PremiumUnlocked bool `json:"premium_unlocked"`
+}
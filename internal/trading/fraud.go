@@ -0,0 +1,118 @@
+package trading
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"gorm.io/gorm"
+)
+
+// FraudContext - obchod tesne pred výmenou vlastníctva
+type FraudContext struct {
+	Offer     common.TradeOffer
+	From      common.User
+	To        common.User
+	Offered   []common.TradeItem // od From pre To
+	Requested []common.TradeItem // od To pre From
+}
+
+// FraudVerdict - Reason != "" označí obchod na kontrolu; Block obchod zastaví
+type FraudVerdict struct {
+	Reason string
+	Block  bool
+}
+
+// FraudHook - kontrola obchodu pred výmenou (beží v transakcii prijatia)
+type FraudHook func(tx *gorm.DB, trade FraudContext) FraudVerdict
+
+var fraudHooks = struct {
+	sync.RWMutex
+	hooks map[string]FraudHook
+}{hooks: map[string]FraudHook{
+	"new_account":      newAccountHook,
+	"one_sided_rare":   oneSidedRareHook,
+	"repeated_partner": repeatedPartnerHook,
+}}
+
+// RegisterFraudHook - pridá alebo nahradí kontrolu (napr. externá antifraud služba)
+func RegisterFraudHook(name string, hook FraudHook) {
+	fraudHooks.Lock()
+	defer fraudHooks.Unlock()
+	fraudHooks.hooks[name] = hook
+}
+
+// runFraudHooks - {"<hook>": "dôvod"} a či niektorý hook obchod zablokoval
+func runFraudHooks(tx *gorm.DB, trade FraudContext) (common.JSONB, bool) {
+	fraudHooks.RLock()
+	defer fraudHooks.RUnlock()
+
+	flags := common.JSONB{}
+	blocked := false
+	for name, hook := range fraudHooks.hooks {
+		verdict := hook(tx, trade)
+		if verdict.Reason == "" {
+			continue
+		}
+		flags[name] = verdict.Reason
+		blocked = blocked || verdict.Block
+	}
+	return flags, blocked
+}
+
+const (
+	newAccountAge          = 24 * time.Hour
+	repeatedPartnerWindow  = 24 * time.Hour
+	repeatedPartnerFlagAt  = 5
+	repeatedPartnerBlockAt = 20
+)
+
+// Čerstvý účet je typický pri presune itemov z multiaccountu
+func newAccountHook(_ *gorm.DB, trade FraudContext) FraudVerdict {
+	for _, user := range []common.User{trade.From, trade.To} {
+		if time.Since(user.CreatedAt) < newAccountAge {
+			return FraudVerdict{Reason: fmt.Sprintf("account %s is younger than %s", user.Username, newAccountAge)}
+		}
+	}
+	return FraudVerdict{}
+}
+
+// Epic/legendary item za nič
+func oneSidedRareHook(_ *gorm.DB, trade FraudContext) FraudVerdict {
+	check := func(given, received []common.TradeItem) bool {
+		if len(received) > 0 {
+			return false
+		}
+		for _, item := range given {
+			if item.Rarity == "epic" || item.Rarity == "legendary" {
+				return true
+			}
+		}
+		return false
+	}
+	if check(trade.Offered, trade.Requested) || check(trade.Requested, trade.Offered) {
+		return FraudVerdict{Reason: "rare item traded for nothing"}
+	}
+	return FraudVerdict{}
+}
+
+// Veľa obchodov s tým istým hráčom za krátky čas
+func repeatedPartnerHook(tx *gorm.DB, trade FraudContext) FraudVerdict {
+	var count int64
+	tx.Model(&common.TradeOffer{}).
+		Where("status = ? AND updated_at > ?", common.TradeStatusAccepted, time.Now().Add(-repeatedPartnerWindow)).
+		Where("(from_user_id = ? AND to_user_id = ?) OR (from_user_id = ? AND to_user_id = ?)",
+			trade.From.ID, trade.To.ID, trade.To.ID, trade.From.ID).
+		Count(&count)
+
+	switch {
+	case count >= repeatedPartnerBlockAt:
+		return FraudVerdict{Reason: fmt.Sprintf("%d trades between the same players in 24h", count), Block: true}
+	case count >= repeatedPartnerFlagAt:
+		return FraudVerdict{Reason: fmt.Sprintf("%d trades between the same players in 24h", count)}
+	default:
+		return FraudVerdict{}
+	}
+}
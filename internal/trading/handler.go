@@ -0,0 +1,289 @@
+package trading
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// CreateOffer - POST /trades
+// {"to_user_id" | "to_username", "offered_item_ids", "requested_item_ids", "message", "expires_in_hours"}
+func (h *Handler) CreateOffer(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var req OfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	offer, err := Propose(h.db, userID.(uuid.UUID), req)
+	if err != nil {
+		respondTradeError(c, err, "create trade offer")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":   true,
+		"message":   "Trade offer sent",
+		"offer":     offer,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// GetOffers - GET /trades?direction=incoming|outgoing&status=pending
+func (h *Handler) GetOffers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	query := h.db.Preload("Items").Where("status = ?", c.DefaultQuery("status", common.TradeStatusPending))
+	switch c.Query("direction") {
+	case "incoming":
+		query = query.Where("to_user_id = ?", userID)
+	case "outgoing":
+		query = query.Where("from_user_id = ?", userID)
+	default:
+		query = query.Where("from_user_id = ? OR to_user_id = ?", userID, userID)
+	}
+
+	var offers []common.TradeOffer
+	if err := query.Order("created_at DESC").Limit(100).Find(&offers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trade offers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"offers":    offers,
+		"count":     len(offers),
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// GetTradeHistory - GET /trades/history?limit=&offset=
+// Uzavreté ponuky hráča (prijaté, odmietnuté, expirované, ...)
+func (h *Handler) GetTradeHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := h.db.Model(&common.TradeOffer{}).
+		Where("(from_user_id = ? OR to_user_id = ?) AND status <> ?", userID, userID, common.TradeStatusPending)
+
+	var total int64
+	query.Count(&total)
+
+	var offers []common.TradeOffer
+	if err := query.Preload("Items").Order("updated_at DESC").Limit(limit).Offset(offset).Find(&offers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trade history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trades": offers,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetOffer - GET /trades/:id
+func (h *Handler) GetOffer(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	offerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trade offer UUID"})
+		return
+	}
+
+	var offer common.TradeOffer
+	if err := h.db.Preload("Items").
+		Where("id = ? AND (from_user_id = ? OR to_user_id = ?)", offerID, userID, userID).
+		First(&offer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trade offer not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offer": offer})
+}
+
+// AcceptOffer - POST /trades/:id/accept
+func (h *Handler) AcceptOffer(c *gin.Context) {
+	h.respond(c, "accept trade offer", "Trade completed", func(userID, offerID uuid.UUID) (*common.TradeOffer, error) {
+		return Accept(h.db, userID, offerID)
+	})
+}
+
+// DeclineOffer - POST /trades/:id/decline
+func (h *Handler) DeclineOffer(c *gin.Context) {
+	h.respond(c, "decline trade offer", "Trade offer declined", func(userID, offerID uuid.UUID) (*common.TradeOffer, error) {
+		return Decline(h.db, userID, offerID)
+	})
+}
+
+// CancelOffer - POST /trades/:id/cancel
+func (h *Handler) CancelOffer(c *gin.Context) {
+	h.respond(c, "cancel trade offer", "Trade offer cancelled", func(userID, offerID uuid.UUID) (*common.TradeOffer, error) {
+		return Cancel(h.db, userID, offerID)
+	})
+}
+
+// CounterOffer - POST /trades/:id/counter
+// Pôvodná ponuka sa uzavrie ako countered, autorovi odíde nová ponuka
+func (h *Handler) CounterOffer(c *gin.Context) {
+	var req OfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.respond(c, "counter trade offer", "Counter offer sent", func(userID, offerID uuid.UUID) (*common.TradeOffer, error) {
+		return Counter(h.db, userID, offerID, req)
+	})
+}
+
+// GetFlaggedTrades - GET /admin/trades?review=pending
+func (h *Handler) GetFlaggedTrades(c *gin.Context) {
+	review := c.DefaultQuery("review", common.TradeReviewPending)
+
+	var offers []common.TradeOffer
+	if err := h.db.Preload("Items").Where("review_status = ?", review).
+		Order("updated_at DESC").Limit(200).Find(&offers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch flagged trades"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trades": offers,
+		"count":  len(offers),
+		"review": review,
+	})
+}
+
+// ReviewTrade - PUT /admin/trades/:id/review {"status": "cleared" | "confirmed", "note": "..."}
+func (h *Handler) ReviewTrade(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	offerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trade offer UUID"})
+		return
+	}
+
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	offer, err := Review(h.db, offerID, adminID.(uuid.UUID), req.Status, req.Note)
+	switch {
+	case errors.Is(err, ErrOfferNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrInvalidReview):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrNotPendingReview):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review trade"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Trade reviewed",
+		"offer":   offer,
+	})
+}
+
+func (h *Handler) respond(c *gin.Context, action, message string, fn func(userID, offerID uuid.UUID) (*common.TradeOffer, error)) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	offerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trade offer UUID"})
+		return
+	}
+
+	offer, err := fn(userID.(uuid.UUID), offerID)
+	if err != nil {
+		respondTradeError(c, err, action)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   message,
+		"offer":     offer,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+func respondTradeError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, ErrOfferNotFound), errors.Is(err, ErrPartnerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrOfferExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrTierRestricted):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrItemUnavailable), errors.Is(err, ErrItemChanged), errors.Is(err, ErrItemEquipped), errors.Is(err, ErrTooManyOffers),
+		errors.Is(err, ErrTradeBlocked), errors.Is(err, ErrPartnerRestricted), errors.Is(err, items.ErrItemInEscrow):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrTradeWithSelf), errors.Is(err, ErrNoItems), errors.Is(err, ErrTooManyItems),
+		errors.Is(err, ErrDuplicateItem):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("❌ Failed to %s: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + action})
+	}
+}
@@ -0,0 +1,469 @@
+package trading
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Propose - nová ponuka; ponúkané itemy sa zamknú v escrow
+func Propose(db *gorm.DB, fromID uuid.UUID, req OfferRequest) (*common.TradeOffer, error) {
+	var offer *common.TradeOffer
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		offer, err = propose(tx, fromID, req, nil)
+		return err
+	})
+	return offer, err
+}
+
+// Counter - odmietne prijatú ponuku a pošle protiponuku jej autorovi
+func Counter(db *gorm.DB, userID, offerID uuid.UUID, req OfferRequest) (*common.TradeOffer, error) {
+	var counter *common.TradeOffer
+	err := db.Transaction(func(tx *gorm.DB) error {
+		original, err := closeOffer(tx, offerID, "to_user_id", userID, common.TradeStatusCountered)
+		if err != nil {
+			return err
+		}
+
+		req.ToUserID, req.ToUsername = &original.FromUserID, ""
+		counter, err = propose(tx, userID, req, &original.ID)
+		return err
+	})
+	return counter, err
+}
+
+// Decline - adresát ponuku odmietne
+func Decline(db *gorm.DB, userID, offerID uuid.UUID) (*common.TradeOffer, error) {
+	var offer *common.TradeOffer
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		offer, err = closeOffer(tx, offerID, "to_user_id", userID, common.TradeStatusDeclined)
+		return err
+	})
+	return offer, err
+}
+
+// Cancel - autor ponuku stiahne
+func Cancel(db *gorm.DB, userID, offerID uuid.UUID) (*common.TradeOffer, error) {
+	var offer *common.TradeOffer
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		offer, err = closeOffer(tx, offerID, "from_user_id", userID, common.TradeStatusCancelled)
+		return err
+	})
+	return offer, err
+}
+
+// Accept - adresát prijme ponuku; vlastníctvo všetkých itemov sa vymení v jednej transakcii.
+// Fraud hooky môžu obchod označiť na kontrolu (prebehne) alebo zablokovať (ponuka sa zruší).
+func Accept(db *gorm.DB, userID, offerID uuid.UUID) (*common.TradeOffer, error) {
+	var offer common.TradeOffer
+	blocked := false
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND to_user_id = ? AND status = ?", offerID, userID, common.TradeStatusPending).
+			First(&offer).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOfferNotFound
+			}
+			return err
+		}
+		if time.Now().After(offer.ExpiresAt) {
+			return ErrOfferExpired
+		}
+
+		var tradeItems []common.TradeItem
+		if err := tx.Where("offer_id = ?", offer.ID).Find(&tradeItems).Error; err != nil {
+			return err
+		}
+
+		var from, to common.User
+		if err := tx.First(&from, "id = ?", offer.FromUserID).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&to, "id = ?", offer.ToUserID).Error; err != nil {
+			return err
+		}
+		if from.IsBanned || !from.IsActive {
+			return ErrPartnerRestricted
+		}
+
+		trade := FraudContext{Offer: offer, From: from, To: to}
+		for _, item := range tradeItems {
+			if item.OwnerID == offer.FromUserID {
+				trade.Offered = append(trade.Offered, item)
+			} else {
+				trade.Requested = append(trade.Requested, item)
+			}
+		}
+
+		// Offered sú v escrow tejto ponuky, requested musia byť stále voľné u adresáta
+		if err := verifyTradeItems(tx, offer, trade.Offered, true); err != nil {
+			return err
+		}
+		if err := verifyTradeItems(tx, offer, trade.Requested, false); err != nil {
+			return err
+		}
+		if err := checkTier(from, to, tradeItems); err != nil {
+			return err
+		}
+
+		flags, block := runFraudHooks(tx, trade)
+		now := time.Now()
+		updates := map[string]interface{}{
+			"status":       common.TradeStatusAccepted,
+			"responded_at": now,
+			"fraud_flags":  flags,
+		}
+		if len(flags) > 0 {
+			updates["review_status"] = common.TradeReviewPending
+			log.Printf("🚩 Trade %s flagged for review: %v", offer.ID, flags)
+		}
+
+		if block {
+			blocked = true
+			updates["status"] = common.TradeStatusCancelled
+			if err := items.ReleaseEscrow(tx, common.EscrowSourceTrade, offer.ID); err != nil {
+				return err
+			}
+			return tx.Model(&offer).Updates(updates).Error
+		}
+
+		for _, item := range trade.Offered {
+			if err := transferItem(tx, item.ItemID, offer.FromUserID, offer.ToUserID, now); err != nil {
+				return err
+			}
+		}
+		for _, item := range trade.Requested {
+			if err := transferItem(tx, item.ItemID, offer.ToUserID, offer.FromUserID, now); err != nil {
+				return err
+			}
+		}
+
+		if err := items.ReleaseEscrow(tx, common.EscrowSourceTrade, offer.ID); err != nil {
+			return err
+		}
+		return tx.Model(&offer).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		offer.Status = common.TradeStatusCancelled
+		return &offer, ErrTradeBlocked
+	}
+
+	return loadOffer(db, offer.ID)
+}
+
+// ExpireOffers - pending ponuky po ExpiresAt sa uzavrú a itemy sa uvoľnia z escrow (volá scheduler)
+func ExpireOffers(db *gorm.DB) int {
+	var expired []common.TradeOffer
+	if err := db.Where("status = ? AND expires_at <= ?", common.TradeStatusPending, time.Now()).
+		Limit(500).Find(&expired).Error; err != nil {
+		log.Printf("❌ Failed to load expired trade offers: %v", err)
+		return 0
+	}
+
+	closed := 0
+	for _, offer := range expired {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&common.TradeOffer{}).
+				Where("id = ? AND status = ?", offer.ID, common.TradeStatusPending).
+				Updates(map[string]interface{}{"status": common.TradeStatusExpired, "responded_at": time.Now()})
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			closed++
+			return items.ReleaseEscrow(tx, common.EscrowSourceTrade, offer.ID)
+		})
+		if err != nil {
+			log.Printf("❌ Failed to expire trade offer %s: %v", offer.ID, err)
+		}
+	}
+	return closed
+}
+
+// Review - admin uzavrie kontrolu označeného obchodu (cleared = v poriadku, confirmed = podvod potvrdený)
+func Review(db *gorm.DB, offerID, adminID uuid.UUID, status, note string) (*common.TradeOffer, error) {
+	if status != common.TradeReviewCleared && status != common.TradeReviewConfirmed {
+		return nil, ErrInvalidReview
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var offer common.TradeOffer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&offer, "id = ?", offerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOfferNotFound
+			}
+			return err
+		}
+		if offer.ReviewStatus != common.TradeReviewPending {
+			return ErrNotPendingReview
+		}
+		return tx.Model(&offer).Updates(map[string]interface{}{
+			"review_status": status,
+			"reviewed_by":   adminID,
+			"review_note":   note,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🛡️ Trade %s reviewed by %s: %s", offerID, adminID, status)
+	return loadOffer(db, offerID)
+}
+
+func propose(tx *gorm.DB, fromID uuid.UUID, req OfferRequest, counterOf *uuid.UUID) (*common.TradeOffer, error) {
+	if len(req.OfferedItemIDs)+len(req.RequestedItemIDs) == 0 {
+		return nil, ErrNoItems
+	}
+	if len(req.OfferedItemIDs) > MaxItemsPerSide || len(req.RequestedItemIDs) > MaxItemsPerSide {
+		return nil, ErrTooManyItems
+	}
+	if hasDuplicates(append(append([]uuid.UUID{}, req.OfferedItemIDs...), req.RequestedItemIDs...)) {
+		return nil, ErrDuplicateItem
+	}
+
+	var from, to common.User
+	if err := tx.First(&from, "id = ?", fromID).Error; err != nil {
+		return nil, err
+	}
+	partner := tx.Where("is_active = true AND is_banned = false")
+	switch {
+	case req.ToUserID != nil:
+		partner = partner.Where("id = ?", *req.ToUserID)
+	case req.ToUsername != "":
+		partner = partner.Where("username = ?", req.ToUsername)
+	default:
+		return nil, ErrPartnerNotFound
+	}
+	if err := partner.First(&to).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPartnerNotFound
+		}
+		return nil, err
+	}
+	if to.ID == from.ID {
+		return nil, ErrTradeWithSelf
+	}
+
+	var pending int64
+	tx.Model(&common.TradeOffer{}).Where("from_user_id = ? AND status = ?", from.ID, common.TradeStatusPending).Count(&pending)
+	if pending >= MaxPendingOffers {
+		return nil, ErrTooManyOffers
+	}
+
+	offered, err := tradeableItems(tx, from.ID, req.OfferedItemIDs, true)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := tradeableItems(tx, to.ID, req.RequestedItemIDs, false)
+	if err != nil {
+		return nil, err
+	}
+
+	offer := common.TradeOffer{
+		FromUserID:  from.ID,
+		ToUserID:    to.ID,
+		Status:      common.TradeStatusPending,
+		Message:     req.Message,
+		CounterOfID: counterOf,
+		ExpiresAt:   time.Now().Add(offerTTL(req.ExpiresInHours)),
+		FraudFlags:  common.JSONB{},
+	}
+	if err := tx.Create(&offer).Error; err != nil {
+		return nil, err
+	}
+
+	for _, item := range append(offered, requested...) {
+		offer.Items = append(offer.Items, snapshot(tx, offer.ID, item))
+	}
+	if err := checkTier(from, to, offer.Items); err != nil {
+		return nil, err
+	}
+	if len(offer.Items) > 0 {
+		if err := tx.Create(&offer.Items).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	offeredIDs := make([]uuid.UUID, len(offered))
+	for i, item := range offered {
+		offeredIDs[i] = item.ID
+	}
+	if err := items.LockEscrow(tx, common.EscrowSourceTrade, offer.ID, offeredIDs); err != nil {
+		return nil, err
+	}
+
+	return &offer, nil
+}
+
+// closeOffer - uzavrie pending ponuku (decline/cancel/counter) a uvoľní escrow
+func closeOffer(tx *gorm.DB, offerID uuid.UUID, ownerColumn string, userID uuid.UUID, status string) (*common.TradeOffer, error) {
+	var offer common.TradeOffer
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND status = ?", offerID, common.TradeStatusPending).
+		Where(ownerColumn+" = ?", userID).
+		First(&offer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOfferNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := tx.Model(&offer).Updates(map[string]interface{}{"status": status, "responded_at": now}).Error; err != nil {
+		return nil, err
+	}
+	if err := items.ReleaseEscrow(tx, common.EscrowSourceTrade, offer.ID); err != nil {
+		return nil, err
+	}
+
+	offer.Status = status
+	offer.RespondedAt = &now
+	return &offer, nil
+}
+
+// tradeableItems - vlastnené, nezmazané, nevybavené a nezamknuté itemy (lock = FOR UPDATE)
+func tradeableItems(tx *gorm.DB, ownerID uuid.UUID, ids []uuid.UUID, lock bool) ([]common.InventoryItem, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := tx.Where("id IN ? AND user_id = ? AND deleted_at IS NULL", ids, ownerID)
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	var found []common.InventoryItem
+	if err := query.Find(&found).Error; err != nil {
+		return nil, err
+	}
+	if len(found) != len(ids) {
+		return nil, ErrItemUnavailable
+	}
+
+	for _, item := range found {
+		var equipped int64
+		tx.Model(&common.PlayerLoadout{}).Where("item_id = ?", item.ID).Count(&equipped)
+		if equipped > 0 {
+			return nil, ErrItemEquipped
+		}
+		if items.InEscrow(tx, item.ID) {
+			return nil, ErrItemUnavailable
+		}
+	}
+	return found, nil
+}
+
+// verifyTradeItems - pri prijatí overí, že sa itemy odvtedy nezmenili (vlastník, zmazanie, escrow, množstvo, definícia)
+func verifyTradeItems(tx *gorm.DB, offer common.TradeOffer, tradeItems []common.TradeItem, inEscrow bool) error {
+	for _, tradeItem := range tradeItems {
+		var item common.InventoryItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ? AND deleted_at IS NULL", tradeItem.ItemID, tradeItem.OwnerID).
+			First(&item).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrItemUnavailable
+			}
+			return err
+		}
+
+		// Stack sa mohol medzičasom zmenšiť (predaj, crafting) alebo premeniť - obchod platí len pre ponúknutý stav
+		if !matchesSnapshot(tradeItem, item, items.Resolve(tx, item).Key) {
+			return ErrItemChanged
+		}
+
+		var escrow common.ItemEscrow
+		locked := tx.Where("item_id = ?", item.ID).First(&escrow).Error == nil
+		if inEscrow && (!locked || escrow.ReferenceID != offer.ID) {
+			return ErrItemUnavailable
+		}
+		if !inEscrow && locked {
+			return ErrItemUnavailable
+		}
+
+		var equipped int64
+		tx.Model(&common.PlayerLoadout{}).Where("item_id = ?", item.ID).Count(&equipped)
+		if equipped > 0 {
+			return ErrItemEquipped
+		}
+	}
+	return nil
+}
+
+// checkTier - obe strany musia mať Tier potrebný pre raritu každého itemu v obchode
+func checkTier(from, to common.User, tradeItems []common.TradeItem) error {
+	for _, item := range tradeItems {
+		required, restricted := minTierForRarity[item.Rarity]
+		if !restricted {
+			continue
+		}
+		if from.Tier < required || to.Tier < required {
+			return ErrTierRestricted
+		}
+	}
+	return nil
+}
+
+func transferItem(tx *gorm.DB, itemID, fromID, toID uuid.UUID, now time.Time) error {
+	if err := items.Transfer(tx, itemID, fromID, toID, now); err != nil {
+		if errors.Is(err, items.ErrItemNotFound) {
+			return ErrItemUnavailable
+		}
+		return err
+	}
+	return nil
+}
+
+func snapshot(tx *gorm.DB, offerID uuid.UUID, item common.InventoryItem) common.TradeItem {
+	def := items.Resolve(tx, item)
+	name := def.Name
+	if custom, ok := item.Properties["name"].(string); ok && custom != "" {
+		name = custom
+	}
+	rarity, _ := item.Properties["rarity"].(string)
+
+	return common.TradeItem{
+		OfferID:       offerID,
+		ItemID:        item.ID,
+		OwnerID:       item.UserID,
+		DefinitionKey: def.Key,
+		Name:          name,
+		Rarity:        rarity,
+		Quantity:      item.Quantity,
+	}
+}
+
+func matchesSnapshot(tradeItem common.TradeItem, item common.InventoryItem, definitionKey string) bool {
+	return item.Quantity == tradeItem.Quantity && definitionKey == tradeItem.DefinitionKey
+}
+
+func hasDuplicates(ids []uuid.UUID) bool {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return true
+		}
+		seen[id] = true
+	}
+	return false
+}
+
+func loadOffer(db *gorm.DB, offerID uuid.UUID) (*common.TradeOffer, error) {
+	var offer common.TradeOffer
+	if err := db.Preload("Items").First(&offer, "id = ?", offerID).Error; err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
@@ -0,0 +1,75 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+)
+
+func TestCheckTier(t *testing.T) {
+	free := common.User{Tier: 0}
+	premium := common.User{Tier: 1}
+	legendary := []common.TradeItem{{Rarity: "common"}, {Rarity: "legendary"}}
+
+	if err := checkTier(premium, free, legendary); err != ErrTierRestricted {
+		t.Errorf("legendary trade with a free player: got %v, want ErrTierRestricted", err)
+	}
+	if err := checkTier(premium, premium, legendary); err != nil {
+		t.Errorf("legendary trade between premium players: %v", err)
+	}
+	if err := checkTier(free, free, []common.TradeItem{{Rarity: "epic"}}); err != nil {
+		t.Errorf("epic items are not tier restricted: %v", err)
+	}
+}
+
+func TestOneSidedRareHook(t *testing.T) {
+	epic := []common.TradeItem{{Rarity: "epic"}}
+	plain := []common.TradeItem{{Rarity: "common"}}
+
+	if oneSidedRareHook(nil, FraudContext{Offered: epic}).Reason == "" {
+		t.Error("epic item for nothing should be flagged")
+	}
+	if oneSidedRareHook(nil, FraudContext{Requested: epic}).Reason == "" {
+		t.Error("requesting epic item for nothing should be flagged")
+	}
+	if oneSidedRareHook(nil, FraudContext{Offered: epic, Requested: plain}).Reason != "" {
+		t.Error("two-sided trade should not be flagged")
+	}
+}
+
+func TestOfferHelpers(t *testing.T) {
+	id := uuid.New()
+	if !hasDuplicates([]uuid.UUID{id, uuid.New(), id}) {
+		t.Error("duplicate item id not detected")
+	}
+	if hasDuplicates([]uuid.UUID{uuid.New(), uuid.New()}) {
+		t.Error("unique ids reported as duplicates")
+	}
+
+	if got := offerTTL(0); got != DefaultOfferTTL {
+		t.Errorf("offerTTL(0) = %s, want %s", got, DefaultOfferTTL)
+	}
+	if got := offerTTL(12); got != 12*time.Hour {
+		t.Errorf("offerTTL(12) = %s", got)
+	}
+	if got := offerTTL(1000); got != MaxOfferTTL {
+		t.Errorf("offerTTL(1000) = %s, want cap %s", got, MaxOfferTTL)
+	}
+}
+
+func TestMatchesSnapshot(t *testing.T) {
+	offered := common.TradeItem{DefinitionKey: "artifact:mineral_ore", Quantity: 10}
+
+	if !matchesSnapshot(offered, common.InventoryItem{Quantity: 10}, "artifact:mineral_ore") {
+		t.Error("unchanged stack should match its snapshot")
+	}
+	if matchesSnapshot(offered, common.InventoryItem{Quantity: 3}, "artifact:mineral_ore") {
+		t.Error("stack sold down to 3 must not match a snapshot of 10")
+	}
+	if matchesSnapshot(offered, common.InventoryItem{Quantity: 10}, "artifact:crystal_shard") {
+		t.Error("different definition must not match")
+	}
+}
@@ -0,0 +1,62 @@
+package trading
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOfferNotFound     = errors.New("trade offer not found")
+	ErrOfferExpired      = errors.New("trade offer has expired")
+	ErrTradeWithSelf     = errors.New("cannot trade with yourself")
+	ErrPartnerNotFound   = errors.New("trade partner not found")
+	ErrNoItems           = errors.New("trade must contain at least one item")
+	ErrTooManyItems      = errors.New("too many items in one trade")
+	ErrTooManyOffers     = errors.New("too many pending trade offers")
+	ErrItemUnavailable   = errors.New("item is no longer available for trade")
+	ErrItemChanged       = errors.New("item changed since the offer was made")
+	ErrItemEquipped      = errors.New("unequip the item before trading it")
+	ErrTierRestricted    = errors.New("your tier does not allow trading this item")
+	ErrTradeBlocked      = errors.New("trade was blocked for review")
+	ErrInvalidReview     = errors.New("review status must be cleared or confirmed")
+	ErrNotPendingReview  = errors.New("trade is not waiting for review")
+	ErrDuplicateItem     = errors.New("item listed more than once")
+	ErrPartnerRestricted = errors.New("trade partner cannot trade right now")
+)
+
+const (
+	DefaultOfferTTL  = 48 * time.Hour
+	MaxOfferTTL      = 7 * 24 * time.Hour
+	MaxPendingOffers = 10 // odoslané pending ponuky na hráča
+	MaxItemsPerSide  = 10
+)
+
+// Minimálny Tier (predplatné) pre obchodovanie s raritou - platí pre obe strany
+var minTierForRarity = map[string]int{
+	"legendary": 1,
+}
+
+// OfferRequest - POST /trades a POST /trades/:id/counter
+type OfferRequest struct {
+	ToUserID         *uuid.UUID  `json:"to_user_id"`
+	ToUsername       string      `json:"to_username"`
+	OfferedItemIDs   []uuid.UUID `json:"offered_item_ids"`
+	RequestedItemIDs []uuid.UUID `json:"requested_item_ids"`
+	Message          string      `json:"message" binding:"max=500"`
+	ExpiresInHours   int         `json:"expires_in_hours"`
+}
+
+// ReviewRequest - PUT /admin/trades/:id/review
+type ReviewRequest struct {
+	Status string `json:"status" binding:"required"` // cleared, confirmed
+	Note   string `json:"note"`
+}
+
+func offerTTL(hours int) time.Duration {
+	if hours <= 0 {
+		return DefaultOfferTTL
+	}
+	return min(time.Duration(hours)*time.Hour, MaxOfferTTL)
+}
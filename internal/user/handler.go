@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
 	"geoanomaly/pkg/redis"
 
 	"github.com/gin-gonic/gin"
@@ -23,6 +24,7 @@ type Handler struct {
 type UpdateProfileRequest struct {
 	Username string `json:"username,omitempty" binding:"omitempty,min=3,max=50"`
 	Email    string `json:"email,omitempty" binding:"omitempty,email"`
+	Timezone string `json:"timezone,omitempty" binding:"omitempty,max=64"` // ✅ NEW: napr. Europe/Bratislava
 }
 
 type UpdateLocationRequest struct {
@@ -149,6 +151,15 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 		updates["email"] = req.Email
 	}
 
+	// ✅ NEW: Časové pásmo pre denné/týždenné questy
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+			return
+		}
+		updates["timezone"] = req.Timezone
+	}
+
 	// Aktualizuj v databáze
 	if len(updates) > 0 {
 		if err := h.db.Model(&user).Updates(updates).Error; err != nil {
@@ -223,6 +234,7 @@ func (h *Handler) GetInventory(c *gin.Context) {
 	if inventory == nil {
 		inventory = []common.InventoryItem{}
 	}
+	items.Attach(h.db, inventory) // ✅ NEW: typované dáta z katalógu
 
 	// Vypočítaj total pages
 	totalPages := int64(0)
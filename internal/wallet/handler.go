@@ -0,0 +1,160 @@
+package wallet
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	db *gorm.DB
+}
+
+func NewHandler(db *gorm.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// GrantRequest - POST /admin/users/:id/wallet/grant (záporná suma stiahne)
+type GrantRequest struct {
+	Currency string `json:"currency" binding:"required"`
+	Amount   int64  `json:"amount" binding:"required"`
+	Note     string `json:"note" binding:"required,max=500"`
+}
+
+// GetWallet - GET /user/wallet
+func (h *Handler) GetWallet(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, walletResponse(Balances(h.db, userID.(uuid.UUID))))
+}
+
+// GetTransactions - GET /user/wallet/transactions?currency=&limit=&offset=
+func (h *Handler) GetTransactions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	h.respondHistory(c, userID.(uuid.UUID))
+}
+
+// GetUserWallet - GET /admin/users/:id/wallet?currency=&limit=&offset=
+func (h *Handler) GetUserWallet(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user UUID"})
+		return
+	}
+	h.respondHistory(c, userID)
+}
+
+// GrantCurrency - POST /admin/users/:id/wallet/grant {"currency", "amount", "note"}
+func (h *Handler) GrantCurrency(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user UUID"})
+		return
+	}
+
+	var req GrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user common.User
+	if err := h.db.Select("id").First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	transaction, err := Grant(h.db, adminID.(uuid.UUID), userID, req.Currency, req.Amount, req.Note)
+	switch {
+	case errors.Is(err, ErrInvalidCurrency), errors.Is(err, ErrInvalidAmount):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrInsufficientFunds):
+		c.JSON(http.StatusConflict, gin.H{"error": "User balance is lower than the amount to revoke"})
+		return
+	case err != nil:
+		log.Printf("❌ Failed to grant currency to %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant currency"})
+		return
+	}
+
+	log.Printf("💳 Admin %s granted %d %s to %s: %s", adminID, req.Amount, req.Currency, userID, req.Note)
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Currency granted",
+		"transaction": transaction,
+		"wallet":      walletResponse(Balances(h.db, userID)),
+	})
+}
+
+// ReconcileLedger - GET /admin/wallet/reconcile
+func (h *Handler) ReconcileLedger(c *gin.Context) {
+	report, err := Reconcile(h.db)
+	if err != nil {
+		log.Printf("❌ Wallet reconciliation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reconcile wallets"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) respondHistory(c *gin.Context, userID uuid.UUID) {
+	currency := c.Query("currency")
+	if currency != "" && !ValidCurrency(currency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ErrInvalidCurrency.Error()})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	entries, total, err := History(h.db, userID, currency, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch wallet history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"wallet":       walletResponse(Balances(h.db, userID)),
+		"transactions": entries,
+		"total":        total,
+		"limit":        limit,
+		"offset":       offset,
+	})
+}
+
+func walletResponse(wallet common.Wallet) gin.H {
+	return gin.H{
+		"balances": gin.H{
+			common.CurrencyCredits:  wallet.Credits,
+			common.CurrencyCrystals: wallet.Crystals,
+		},
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+}
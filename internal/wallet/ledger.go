@@ -0,0 +1,211 @@
+package wallet
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReconcileInterval - ako často scheduler kontroluje invarianty ledgeru
+const ReconcileInterval = time.Hour
+
+const reconcileSampleLimit = 100
+
+// HistoryEntry - pohyb na účte hráča spolu s dôvodom transakcie
+type HistoryEntry struct {
+	ID            uuid.UUID  `json:"id"`
+	TransactionID uuid.UUID  `json:"transaction_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Currency      string     `json:"currency"`
+	Amount        int64      `json:"amount"`
+	BalanceAfter  *int64     `json:"balance_after,omitempty"`
+	Reason        string     `json:"reason"`
+	ReferenceType string     `json:"reference_type,omitempty"`
+	ReferenceID   *uuid.UUID `json:"reference_id,omitempty"`
+	Note          string     `json:"note,omitempty"`
+}
+
+// WalletMismatch - zostatok v wallets nesedí so súčtom ledgeru
+type WalletMismatch struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Currency string    `json:"currency"`
+	Wallet   int64     `json:"wallet"`
+	Ledger   int64     `json:"ledger"`
+}
+
+// ReconcileReport - výsledok kontroly invariantov
+type ReconcileReport struct {
+	CheckedAt       time.Time                   `json:"checked_at"`
+	Transactions    int64                       `json:"transactions"`
+	Unbalanced      []uuid.UUID                 `json:"unbalanced_transactions"`
+	Mismatches      []WalletMismatch            `json:"wallet_mismatches"`
+	NegativeWallets []uuid.UUID                 `json:"negative_wallets"`
+	CurrencyTotals  map[string]int64            `json:"currency_totals"` // súčet všetkých záznamov, musí byť 0
+	SystemAccounts  map[string]map[string]int64 `json:"system_accounts"` // účet -> mena -> zostatok (sinky sú kladné)
+	OK              bool                        `json:"ok"`
+}
+
+// History - pohyby hráča, najnovšie prvé
+func History(db *gorm.DB, userID uuid.UUID, currency string, limit, offset int) ([]HistoryEntry, int64, error) {
+	query := db.Table("ledger_entries e").
+		Joins("JOIN ledger_transactions t ON t.id = e.transaction_id").
+		Where("e.user_id = ?", userID)
+	if currency != "" {
+		query = query.Where("e.currency = ?", currency)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []HistoryEntry
+	err := query.Select(`e.id, e.transaction_id, e.created_at, e.currency, e.amount, e.balance_after,
+			t.reason, t.reference_type, t.reference_id, t.note`).
+		Order("e.created_at DESC").Limit(limit).Offset(offset).
+		Scan(&entries).Error
+	return entries, total, err
+}
+
+// Reconcile - overí, že každá transakcia je vyvážená, zostatky sedia s ledgerom a nikto nie je v mínuse
+func Reconcile(db *gorm.DB) (*ReconcileReport, error) {
+	report := &ReconcileReport{
+		CheckedAt:      time.Now(),
+		CurrencyTotals: map[string]int64{},
+		SystemAccounts: map[string]map[string]int64{},
+	}
+
+	if err := db.Model(&common.LedgerTransaction{}).Count(&report.Transactions).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&common.LedgerEntry{}).
+		Group("transaction_id, currency").
+		Having("SUM(amount) <> 0").
+		Limit(reconcileSampleLimit).
+		Pluck("transaction_id", &report.Unbalanced).Error; err != nil {
+		return nil, err
+	}
+
+	for _, currency := range common.Currencies {
+		var mismatches []WalletMismatch
+		query := fmt.Sprintf(`
+			SELECT COALESCE(w.user_id, l.user_id) AS user_id, ? AS currency,
+				COALESCE(w.%[1]s, 0) AS wallet, COALESCE(l.total, 0) AS ledger
+			FROM wallets w
+			FULL OUTER JOIN (
+				SELECT user_id, SUM(amount) AS total FROM ledger_entries
+				WHERE user_id IS NOT NULL AND currency = ? GROUP BY user_id
+			) l ON l.user_id = w.user_id
+			WHERE COALESCE(w.%[1]s, 0) <> COALESCE(l.total, 0)
+			LIMIT ?`, currency)
+		if err := db.Raw(query, currency, currency, reconcileSampleLimit).Scan(&mismatches).Error; err != nil {
+			return nil, err
+		}
+		report.Mismatches = append(report.Mismatches, mismatches...)
+	}
+
+	if err := db.Model(&common.Wallet{}).
+		Where("credits < 0 OR crystals < 0").
+		Limit(reconcileSampleLimit).
+		Pluck("user_id", &report.NegativeWallets).Error; err != nil {
+		return nil, err
+	}
+
+	var totals []struct {
+		Currency string
+		Total    int64
+	}
+	if err := db.Model(&common.LedgerEntry{}).Select("currency, SUM(amount) AS total").
+		Group("currency").Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range totals {
+		report.CurrencyTotals[row.Currency] = row.Total
+	}
+
+	var system []struct {
+		Account  string
+		Currency string
+		Total    int64
+	}
+	if err := db.Model(&common.LedgerEntry{}).Select("account, currency, SUM(amount) AS total").
+		Where("user_id IS NULL").Group("account, currency").Scan(&system).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range system {
+		if report.SystemAccounts[row.Account] == nil {
+			report.SystemAccounts[row.Account] = map[string]int64{}
+		}
+		report.SystemAccounts[row.Account][row.Currency] = row.Total
+	}
+
+	report.OK = len(report.Unbalanced) == 0 && len(report.Mismatches) == 0 && len(report.NegativeWallets) == 0
+	for _, total := range report.CurrencyTotals {
+		report.OK = report.OK && total == 0
+	}
+	return report, nil
+}
+
+// RunReconcile - Reconcile pre scheduler; porušené invarianty sa zalogujú
+func RunReconcile(db *gorm.DB) {
+	report, err := Reconcile(db)
+	if err != nil {
+		log.Printf("❌ Wallet reconciliation failed: %v", err)
+		return
+	}
+	if !report.OK {
+		log.Printf("🚨 Wallet ledger out of balance: %d unbalanced transactions, %d wallet mismatches, %d negative wallets, totals %v",
+			len(report.Unbalanced), len(report.Mismatches), len(report.NegativeWallets), report.CurrencyTotals)
+		return
+	}
+	log.Printf("💳 Wallet ledger reconciled (%d transactions)", report.Transactions)
+}
+
+// BackfillLedger - peňaženky bez jediného záznamu v ledgeri (pred zavedením ledgeru) dostanú
+// otváraciu transakciu zo system:mint, aby sedela kontrola zostatkov. Zostatok sa nemení.
+func BackfillLedger(db *gorm.DB) error {
+	var wallets []common.Wallet
+	if err := db.Where("(credits <> 0 OR crystals <> 0) AND user_id NOT IN (SELECT user_id FROM ledger_entries WHERE user_id IS NOT NULL)").
+		Find(&wallets).Error; err != nil {
+		return err
+	}
+
+	for _, wallet := range wallets {
+		balances := map[string]int64{common.CurrencyCredits: wallet.Credits, common.CurrencyCrystals: wallet.Crystals}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, currency := range common.Currencies {
+				amount := balances[currency]
+				if amount == 0 {
+					continue
+				}
+				transaction := common.LedgerTransaction{Currency: currency, Reason: ReasonOpeningBalance, ReferenceType: "user", ReferenceID: &wallet.UserID}
+				if err := tx.Create(&transaction).Error; err != nil {
+					return err
+				}
+				userID := wallet.UserID
+				entries := []common.LedgerEntry{
+					{TransactionID: transaction.ID, Account: common.AccountMint, Currency: currency, Amount: -amount},
+					{TransactionID: transaction.ID, Account: UserAccount(userID), UserID: &userID, Currency: currency, Amount: amount, BalanceAfter: &amount},
+				}
+				if err := tx.Create(&entries).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(wallets) > 0 {
+		log.Printf("💳 Opened ledger for %d existing wallets", len(wallets))
+	}
+	return nil
+}
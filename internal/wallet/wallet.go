@@ -0,0 +1,215 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInsufficientFunds = errors.New("not enough funds")
+	ErrInvalidAmount     = errors.New("amount must be positive")
+	ErrInvalidCurrency   = errors.New("unknown currency")
+	ErrUnbalanced        = errors.New("ledger transaction does not balance")
+)
+
+// Dôvody transakcií spravované peňaženkou (ostatné definujú moduly, ktoré platia)
+const (
+	ReasonAdminGrant     = "admin_grant"
+	ReasonAdminRevoke    = "admin_revoke"
+	ReasonOpeningBalance = "opening_balance" // zostatok z čias pred ledgerom
+	ReasonRepair         = "repair"
+)
+
+const userAccountPrefix = "user:"
+
+// Entry - jedna strana transakcie; kladná suma pripisuje, záporná sťahuje
+type Entry struct {
+	Account string
+	Amount  int64
+}
+
+// Posting - vyvážená transakcia ledgeru
+type Posting struct {
+	Currency      string
+	Reason        string
+	ReferenceType string
+	ReferenceID   *uuid.UUID
+	CreatedBy     *uuid.UUID
+	Note          string
+	Entries       []Entry
+}
+
+// UserAccount - účet hráča v ledgeri
+func UserAccount(userID uuid.UUID) string {
+	return userAccountPrefix + userID.String()
+}
+
+// Move - presun sumy z účtu na účet
+func Move(from, to string, amount int64) []Entry {
+	return []Entry{{Account: from, Amount: -amount}, {Account: to, Amount: amount}}
+}
+
+// ValidCurrency - mena existuje (názov meny je zároveň stĺpec v wallets)
+func ValidCurrency(currency string) bool {
+	for _, c := range common.Currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// Post - zapíše vyváženú transakciu a zmení zostatky hráčov v tej istej DB transakcii.
+// Stiahnutie je podmienený UPDATE, takže súbežné platby zostatok nikdy nedostanú do mínusu.
+func Post(tx *gorm.DB, posting Posting) (*common.LedgerTransaction, error) {
+	if !ValidCurrency(posting.Currency) {
+		return nil, ErrInvalidCurrency
+	}
+	entries, err := normalize(posting.Entries)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := common.LedgerTransaction{
+		Currency:      posting.Currency,
+		Reason:        posting.Reason,
+		ReferenceType: posting.ReferenceType,
+		ReferenceID:   posting.ReferenceID,
+		CreatedBy:     posting.CreatedBy,
+		Note:          posting.Note,
+	}
+	if err := tx.Create(&transaction).Error; err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		record := common.LedgerEntry{
+			TransactionID: transaction.ID,
+			Account:       entry.Account,
+			Currency:      posting.Currency,
+			Amount:        entry.Amount,
+		}
+		if userID, ok := parseUserAccount(entry.Account); ok {
+			balance, err := applyBalance(tx, userID, posting.Currency, entry.Amount)
+			if err != nil {
+				return nil, err
+			}
+			record.UserID = &userID
+			record.BalanceAfter = &balance
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return nil, err
+		}
+		transaction.Entries = append(transaction.Entries, record)
+	}
+	return &transaction, nil
+}
+
+// Balances - oba zostatky z jedného riadku (konzistentné čítanie); hráč bez peňaženky má 0
+func Balances(db *gorm.DB, userID uuid.UUID) common.Wallet {
+	wallet := common.Wallet{UserID: userID}
+	db.Where("user_id = ?", userID).First(&wallet)
+	return wallet
+}
+
+// Balance - zostatok v jednej mene
+func Balance(db *gorm.DB, userID uuid.UUID, currency string) int64 {
+	wallet := Balances(db, userID)
+	if currency == common.CurrencyCrystals {
+		return wallet.Crystals
+	}
+	return wallet.Credits
+}
+
+// Grant - admin pripíše (kladná suma) alebo stiahne (záporná suma) menu hráčovi
+func Grant(db *gorm.DB, adminID, userID uuid.UUID, currency string, amount int64, note string) (*common.LedgerTransaction, error) {
+	if amount == 0 {
+		return nil, ErrInvalidAmount
+	}
+	reason := ReasonAdminGrant
+	if amount < 0 {
+		reason = ReasonAdminRevoke
+	}
+
+	var transaction *common.LedgerTransaction
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		transaction, err = Post(tx, Posting{
+			Currency:      currency,
+			Reason:        reason,
+			ReferenceType: "user",
+			ReferenceID:   &userID,
+			CreatedBy:     &adminID,
+			Note:          note,
+			Entries:       Move(common.AccountMint, UserAccount(userID), amount),
+		})
+		return err
+	})
+	return transaction, err
+}
+
+// normalize - zahodí nulové sumy, overí vyváženie a zoradí účty (stabilné poradie zámkov riadkov)
+func normalize(entries []Entry) ([]Entry, error) {
+	var result []Entry
+	var sum int64
+	for _, entry := range entries {
+		if entry.Amount == 0 {
+			continue
+		}
+		if entry.Account == "" {
+			return nil, fmt.Errorf("%w: empty account", ErrUnbalanced)
+		}
+		sum += entry.Amount
+		result = append(result, entry)
+	}
+	if len(result) < 2 || sum != 0 {
+		return nil, ErrUnbalanced
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Account < result[j].Account })
+	return result, nil
+}
+
+func parseUserAccount(account string) (uuid.UUID, bool) {
+	if !strings.HasPrefix(account, userAccountPrefix) {
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(strings.TrimPrefix(account, userAccountPrefix))
+	return userID, err == nil
+}
+
+// applyBalance - zmení zostatok hráča a vráti nový; currency je overená cez ValidCurrency
+func applyBalance(tx *gorm.DB, userID uuid.UUID, currency string, amount int64) (int64, error) {
+	var balances []int64
+	if amount < 0 {
+		query := fmt.Sprintf(
+			"UPDATE wallets SET %[1]s = %[1]s + ?, updated_at = NOW() WHERE user_id = ? AND %[1]s + ? >= 0 RETURNING %[1]s",
+			currency)
+		if err := tx.Raw(query, amount, userID, amount).Scan(&balances).Error; err != nil {
+			return 0, err
+		}
+		if len(balances) == 0 {
+			return 0, ErrInsufficientFunds
+		}
+		return balances[0], nil
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO wallets (user_id, %[1]s, updated_at) VALUES (?, ?, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET %[1]s = wallets.%[1]s + EXCLUDED.%[1]s, updated_at = NOW()
+		RETURNING %[1]s`, currency)
+	if err := tx.Raw(query, userID, amount).Scan(&balances).Error; err != nil {
+		return 0, err
+	}
+	if len(balances) == 0 {
+		return 0, fmt.Errorf("wallet update for %s returned no balance", userID)
+	}
+	return balances[0], nil
+}
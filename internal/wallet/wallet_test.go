@@ -0,0 +1,54 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalize(t *testing.T) {
+	user := UserAccount(uuid.New())
+
+	entries, err := normalize([]Entry{
+		{Account: user, Amount: -100},
+		{Account: common.AccountMarketTax, Amount: 0}, // daň zaokrúhlená na 0
+		{Account: common.AccountMarketFees, Amount: 100},
+	})
+	if err != nil {
+		t.Fatalf("balanced posting rejected: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Account > entries[1].Account {
+		t.Errorf("expected 2 entries sorted by account, got %+v", entries)
+	}
+
+	if _, err := normalize(Move(user, common.AccountMint, 10)[:1]); !errors.Is(err, ErrUnbalanced) {
+		t.Errorf("single-sided posting: got %v, want ErrUnbalanced", err)
+	}
+	if _, err := normalize([]Entry{{Account: user, Amount: -10}, {Account: common.AccountMint, Amount: 9}}); !errors.Is(err, ErrUnbalanced) {
+		t.Errorf("posting that does not sum to zero: got %v, want ErrUnbalanced", err)
+	}
+}
+
+func TestUserAccount(t *testing.T) {
+	userID := uuid.New()
+	parsed, ok := parseUserAccount(UserAccount(userID))
+	if !ok || parsed != userID {
+		t.Errorf("parseUserAccount(UserAccount(id)) = %s, %v", parsed, ok)
+	}
+	if _, ok := parseUserAccount(common.AccountMint); ok {
+		t.Error("system account parsed as user account")
+	}
+}
+
+func TestValidCurrency(t *testing.T) {
+	if !ValidCurrency(common.CurrencyCredits) || !ValidCurrency(common.CurrencyCrystals) {
+		t.Error("known currencies must be valid")
+	}
+	// Mena sa dosádza ako názov stĺpca, nič iné nesmie prejsť
+	if ValidCurrency("credits; DROP TABLE wallets") || ValidCurrency("") {
+		t.Error("unknown currency accepted")
+	}
+}
@@ -0,0 +1,17 @@
+package xp
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// collectionXPBonus - trvalý XP bonus z dokončených zbierok (súčet collection_sets.bonus_xp_multiplier)
+func collectionXPBonus(db *gorm.DB, userID uuid.UUID) float64 {
+	var bonus float64
+	db.Table("player_collections").
+		Select("COALESCE(SUM(collection_sets.bonus_xp_multiplier), 0)").
+		Joins("JOIN collection_sets ON collection_sets.key = player_collections.set_key AND collection_sets.deleted_at IS NULL").
+		Where("player_collections.user_id = ?", userID).
+		Scan(&bonus)
+	return bonus
+}
@@ -0,0 +1,255 @@
+package xp
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"geoanomaly/internal/common"
+	"geoanomaly/internal/items"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Kľúč vzorca pre zber artefaktov (xp_formulas.key)
+const FormulaArtifact = "artifact_collect"
+
+// Ako dlho platí načítaný vzorec (admin úprava ho invaliduje hneď)
+const formulaTTL = time.Minute
+
+// DefaultArtifactFormula - pôvodné hodnoty calculateArtifactXP, zapíšu sa do xp_formulas pri štarte
+var DefaultArtifactFormula = common.XPFormula{
+	Key:    FormulaArtifact,
+	BaseXP: 10,
+	RarityBonuses: common.JSONB{
+		"common": 0, "rare": 5, "epic": 15, "legendary": 30,
+	},
+	BiomeBonuses: common.JSONB{
+		"forest": 0, "mountain": 5, "urban": 5, "water": 5,
+		"industrial": 10, "radioactive": 15, "chemical": 15,
+	},
+	ZoneTierBonus: 3,
+	TierMultipliers: common.JSONB{
+		"0": 1.0, "1": 1.05, "2": 1.1, "3": 1.15, "4": 1.25,
+	},
+	GearMultipliers: common.JSONB{
+		"geiger_counter": 0.05, "radiation_detector": 0.05,
+	},
+	DiminishingFree:  5,
+	DiminishingStep:  0.15,
+	DiminishingFloor: 0.25,
+	DiminishingHours: 24,
+}
+
+// ArtifactCollect - vstup pre výpočet XP za zber artefaktu (aj dry-run)
+type ArtifactCollect struct {
+	UserID     uuid.UUID `json:"user_id"`
+	ArtifactID uuid.UUID `json:"artifact_id"`
+	ZoneID     uuid.UUID `json:"zone_id"`
+	Rarity     string    `json:"rarity"`
+	Biome      string    `json:"biome"`
+	ZoneTier   int       `json:"zone_tier"`
+	AreaYield  float64   `json:"area_yield,omitempty"` // výnos bunky mapy (anti-farming), 0 = plný
+}
+
+// FormulaCache - aktuálny vzorec zdieľaný všetkými XP handlermi
+type FormulaCache struct {
+	mu       sync.RWMutex
+	formula  *common.XPFormula
+	loadedAt time.Time
+}
+
+var artifactFormula = &FormulaCache{}
+
+// InvalidateXPFormula - po úprave xp_formulas sa vzorec načíta znova
+func InvalidateXPFormula() {
+	artifactFormula.mu.Lock()
+	defer artifactFormula.mu.Unlock()
+	artifactFormula.formula = nil
+}
+
+func (fc *FormulaCache) get(db *gorm.DB) common.XPFormula {
+	fc.mu.RLock()
+	formula, loadedAt := fc.formula, fc.loadedAt
+	fc.mu.RUnlock()
+
+	if formula != nil && time.Since(loadedAt) < formulaTTL {
+		return *formula
+	}
+
+	var fresh common.XPFormula
+	if err := db.Where("key = ? AND deleted_at IS NULL", FormulaArtifact).First(&fresh).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Printf("❌ Failed to load XP formula: %v", err)
+		}
+		if formula != nil {
+			return *formula // starší vzorec je lepší ako predvolený
+		}
+		return DefaultArtifactFormula
+	}
+
+	fc.mu.Lock()
+	fc.formula = &fresh
+	fc.loadedAt = time.Now()
+	fc.mu.Unlock()
+
+	return fresh
+}
+
+// SeedFormula - zapíše predvolený vzorec, ak ešte neexistuje (existujúci nemení)
+func SeedFormula(db *gorm.DB) error {
+	formula := DefaultArtifactFormula
+	result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).Create(&formula)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("🧮 Seeded XP formula: %s", formula.Key)
+	}
+	return nil
+}
+
+// AwardArtifactXP - zber artefaktu podľa vzorca a násobkov (idempotentné podľa ID artefaktu)
+func (h *Handler) AwardArtifactXP(collect ArtifactCollect) (*XPResult, error) {
+	breakdown, multipliers := h.artifactXP(artifactFormula.get(h.db), collect, time.Now())
+
+	result, err := h.Grant(XPGrant{
+		UserID:     collect.UserID,
+		SourceType: SourceArtifactCollect,
+		SourceID:   collect.ArtifactID.String(),
+		Breakdown:  breakdown,
+		Multiplier: multipliers.stacked(), // bonus zo zbierok pripočíta Grant
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !result.Duplicate {
+		result.Multipliers = &multipliers
+	}
+	return result, nil
+}
+
+// artifactXP - breakdown zo vzorca + všetky násobky pre daného hráča a zónu
+func (h *Handler) artifactXP(formula common.XPFormula, collect ArtifactCollect, now time.Time) (XPBreakdown, XPMultipliers) {
+	var user common.User
+	h.db.Select("id", "tier").First(&user, "id = ?", collect.UserID)
+
+	// ✅ NEW: Vybavený gear z loadoutu (typy pre gear_multipliers + stat xp_bonus)
+	var gearTypes []string
+	for _, def := range items.EquippedDefinitions(h.db, collect.UserID) {
+		gearTypes = append(gearTypes, def.Type)
+	}
+	stats := items.Stats(h.db, collect.UserID)
+
+	var zoneCollects int64
+	if collect.ZoneID != uuid.Nil {
+		since := now.Add(-time.Duration(formula.DiminishingHours) * time.Hour)
+		// item_collects, nie inventár - stackovaný zber nepridá riadok a predaný item riadok nezmaže
+		h.db.Model(&common.ItemCollect{}).
+			Where("user_id = ? AND item_type = 'artifact' AND zone_id = ? AND item_id <> ? AND created_at >= ?",
+				collect.UserID, collect.ZoneID, collect.ArtifactID, since).
+			Count(&zoneCollects)
+	}
+
+	eventMultiplier, eventNames := eventMultiplierFor(h.activeEvents(now), collect.Biome)
+
+	multipliers := XPMultipliers{
+		Event:        eventMultiplier,
+		Events:       eventNames,
+		Tier:         tierMultiplier(formula, user.Tier),
+		Gear:         gearMultiplier(formula, gearTypes) + stats.XPBonus,
+		Diminishing:  diminishingMultiplier(formula, int(zoneCollects)),
+		ZoneCollects: int(zoneCollects),
+		Area:         areaMultiplier(collect.AreaYield),
+		Collection:   collectionXPBonus(h.db, collect.UserID),
+	}
+	multipliers.Total = roundMultiplier(multipliers.stacked() + multipliers.Collection)
+
+	return artifactBreakdown(formula, collect.Rarity, collect.Biome, collect.ZoneTier), multipliers
+}
+
+// activeEvents - XP eventy bežiace v čase now
+func (h *Handler) activeEvents(now time.Time) []common.XPEvent {
+	var events []common.XPEvent
+	h.db.Where("starts_at <= ? AND ends_at > ? AND deleted_at IS NULL", now, now).Order("starts_at ASC").Find(&events)
+	return events
+}
+
+func artifactBreakdown(formula common.XPFormula, rarity, biome string, zoneTier int) XPBreakdown {
+	return XPBreakdown{
+		BaseXP:      formula.BaseXP,
+		RarityBonus: int(formulaValue(formula.RarityBonuses, rarity, 0)),
+		BiomeBonus:  int(formulaValue(formula.BiomeBonuses, biome, 0)),
+		TierBonus:   zoneTier * formula.ZoneTierBonus,
+	}
+}
+
+// eventMultiplierFor - eventy sa násobia; event s biomom platí len pre daný biom
+func eventMultiplierFor(events []common.XPEvent, biome string) (float64, []string) {
+	multiplier := 1.0
+	var names []string
+	for _, event := range events {
+		if event.Biome != "" && event.Biome != biome {
+			continue
+		}
+		if event.Multiplier > 0 {
+			multiplier *= event.Multiplier
+			names = append(names, event.Name)
+		}
+	}
+	return multiplier, names
+}
+
+// areaMultiplier - výnos bunky počíta game (0 = neznámy/plný)
+func areaMultiplier(yield float64) float64 {
+	if yield <= 0 || yield > 1 {
+		return 1
+	}
+	return yield
+}
+
+func tierMultiplier(formula common.XPFormula, tier int) float64 {
+	return formulaValue(formula.TierMultipliers, strconv.Itoa(tier), 1)
+}
+
+// gearMultiplier - 1 + súčet bonusov vybavených typov gearu (každý typ raz)
+func gearMultiplier(formula common.XPFormula, gearTypes []string) float64 {
+	multiplier := 1.0
+	seen := make(map[string]bool, len(gearTypes))
+	for _, gearType := range gearTypes {
+		if seen[gearType] {
+			continue
+		}
+		seen[gearType] = true
+		multiplier += formulaValue(formula.GearMultipliers, gearType, 0)
+	}
+	return multiplier
+}
+
+// diminishingMultiplier - po DiminishingFree zberoch v zóne klesá o DiminishingStep až na DiminishingFloor
+func diminishingMultiplier(formula common.XPFormula, zoneCollects int) float64 {
+	over := zoneCollects - formula.DiminishingFree + 1
+	if over <= 0 {
+		return 1
+	}
+	return math.Max(formula.DiminishingFloor, 1-float64(over)*formula.DiminishingStep)
+}
+
+// formulaValue - číslo z JSONB (z DB float64, z predvolených hodnôt int/float64)
+func formulaValue(values common.JSONB, key string, fallback float64) float64 {
+	switch v := values[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return fallback
+}
+
+func roundMultiplier(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
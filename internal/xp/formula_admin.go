@@ -0,0 +1,308 @@
+package xp
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UpdateXPFormulaRequest - len zadané polia sa menia
+type UpdateXPFormulaRequest struct {
+	BaseXP           *int         `json:"base_xp,omitempty" binding:"omitempty,min=0"`
+	RarityBonuses    common.JSONB `json:"rarity_bonuses,omitempty"`
+	BiomeBonuses     common.JSONB `json:"biome_bonuses,omitempty"`
+	ZoneTierBonus    *int         `json:"zone_tier_bonus,omitempty" binding:"omitempty,min=0"`
+	TierMultipliers  common.JSONB `json:"tier_multipliers,omitempty"`
+	GearMultipliers  common.JSONB `json:"gear_multipliers,omitempty"`
+	DiminishingFree  *int         `json:"diminishing_free,omitempty" binding:"omitempty,min=0"`
+	DiminishingStep  *float64     `json:"diminishing_step,omitempty" binding:"omitempty,min=0,max=1"`
+	DiminishingFloor *float64     `json:"diminishing_floor,omitempty" binding:"omitempty,gt=0,max=1"`
+	DiminishingHours *int         `json:"diminishing_hours,omitempty" binding:"omitempty,min=1,max=720"`
+}
+
+type CreateXPEventRequest struct {
+	Name       string    `json:"name" binding:"required,max=100"`
+	Multiplier float64   `json:"multiplier" binding:"required,gt=0,max=10"`
+	Biome      string    `json:"biome,omitempty"`
+	StartsAt   time.Time `json:"starts_at" binding:"required"`
+	EndsAt     time.Time `json:"ends_at" binding:"required"`
+}
+
+// XPDryRunRequest - artifact_id, alebo ručne zadané rarity/biome/zone_tier (+ voliteľne zone_id)
+type XPDryRunRequest struct {
+	UserID     uuid.UUID               `json:"user_id" binding:"required"`
+	ArtifactID *uuid.UUID              `json:"artifact_id,omitempty"`
+	ZoneID     *uuid.UUID              `json:"zone_id,omitempty"`
+	Rarity     string                  `json:"rarity,omitempty"`
+	Biome      string                  `json:"biome,omitempty"`
+	ZoneTier   int                     `json:"zone_tier,omitempty" binding:"omitempty,min=0,max=4"`
+	AreaYield  float64                 `json:"area_yield,omitempty" binding:"omitempty,gt=0,max=1"` // simulácia vyčerpanej oblasti
+	Formula    *UpdateXPFormulaRequest `json:"formula,omitempty"`                                   // neuložené zmeny vzorca na porovnanie
+}
+
+// GetXPFormula - GET /admin/xp/formula
+func (h *Handler) GetXPFormula(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"formula":       artifactFormula.get(h.db),
+		"active_events": h.activeEvents(time.Now()),
+	})
+}
+
+// UpdateXPFormula - PUT /admin/xp/formula
+func (h *Handler) UpdateXPFormula(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var req UpdateXPFormulaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if field, ok := validFormulaMaps(req); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid values in " + field})
+		return
+	}
+
+	var formula common.XPFormula
+	if err := h.db.Where("key = ? AND deleted_at IS NULL", FormulaArtifact).First(&formula).Error; err != nil {
+		formula = DefaultArtifactFormula
+	}
+	applyFormulaUpdate(&formula, req)
+	admin := adminID.(uuid.UUID)
+	formula.UpdatedBy = &admin
+
+	if err := h.db.Save(&formula).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update XP formula"})
+		return
+	}
+	InvalidateXPFormula()
+
+	log.Printf("🧮 XP formula updated by admin %s", admin)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "XP formula updated",
+		"formula": formula,
+	})
+}
+
+// ListXPEvents - GET /admin/xp/events
+func (h *Handler) ListXPEvents(c *gin.Context) {
+	var events []common.XPEvent
+	if err := h.db.Where("deleted_at IS NULL").Order("starts_at DESC").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch XP events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"total":  len(events),
+	})
+}
+
+// CreateXPEvent - POST /admin/xp/events
+func (h *Handler) CreateXPEvent(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var req CreateXPEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must be after starts_at"})
+		return
+	}
+
+	admin := adminID.(uuid.UUID)
+	event := common.XPEvent{
+		Name:       req.Name,
+		Multiplier: req.Multiplier,
+		Biome:      req.Biome,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		CreatedBy:  &admin,
+	}
+	if err := h.db.Create(&event).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create XP event"})
+		return
+	}
+
+	log.Printf("🎉 XP event created: %s x%.2f (%s → %s)", event.Name, event.Multiplier,
+		event.StartsAt.Format(time.RFC3339), event.EndsAt.Format(time.RFC3339))
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "XP event created",
+		"event":   event,
+	})
+}
+
+// DeleteXPEvent - DELETE /admin/xp/events/:id
+func (h *Handler) DeleteXPEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	result := h.db.Model(&common.XPEvent{}).
+		Where("id = ? AND deleted_at IS NULL", eventID).
+		Update("deleted_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete XP event"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "XP event not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "XP event deleted"})
+}
+
+// DryRunArtifactXP - POST /admin/xp/dry-run
+// Koľko XP by hráč dostal za zber - nič nezapisuje
+func (h *Handler) DryRunArtifactXP(c *gin.Context) {
+	var req XPDryRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user common.User
+	if err := h.db.Select("id", "username", "tier").First(&user, "id = ?", req.UserID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	collect := ArtifactCollect{
+		UserID:    user.ID,
+		Rarity:    req.Rarity,
+		Biome:     req.Biome,
+		ZoneTier:  req.ZoneTier,
+		AreaYield: req.AreaYield,
+	}
+	if req.ZoneID != nil {
+		collect.ZoneID = *req.ZoneID
+	}
+
+	if req.ArtifactID != nil {
+		var artifact common.Artifact
+		if err := h.db.First(&artifact, "id = ?", *req.ArtifactID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Artifact not found"})
+			return
+		}
+		collect.ArtifactID = artifact.ID
+		collect.ZoneID = artifact.ZoneID
+		collect.Rarity = artifact.Rarity
+		collect.Biome = artifact.Biome
+
+		var zone common.Zone
+		if err := h.db.Select("id", "tier_required").First(&zone, "id = ?", artifact.ZoneID).Error; err == nil {
+			collect.ZoneTier = zone.TierRequired
+		}
+	} else if collect.Rarity == "" || collect.Biome == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "artifact_id or rarity and biome required"})
+		return
+	}
+
+	formula := artifactFormula.get(h.db)
+	if req.Formula != nil {
+		if field, ok := validFormulaMaps(*req.Formula); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid values in " + field})
+			return
+		}
+		applyFormulaUpdate(&formula, *req.Formula)
+	}
+
+	breakdown, multipliers := h.artifactXP(formula, collect, time.Now())
+
+	response := gin.H{
+		"user_id":     user.ID,
+		"username":    user.Username,
+		"user_tier":   user.Tier,
+		"collect":     collect,
+		"breakdown":   breakdown,
+		"base_xp":     breakdown.Total(),
+		"multipliers": multipliers,
+		"xp_awarded":  int(math.Round(float64(breakdown.Total()) * multipliers.Total)),
+		"formula":     formula,
+		"dry_run":     true,
+	}
+
+	if req.ArtifactID != nil {
+		var awarded int64
+		h.db.Model(&common.XPLedgerEntry{}).
+			Where("user_id = ? AND source_type = ? AND source_id = ?", user.ID, SourceArtifactCollect, req.ArtifactID.String()).
+			Count(&awarded)
+		response["already_awarded"] = awarded > 0
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func applyFormulaUpdate(formula *common.XPFormula, req UpdateXPFormulaRequest) {
+	if req.BaseXP != nil {
+		formula.BaseXP = *req.BaseXP
+	}
+	if req.RarityBonuses != nil {
+		formula.RarityBonuses = req.RarityBonuses
+	}
+	if req.BiomeBonuses != nil {
+		formula.BiomeBonuses = req.BiomeBonuses
+	}
+	if req.ZoneTierBonus != nil {
+		formula.ZoneTierBonus = *req.ZoneTierBonus
+	}
+	if req.TierMultipliers != nil {
+		formula.TierMultipliers = req.TierMultipliers
+	}
+	if req.GearMultipliers != nil {
+		formula.GearMultipliers = req.GearMultipliers
+	}
+	if req.DiminishingFree != nil {
+		formula.DiminishingFree = *req.DiminishingFree
+	}
+	if req.DiminishingStep != nil {
+		formula.DiminishingStep = *req.DiminishingStep
+	}
+	if req.DiminishingFloor != nil {
+		formula.DiminishingFloor = *req.DiminishingFloor
+	}
+	if req.DiminishingHours != nil {
+		formula.DiminishingHours = *req.DiminishingHours
+	}
+}
+
+// validFormulaMaps - JSONB mapy vzorca musia obsahovať len čísla (násobky tieru kladné, inak nezáporné)
+func validFormulaMaps(req UpdateXPFormulaRequest) (string, bool) {
+	maps := []struct {
+		field    string
+		values   common.JSONB
+		positive bool
+	}{
+		{"rarity_bonuses", req.RarityBonuses, false},
+		{"biome_bonuses", req.BiomeBonuses, false},
+		{"tier_multipliers", req.TierMultipliers, true},
+		{"gear_multipliers", req.GearMultipliers, false},
+	}
+	for _, m := range maps {
+		for _, value := range m.values {
+			n, ok := value.(float64)
+			if !ok || n < 0 || (m.positive && n == 0) {
+				return m.field, false
+			}
+		}
+	}
+	return "", true
+}
@@ -0,0 +1,63 @@
+package xp
+
+import (
+	"testing"
+
+	"geoanomaly/internal/common"
+)
+
+func TestArtifactBreakdownMatchesDefaults(t *testing.T) {
+	breakdown := artifactBreakdown(DefaultArtifactFormula, "epic", "industrial", 2)
+	if breakdown.BaseXP != 10 || breakdown.RarityBonus != 15 || breakdown.BiomeBonus != 10 || breakdown.TierBonus != 6 {
+		t.Errorf("unexpected breakdown: %+v", breakdown)
+	}
+
+	// Hodnoty z DB prichádzajú ako float64
+	formula := DefaultArtifactFormula
+	formula.RarityBonuses = common.JSONB{"epic": float64(40)}
+	if got := artifactBreakdown(formula, "epic", "unknown", 0); got.RarityBonus != 40 || got.BiomeBonus != 0 {
+		t.Errorf("unexpected breakdown from stored formula: %+v", got)
+	}
+}
+
+func TestDiminishingMultiplier(t *testing.T) {
+	formula := common.XPFormula{DiminishingFree: 2, DiminishingStep: 0.25, DiminishingFloor: 0.3}
+
+	cases := []struct {
+		collects int
+		want     float64
+	}{
+		{0, 1}, {1, 1}, {2, 0.75}, {3, 0.5}, {4, 0.3}, {50, 0.3},
+	}
+	for _, tc := range cases {
+		if got := diminishingMultiplier(formula, tc.collects); got != tc.want {
+			t.Errorf("diminishingMultiplier(%d) = %v, want %v", tc.collects, got, tc.want)
+		}
+	}
+}
+
+func TestStackedMultipliers(t *testing.T) {
+	events := []common.XPEvent{
+		{Name: "Double XP Weekend", Multiplier: 2},
+		{Name: "Forest Week", Multiplier: 1.5, Biome: "forest"},
+	}
+	if m, names := eventMultiplierFor(events, "mountain"); m != 2 || len(names) != 1 {
+		t.Errorf("expected only the global event for mountain, got %v %v", m, names)
+	}
+	if m, _ := eventMultiplierFor(events, "forest"); m != 3 {
+		t.Errorf("expected events to stack for forest, got %v", m)
+	}
+
+	if got := gearMultiplier(DefaultArtifactFormula, []string{"geiger_counter", "geiger_counter", "pickaxe"}); got != 1.05 {
+		t.Errorf("each gear type should count once, got %v", got)
+	}
+	if got := (XPMultipliers{Event: 2, Tier: 1, Gear: 1, Diminishing: 0.5, Area: 0.5}).stacked(); got != 0.5 {
+		t.Errorf("expected multipliers to stack multiplicatively, got %v", got)
+	}
+	if got := areaMultiplier(0); got != 1 {
+		t.Errorf("unknown area yield should not reduce XP, got %v", got)
+	}
+	if got := tierMultiplier(DefaultArtifactFormula, 9); got != 1 {
+		t.Errorf("unknown tier should default to 1, got %v", got)
+	}
+}
@@ -0,0 +1,318 @@
+package xp
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrLedgerEntryNotFound = errors.New("XP ledger entry not found")
+	ErrAlreadyReversed     = errors.New("XP ledger entry already reversed")
+	ErrCannotReverse       = errors.New("reversal entries cannot be reversed")
+)
+
+// Grant - jediné miesto, kde sa mení users.xp
+// Zápis do ledgeru a inkrement XP bežia v jednej transakcii so zámkom na riadku používateľa
+func (h *Handler) Grant(grant XPGrant) (*XPResult, error) {
+	// ✅ NEW: Bonus zo zbierok platí len pre herné XP (nie pre pevné odmeny a reversal)
+	bonus := 0.0
+	if grant.Amount == 0 {
+		bonus = collectionXPBonus(h.db, grant.UserID)
+	}
+	amount, multiplier := grantAmount(grant, bonus)
+
+	var result *XPResult
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var user common.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "xp", "level").
+			First(&user, "id = ?", grant.UserID).Error; err != nil {
+			return err
+		}
+
+		amount = clampToBalance(user.XP, amount)
+		newXP := user.XP + amount
+
+		now := time.Now()
+		seasonID, seasonXP, err := seasonXPFor(tx, grant, amount, now)
+		if err != nil {
+			return err
+		}
+
+		entry := common.XPLedgerEntry{
+			ID:           uuid.New(),
+			UserID:       grant.UserID,
+			Amount:       amount,
+			SourceType:   grant.SourceType,
+			SourceID:     grant.SourceID,
+			Breakdown:    breakdownToJSONB(grant.Breakdown),
+			Multiplier:   multiplier,
+			BalanceAfter: newXP,
+			ReversalOf:   grant.ReversalOf,
+			Reason:       grant.Reason,
+			CreatedBy:    grant.CreatedBy,
+			SeasonID:     seasonID,
+			SeasonXP:     seasonXP,
+		}
+
+		insert := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry)
+		if insert.Error != nil {
+			return insert.Error
+		}
+		if insert.RowsAffected == 0 {
+			// Tento zdroj už XP dostal - nič nepripočítaj
+			result = &XPResult{
+				TotalXP:      user.XP,
+				CurrentLevel: user.Level,
+				Breakdown:    grant.Breakdown,
+				Duplicate:    true,
+				Source:       grant.SourceType,
+			}
+			return nil
+		}
+
+		newLevel := h.getLevelFromXP(newXP)
+		updates := map[string]interface{}{"xp": gorm.Expr("xp + ?", amount)}
+		if newLevel != user.Level {
+			updates["level"] = newLevel
+		}
+		if err := tx.Model(&common.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		if seasonID != nil && seasonXP != 0 {
+			if err := addSeasonXP(tx, *seasonID, user.ID, seasonXP, now); err != nil {
+				return err
+			}
+		}
+
+		result = &XPResult{
+			XPGained:      amount,
+			TotalXP:       newXP,
+			CurrentLevel:  newLevel,
+			LevelUp:       newLevel > user.Level,
+			Breakdown:     grant.Breakdown,
+			LedgerEntryID: &entry.ID,
+			Source:        grant.SourceType,
+			SeasonXP:      seasonXP,
+		}
+
+		if result.LevelUp {
+			unlocks, err := h.grantLevelRewards(tx, user.ID, newLevel)
+			if err != nil {
+				return err
+			}
+
+			result.LevelUpInfo = &LevelUpInfo{
+				OldLevel:    user.Level,
+				NewLevel:    newLevel,
+				Rewards:     describeUnlocks(unlocks),
+				Unlocks:     unlocks,
+				LevelUpTime: time.Now().Unix(),
+			}
+			if def, ok := findLevel(levelCurve.get(h.db), newLevel); ok {
+				result.LevelUpInfo.LevelName = def.LevelName
+			}
+			log.Printf("🎉 LEVEL UP! User %s: %d → %d (XP: %d → %d)", user.ID, user.Level, newLevel, user.XP, newXP)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReverseEntry - admin reversal (fraud cleanup), zapíše protizáznam so záporným amount
+func (h *Handler) ReverseEntry(entryID, adminID uuid.UUID, reason string) (*XPResult, error) {
+	var entry common.XPLedgerEntry
+	if err := h.db.First(&entry, "id = ?", entryID).Error; err != nil {
+		return nil, ErrLedgerEntryNotFound
+	}
+	grant, err := reversalGrant(entry, adminID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.Grant(grant)
+	if err != nil {
+		return nil, err
+	}
+	if result.Duplicate {
+		return nil, ErrAlreadyReversed
+	}
+
+	log.Printf("↩️ XP reversal: entry %s (%d XP) reversed for user %s by admin %s: %s",
+		entry.ID, entry.Amount, entry.UserID, adminID, reason)
+	return result, nil
+}
+
+// grantAmount - XP na zápis do ledgeru a použitý multiplikátor
+// Pevný Amount (odmeny, reversal) sa neprenásobuje, herné XP = Breakdown * (Multiplier + bonus)
+func grantAmount(grant XPGrant, bonus float64) (int, float64) {
+	multiplier := grant.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	if grant.Amount != 0 {
+		return grant.Amount, multiplier
+	}
+
+	multiplier += bonus
+	return int(math.Round(float64(grant.Breakdown.Total()) * multiplier)), multiplier
+}
+
+// clampToBalance - users.xp má check_xp >= 0, záporný zápis zoberie najviac aktuálny zostatok
+func clampToBalance(balance, amount int) int {
+	if balance+amount < 0 {
+		return -balance
+	}
+	return amount
+}
+
+// reversalGrant - protizáznam k entry; SourceID = ID pôvodného záznamu, takže druhý reversal je duplikát
+func reversalGrant(entry common.XPLedgerEntry, adminID uuid.UUID, reason string) (XPGrant, error) {
+	if entry.SourceType == SourceReversal {
+		return XPGrant{}, ErrCannotReverse
+	}
+
+	return XPGrant{
+		UserID:     entry.UserID,
+		SourceType: SourceReversal,
+		SourceID:   entry.ID.String(),
+		Amount:     -entry.Amount,
+		Reason:     reason,
+		CreatedBy:  &adminID,
+		ReversalOf: &entry.ID,
+	}, nil
+}
+
+// ============================================
+// HTTP ENDPOINTS
+// ============================================
+
+// GetXPHistory - GET /user/xp/history
+func (h *Handler) GetXPHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	h.respondXPHistory(c, userID.(uuid.UUID))
+}
+
+// GetUserXPHistory - GET /admin/users/:id/xp/history
+func (h *Handler) GetUserXPHistory(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	h.respondXPHistory(c, userID)
+}
+
+// ReverseXPEntry - POST /admin/xp/:id/reverse
+func (h *Handler) ReverseXPEntry(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	entryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ledger entry ID"})
+		return
+	}
+
+	var req ReverseXPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.ReverseEntry(entryID, adminID.(uuid.UUID), req.Reason)
+	switch {
+	case errors.Is(err, ErrLedgerEntryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ErrAlreadyReversed), errors.Is(err, ErrCannotReverse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reverse XP entry"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "XP entry reversed",
+		"reversed":      entryID,
+		"xp_change":     result.XPGained,
+		"total_xp":      result.TotalXP,
+		"current_level": result.CurrentLevel,
+		"reversal_id":   result.LedgerEntryID,
+	})
+}
+
+func (h *Handler) respondXPHistory(c *gin.Context, userID uuid.UUID) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := h.db.Model(&common.XPLedgerEntry{}).Where("user_id = ?", userID)
+	if sourceType := c.Query("source_type"); sourceType != "" {
+		query = query.Where("source_type = ?", sourceType)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var entries []common.XPLedgerEntry
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch XP history"})
+		return
+	}
+
+	var user common.User
+	h.db.Select("id", "xp", "level").First(&user, "id = ?", userID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries":       entries,
+		"total":         total,
+		"limit":         limit,
+		"offset":        offset,
+		"total_xp":      user.XP,
+		"current_level": user.Level,
+	})
+}
+
+func breakdownToJSONB(breakdown XPBreakdown) common.JSONB {
+	result := common.JSONB{}
+	raw, err := json.Marshal(breakdown)
+	if err != nil {
+		return result
+	}
+	json.Unmarshal(raw, &result)
+	return result
+}
@@ -0,0 +1,67 @@
+package xp
+
+import (
+	"errors"
+	"testing"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+)
+
+func TestGrantAmount(t *testing.T) {
+	breakdown := XPBreakdown{BaseXP: 10, RarityBonus: 5, BiomeBonus: 3, TierBonus: 2}
+
+	amount, multiplier := grantAmount(XPGrant{Breakdown: breakdown}, 0)
+	if amount != 20 || multiplier != 1 {
+		t.Errorf("plain grant = %d (x%.2f), want 20 (x1)", amount, multiplier)
+	}
+	amount, multiplier = grantAmount(XPGrant{Breakdown: breakdown, Multiplier: 1.5}, 0.25)
+	if amount != 35 || multiplier != 1.75 {
+		t.Errorf("grant with collection bonus = %d (x%.2f), want 35 (x1.75)", amount, multiplier)
+	}
+	// Pevná odmena aj reversal idú do ledgeru presne tak, ako prišli
+	if amount, _ := grantAmount(XPGrant{Amount: 100, Multiplier: 2, Breakdown: breakdown}, 0.25); amount != 100 {
+		t.Errorf("fixed amount = %d, want 100", amount)
+	}
+	if amount, _ := grantAmount(XPGrant{Amount: -40}, 0); amount != -40 {
+		t.Errorf("reversal amount = %d, want -40", amount)
+	}
+}
+
+func TestClampToBalance(t *testing.T) {
+	if got := clampToBalance(500, 120); got != 120 {
+		t.Errorf("positive grant = %d, want 120", got)
+	}
+	if got := clampToBalance(500, -120); got != -120 {
+		t.Errorf("covered reversal = %d, want -120", got)
+	}
+	if got := clampToBalance(80, -120); got != -80 {
+		t.Errorf("reversal over balance = %d, want -80 (xp must not go negative)", got)
+	}
+}
+
+func TestReversalGrant(t *testing.T) {
+	adminID := uuid.New()
+	entry := common.XPLedgerEntry{ID: uuid.New(), UserID: uuid.New(), Amount: 75, SourceType: SourceDistance}
+
+	grant, err := reversalGrant(entry, adminID, "GPS spoofing")
+	if err != nil {
+		t.Fatalf("reversal of a grant: %v", err)
+	}
+	if grant.UserID != entry.UserID || grant.Amount != -75 || grant.SourceType != SourceReversal {
+		t.Errorf("reversal grant = %+v", grant)
+	}
+	// SourceID = pôvodný záznam, takže druhý reversal narazí na unikátny index ako duplikát
+	if grant.SourceID != entry.ID.String() || grant.ReversalOf == nil || *grant.ReversalOf != entry.ID {
+		t.Errorf("reversal must point at entry %s, got source %q / %v", entry.ID, grant.SourceID, grant.ReversalOf)
+	}
+	if grant.CreatedBy == nil || *grant.CreatedBy != adminID || grant.Reason != "GPS spoofing" {
+		t.Errorf("reversal audit fields = %v / %q", grant.CreatedBy, grant.Reason)
+	}
+
+	entry.SourceType = SourceReversal
+	if _, err := reversalGrant(entry, adminID, "undo"); !errors.Is(err, ErrCannotReverse) {
+		t.Errorf("reversal of a reversal: got %v, want ErrCannotReverse", err)
+	}
+}
@@ -0,0 +1,293 @@
+package xp
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Ako dlho platí načítaná level krivka (level_definitions sa mení len výnimočne)
+const levelCurveTTL = 10 * time.Minute
+
+// Počet nasledujúcich levelov v progress endpointe
+const upcomingLevelsCount = 3
+
+// LevelCurveCache - zoradené level_definitions zdieľané všetkými XP handlermi
+type LevelCurveCache struct {
+	mu       sync.RWMutex
+	levels   []common.LevelDefinition
+	loadedAt time.Time
+}
+
+var levelCurve = &LevelCurveCache{}
+
+// InvalidateLevelCurve - po úprave level_definitions sa krivka načíta znova
+func InvalidateLevelCurve() {
+	levelCurve.mu.Lock()
+	defer levelCurve.mu.Unlock()
+	levelCurve.levels = nil
+}
+
+func (lc *LevelCurveCache) get(db *gorm.DB) []common.LevelDefinition {
+	lc.mu.RLock()
+	levels, loadedAt := lc.levels, lc.loadedAt
+	lc.mu.RUnlock()
+
+	if levels != nil && time.Since(loadedAt) < levelCurveTTL {
+		return levels
+	}
+
+	var fresh []common.LevelDefinition
+	if err := db.Order("level ASC").Find(&fresh).Error; err != nil {
+		log.Printf("❌ Failed to load level curve: %v", err)
+		return levels // staršia krivka je lepšia ako žiadna
+	}
+
+	lc.mu.Lock()
+	lc.levels = fresh
+	lc.loadedAt = time.Now()
+	lc.mu.Unlock()
+
+	return fresh
+}
+
+// LevelUnlock - jedna odmena za dosiahnutý level
+type LevelUnlock struct {
+	Level    int    `json:"level"`
+	Kind     string `json:"kind"` // feature, cosmetic, item
+	Key      string `json:"key"`
+	Quantity int    `json:"quantity,omitempty"`
+}
+
+// LevelProgress - GET /user/level/progress
+type LevelProgress struct {
+	Level           int                      `json:"level"`
+	LevelName       string                   `json:"level_name"`
+	TotalXP         int                      `json:"total_xp"`
+	CurrentLevelXP  int                      `json:"current_level_xp"`
+	NextLevelXP     int                      `json:"next_level_xp,omitempty"`
+	XPIntoLevel     int                      `json:"xp_into_level"`
+	XPToNextLevel   int                      `json:"xp_to_next_level"`
+	ProgressPercent float64                  `json:"progress_percent"`
+	MaxLevel        bool                     `json:"max_level"`
+	Upcoming        []common.LevelDefinition `json:"upcoming"`
+	Features        common.JSONB             `json:"features"`
+	Cosmetics       common.JSONB             `json:"cosmetics"`
+}
+
+// Najvyšší level, na ktorý hráč s daným XP má nárok (krivka zoradená vzostupne)
+func levelForXP(levels []common.LevelDefinition, totalXP int) int {
+	level := 1
+	for _, def := range levels {
+		if totalXP >= def.XPRequired && def.Level > level {
+			level = def.Level
+		}
+	}
+	return level
+}
+
+func findLevel(levels []common.LevelDefinition, level int) (common.LevelDefinition, bool) {
+	index := sort.Search(len(levels), func(i int) bool { return levels[i].Level >= level })
+	if index < len(levels) && levels[index].Level == level {
+		return levels[index], true
+	}
+	return common.LevelDefinition{}, false
+}
+
+// Odmeny za levely (fromLevel, toLevel] - pri skoku o viac levelov za každý level zvlášť
+func unlocksBetween(levels []common.LevelDefinition, fromLevel, toLevel int) []LevelUnlock {
+	var unlocks []LevelUnlock
+	for _, def := range levels {
+		if def.Level <= fromLevel || def.Level > toLevel {
+			continue
+		}
+		for _, key := range sortedKeys(def.FeaturesUnlocked) {
+			unlocks = append(unlocks, LevelUnlock{Level: def.Level, Kind: "feature", Key: key})
+		}
+		for _, key := range sortedKeys(def.CosmeticUnlocks) {
+			unlocks = append(unlocks, LevelUnlock{Level: def.Level, Kind: "cosmetic", Key: key})
+		}
+		for _, key := range sortedKeys(def.ItemRewards) {
+			quantity := 1
+			if reward, ok := def.ItemRewards[key].(map[string]interface{}); ok {
+				if q, ok := reward["quantity"].(float64); ok && q > 0 {
+					quantity = int(q)
+				}
+			}
+			unlocks = append(unlocks, LevelUnlock{Level: def.Level, Kind: "item", Key: key, Quantity: quantity})
+		}
+	}
+	return unlocks
+}
+
+func sortedKeys(m common.JSONB) []string {
+	keys := make([]string, 0, len(m))
+	for key, value := range m {
+		if enabled, ok := value.(bool); ok && !enabled {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// grantLevelRewards - beží v Grant transakcii; odmena za každý level sa udelí len raz
+// (rewarded_level chráni pred opakovaným ziskom po reversale a novom level-upe)
+func (h *Handler) grantLevelRewards(tx *gorm.DB, userID uuid.UUID, newLevel int) ([]LevelUnlock, error) {
+	var user common.User
+	if err := tx.Select("id", "rewarded_level", "features", "cosmetics").First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	if newLevel <= user.RewardedLevel {
+		return nil, nil
+	}
+
+	levels := levelCurve.get(h.db)
+	unlocks := unlocksBetween(levels, user.RewardedLevel, newLevel)
+
+	features := user.Features
+	if features == nil {
+		features = common.JSONB{}
+	}
+	cosmetics := user.Cosmetics
+	if cosmetics == nil {
+		cosmetics = common.JSONB{}
+	}
+
+	for _, unlock := range unlocks {
+		switch unlock.Kind {
+		case "feature":
+			features[unlock.Key] = true
+		case "cosmetic":
+			cosmetics[unlock.Key] = true
+		case "item":
+			def, _ := findLevel(levels, unlock.Level)
+			if err := tx.Create(levelRewardItem(userID, unlock, def.ItemRewards[unlock.Key])).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Model(&common.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"rewarded_level": newLevel,
+		"features":       features,
+		"cosmetics":      cosmetics,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return unlocks, nil
+}
+
+func levelRewardItem(userID uuid.UUID, unlock LevelUnlock, raw interface{}) *common.InventoryItem {
+	reward, _ := raw.(map[string]interface{})
+
+	itemType, _ := reward["item_type"].(string)
+	if itemType == "" {
+		itemType = "gear"
+	}
+
+	properties := common.JSONB{}
+	for key, value := range reward {
+		if key != "item_type" && key != "quantity" {
+			properties[key] = value
+		}
+	}
+	if _, ok := properties["name"]; !ok {
+		properties["name"] = unlock.Key
+	}
+	properties["level_reward"] = unlock.Level
+	properties["acquired_at"] = time.Now().Unix()
+
+	return &common.InventoryItem{
+		UserID:        userID,
+		ItemType:      itemType,
+		ItemID:        uuid.New(),
+		Quantity:      unlock.Quantity,
+		Properties:    properties,
+		DefinitionKey: common.ItemDefinitionKey(itemType, properties),
+	}
+}
+
+func describeUnlocks(unlocks []LevelUnlock) []string {
+	rewards := make([]string, 0, len(unlocks))
+	for _, unlock := range unlocks {
+		switch unlock.Kind {
+		case "feature":
+			rewards = append(rewards, fmt.Sprintf("Feature unlocked: %s", unlock.Key))
+		case "cosmetic":
+			rewards = append(rewards, fmt.Sprintf("Cosmetic unlocked: %s", unlock.Key))
+		case "item":
+			rewards = append(rewards, fmt.Sprintf("Item: %s x%d", unlock.Key, unlock.Quantity))
+		}
+	}
+	return rewards
+}
+
+func buildLevelProgress(levels []common.LevelDefinition, user common.User) LevelProgress {
+	progress := LevelProgress{
+		Level:     user.Level,
+		TotalXP:   user.XP,
+		Features:  user.Features,
+		Cosmetics: user.Cosmetics,
+		Upcoming:  []common.LevelDefinition{},
+	}
+
+	if current, ok := findLevel(levels, user.Level); ok {
+		progress.LevelName = current.LevelName
+		progress.CurrentLevelXP = current.XPRequired
+	}
+	progress.XPIntoLevel = user.XP - progress.CurrentLevelXP
+
+	next, ok := findLevel(levels, user.Level+1)
+	if !ok {
+		progress.MaxLevel = true
+		progress.ProgressPercent = 100
+		return progress
+	}
+
+	progress.NextLevelXP = next.XPRequired
+	progress.XPToNextLevel = next.XPRequired - user.XP
+	if progress.XPToNextLevel < 0 {
+		progress.XPToNextLevel = 0
+	}
+	if span := next.XPRequired - progress.CurrentLevelXP; span > 0 {
+		percent := float64(progress.XPIntoLevel) / float64(span) * 100
+		progress.ProgressPercent = float64(int(percent*10)) / 10
+	}
+
+	for _, def := range levels {
+		if def.Level > user.Level && len(progress.Upcoming) < upcomingLevelsCount {
+			progress.Upcoming = append(progress.Upcoming, def)
+		}
+	}
+
+	return progress
+}
+
+// GetLevelProgress - GET /user/level/progress
+func (h *Handler) GetLevelProgress(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var user common.User
+	if err := h.db.Select("id", "xp", "level", "features", "cosmetics").First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildLevelProgress(levelCurve.get(h.db), user))
+}
@@ -0,0 +1,62 @@
+package xp
+
+import (
+	"testing"
+
+	"geoanomaly/internal/common"
+)
+
+func testLevelCurve() []common.LevelDefinition {
+	return []common.LevelDefinition{
+		{Level: 1, XPRequired: 0, LevelName: "Novice Explorer", FeaturesUnlocked: common.JSONB{"basic_scanning": true}},
+		{Level: 2, XPRequired: 100, LevelName: "Amateur Seeker", FeaturesUnlocked: common.JSONB{"improved_detection": true}, CosmeticUnlocks: common.JSONB{"bronze_badge": true}},
+		{Level: 3, XPRequired: 250, LevelName: "Dedicated Hunter", ItemRewards: common.JSONB{"Field Kit": map[string]interface{}{"item_type": "gear", "quantity": float64(2)}}},
+		{Level: 4, XPRequired: 500, LevelName: "Skilled Adventurer", CosmeticUnlocks: common.JSONB{"gold_badge": true}},
+	}
+}
+
+func TestLevelForXP(t *testing.T) {
+	levels := testLevelCurve()
+	tests := map[int]int{0: 1, 99: 1, 100: 2, 260: 3, 10000: 4}
+	for totalXP, want := range tests {
+		if got := levelForXP(levels, totalXP); got != want {
+			t.Errorf("levelForXP(%d) = %d; want %d", totalXP, got, want)
+		}
+	}
+}
+
+func TestUnlocksBetweenMultiLevelJump(t *testing.T) {
+	unlocks := unlocksBetween(testLevelCurve(), 1, 4)
+
+	want := []LevelUnlock{
+		{Level: 2, Kind: "feature", Key: "improved_detection"},
+		{Level: 2, Kind: "cosmetic", Key: "bronze_badge"},
+		{Level: 3, Kind: "item", Key: "Field Kit", Quantity: 2},
+		{Level: 4, Kind: "cosmetic", Key: "gold_badge"},
+	}
+	if len(unlocks) != len(want) {
+		t.Fatalf("got %d unlocks; want %d (%v)", len(unlocks), len(want), unlocks)
+	}
+	for i := range want {
+		if unlocks[i] != want[i] {
+			t.Errorf("unlock %d = %+v; want %+v", i, unlocks[i], want[i])
+		}
+	}
+}
+
+func TestBuildLevelProgress(t *testing.T) {
+	levels := testLevelCurve()
+
+	progress := buildLevelProgress(levels, common.User{XP: 175, Level: 2})
+	if progress.XPIntoLevel != 75 || progress.XPToNextLevel != 75 || progress.ProgressPercent != 50 {
+		t.Errorf("unexpected progress: %+v", progress)
+	}
+	if len(progress.Upcoming) != 2 || progress.Upcoming[0].Level != 3 {
+		t.Errorf("unexpected upcoming levels: %+v", progress.Upcoming)
+	}
+
+	maxed := buildLevelProgress(levels, common.User{XP: 900, Level: 4})
+	if !maxed.MaxLevel || maxed.ProgressPercent != 100 {
+		t.Errorf("expected max level progress, got %+v", maxed)
+	}
+}
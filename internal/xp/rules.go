@@ -0,0 +1,256 @@
+package xp
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// XPRule - deklarované pravidlo: BaseXP + PerUnitXP * units (max MaxUnits), celé * Multiplier
+type XPRule struct {
+	Source      string  `json:"source"`
+	Description string  `json:"description"`
+	BaseXP      int     `json:"base_xp"`
+	PerUnitXP   int     `json:"per_unit_xp"`
+	MaxUnits    int     `json:"max_units,omitempty"` // 0 = bez limitu
+	Multiplier  float64 `json:"multiplier"`
+}
+
+// DefaultRules - XP pravidlá mimo zberu artefaktov (artefakty počíta calculateArtifactXP)
+var DefaultRules = map[string]XPRule{
+	SourceGearPickup: {
+		Source:      SourceGearPickup,
+		Description: "Gear pickup, scaled by gear level",
+		BaseXP:      5,
+		PerUnitXP:   3, // za level gearu
+		MaxUnits:    10,
+		Multiplier:  1.0,
+	},
+	SourceZoneDiscovery: {
+		Source:      SourceZoneDiscovery,
+		Description: "First entry into a zone",
+		BaseXP:      25,
+		PerUnitXP:   5, // za tier zóny
+		Multiplier:  1.0,
+	},
+	SourceArtifactType: {
+		Source:      SourceArtifactType,
+		Description: "First collection of a new artifact type",
+		BaseXP:      20,
+		Multiplier:  1.0,
+	},
+	SourceDistance: {
+		Source:      SourceDistance,
+		Description: "Every kilometer walked",
+		BaseXP:      10,
+		Multiplier:  1.0,
+	},
+	SourceZoneClear: {
+		Source:      SourceZoneClear,
+		Description: "Collecting the last item in a zone",
+		BaseXP:      30,
+		PerUnitXP:   10, // za tier zóny
+		Multiplier:  1.0,
+	},
+	SourceLoginStreak: {
+		Source:      SourceLoginStreak,
+		Description: "Daily login, bonus per consecutive day",
+		BaseXP:      10,
+		PerUnitXP:   5, // za deň streaku
+		MaxUnits:    7,
+		Multiplier:  1.0,
+	},
+}
+
+// GetXPRules - GET /game/xp/rules
+func (h *Handler) GetXPRules(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"rules":            h.rules,
+		"rules_count":      len(h.rules),
+		"artifact_formula": artifactFormula.get(h.db),
+		"active_events":    h.activeEvents(time.Now()),
+	})
+}
+
+// bonus za jednotky (level, tier, deň streaku) s limitom
+func (r XPRule) unitBonus(units int) int {
+	if units < 0 {
+		units = 0
+	}
+	if r.MaxUnits > 0 && units > r.MaxUnits {
+		units = r.MaxUnits
+	}
+	return r.PerUnitXP * units
+}
+
+func (h *Handler) applyRule(userID uuid.UUID, source, sourceID string, breakdown XPBreakdown) (*XPResult, error) {
+	rule, ok := h.rules[source]
+	if !ok {
+		return nil, fmt.Errorf("unknown XP rule: %s", source)
+	}
+
+	return h.Grant(XPGrant{
+		UserID:     userID,
+		SourceType: source,
+		SourceID:   sourceID,
+		Breakdown:  breakdown,
+		Multiplier: rule.Multiplier,
+	})
+}
+
+// AwardGearXP - zber gearu, idempotentné podľa ID gearu
+// areaYield (anti-farming výnos bunky) znižuje XP, 0 = plný výnos
+func (h *Handler) AwardGearXP(userID, gearID uuid.UUID, gearLevel int, biome string, zoneTier int, areaYield float64) (*XPResult, error) {
+	rule := h.rules[SourceGearPickup]
+	artifactBreakdown := h.calculateArtifactXP("common", biome, zoneTier)
+
+	return h.Grant(XPGrant{
+		UserID:     userID,
+		SourceType: SourceGearPickup,
+		SourceID:   gearID.String(),
+		Breakdown: XPBreakdown{
+			BaseXP:     rule.BaseXP,
+			LevelBonus: rule.unitBonus(gearLevel),
+			BiomeBonus: artifactBreakdown.BiomeBonus,
+			TierBonus:  artifactBreakdown.TierBonus,
+		},
+		Multiplier: rule.Multiplier * areaMultiplier(areaYield),
+	})
+}
+
+// AwardZoneDiscoveryXP - prvý vstup do zóny
+func (h *Handler) AwardZoneDiscoveryXP(userID, zoneID uuid.UUID, zoneTier int) (*XPResult, error) {
+	rule := h.rules[SourceZoneDiscovery]
+	return h.applyRule(userID, SourceZoneDiscovery, zoneID.String(), XPBreakdown{
+		BaseXP:    rule.BaseXP,
+		TierBonus: rule.unitBonus(zoneTier),
+	})
+}
+
+// AwardArtifactTypeXP - prvý artefakt daného typu (source_id = typ artefaktu)
+func (h *Handler) AwardArtifactTypeXP(userID uuid.UUID, artifactType, rarity string) (*XPResult, error) {
+	rule := h.rules[SourceArtifactType]
+	return h.applyRule(userID, SourceArtifactType, artifactType, XPBreakdown{
+		BaseXP:      rule.BaseXP,
+		RarityBonus: h.calculateArtifactXP(rarity, "", 0).RarityBonus,
+	})
+}
+
+// AwardZoneClearXP - hráč zobral posledný item v zóne
+func (h *Handler) AwardZoneClearXP(userID, zoneID uuid.UUID, zoneTier int) (*XPResult, error) {
+	rule := h.rules[SourceZoneClear]
+	return h.applyRule(userID, SourceZoneClear, zoneID.String(), XPBreakdown{
+		BaseXP:    rule.BaseXP,
+		TierBonus: rule.unitBonus(zoneTier),
+	})
+}
+
+// CreditedDistance - koľko z prejdeného segmentu sa započíta pri danom čase od poslednej polohy
+func CreditedDistance(meters float64, elapsed time.Duration) float64 {
+	seconds := elapsed.Seconds()
+	if meters <= 0 || seconds <= 0 || meters > MaxWalkSegmentMeters {
+		return 0
+	}
+	if meters/seconds > MaxSegmentSpeedMPS {
+		return 0
+	}
+	return math.Min(meters, MaxWalkSpeedMPS*seconds)
+}
+
+// RecordDistance - pripočíta prejdenú vzdialenosť a udelí XP za každý nový celý kilometer
+// Segmenty dlhšie ako MaxWalkSegmentMeters (teleport, GPS skok) sa ignorujú
+func (h *Handler) RecordDistance(userID uuid.UUID, meters float64) ([]*XPResult, error) {
+	if meters <= 0 || meters > MaxWalkSegmentMeters {
+		return nil, nil
+	}
+
+	var before float64
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var user common.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "distance_walked").First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		before = user.DistanceWalked
+		return tx.Model(&common.User{}).Where("id = ?", userID).
+			Update("distance_walked", gorm.Expr("distance_walked + ?", meters)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rule := h.rules[SourceDistance]
+	var results []*XPResult
+	fromKm := int(math.Floor(before / 1000))
+	toKm := int(math.Floor((before + meters) / 1000))
+	for km := fromKm + 1; km <= toKm; km++ {
+		result, err := h.applyRule(userID, SourceDistance, fmt.Sprintf("km:%d", km), XPBreakdown{BaseXP: rule.BaseXP})
+		if err != nil {
+			return results, err
+		}
+		if !result.Duplicate {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// RecordLogin - denný login streak; XP len za prvý login v danom dni (UTC)
+func (h *Handler) RecordLogin(userID uuid.UUID, now time.Time) (*XPResult, int, error) {
+	var user common.User
+	if err := h.db.Select("id", "last_login", "login_streak").First(&user, "id = ?", userID).Error; err != nil {
+		return nil, 0, err
+	}
+
+	today := now.UTC().Format("2006-01-02")
+	streak := nextLoginStreak(user.LastLogin, user.LoginStreak, now)
+
+	if err := h.db.Model(&common.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"last_login":   now,
+		"login_streak": streak,
+	}).Error; err != nil {
+		return nil, streak, err
+	}
+
+	rule := h.rules[SourceLoginStreak]
+	result, err := h.applyRule(userID, SourceLoginStreak, today, XPBreakdown{
+		BaseXP:      rule.BaseXP,
+		StreakBonus: rule.unitBonus(streak - 1),
+	})
+	if err != nil {
+		return nil, streak, err
+	}
+
+	if !result.Duplicate && streak > 1 {
+		log.Printf("🔥 Login streak: user %s - %d days", userID, streak)
+	}
+	return result, streak, nil
+}
+
+// Streak pokračuje, ak posledný login bol včera; dnešný login ho nemení; inak začína od 1
+func nextLoginStreak(lastLogin *time.Time, streak int, now time.Time) int {
+	if lastLogin == nil || streak < 1 {
+		return 1
+	}
+
+	last := lastLogin.UTC().Truncate(24 * time.Hour)
+	today := now.UTC().Truncate(24 * time.Hour)
+
+	switch today.Sub(last) {
+	case 0:
+		return streak
+	case 24 * time.Hour:
+		return streak + 1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,22 @@
+package xp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCreditedDistance(t *testing.T) {
+	if got := CreditedDistance(100, time.Minute); got != 100 {
+		t.Errorf("100m in a minute = %v, want 100", got)
+	}
+	// 3 m/s je nad pešou rýchlosťou, ale pod hranicou zahodenia - započíta sa strop
+	if got := CreditedDistance(180, time.Minute); got != MaxWalkSpeedMPS*60 {
+		t.Errorf("180m in a minute = %v, want capped %v", got, MaxWalkSpeedMPS*60)
+	}
+	if got := CreditedDistance(400, time.Minute); got != 0 {
+		t.Errorf("400m in a minute (vehicle) = %v, want 0", got)
+	}
+	if got := CreditedDistance(50, 0); got != 0 {
+		t.Errorf("segment without elapsed time = %v, want 0", got)
+	}
+}
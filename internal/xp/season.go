@@ -0,0 +1,92 @@
+package xp
+
+import (
+	"time"
+
+	"geoanomaly/internal/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seasonXPFor - do ktorej sezóny a koľko sezónnych XP pripíše nový záznam v ledgeri
+// Reversal ide proti sezóne pôvodného záznamu, nie proti práve bežiacej
+func seasonXPFor(tx *gorm.DB, grant XPGrant, amount int, now time.Time) (*uuid.UUID, int, error) {
+	if amount == 0 || grant.SourceType == SourceSeasonReward {
+		return nil, 0, nil
+	}
+
+	if grant.ReversalOf != nil {
+		var original common.XPLedgerEntry
+		if err := tx.First(&original, "id = ?", *grant.ReversalOf).Error; err != nil {
+			return nil, 0, err
+		}
+		if original.SeasonID == nil {
+			// Záznamy spred season_id - sezóna podľa času pôvodného grantu
+			if original.SourceType == SourceSeasonReward {
+				return nil, 0, nil
+			}
+			season, err := openSeasonAt(tx, original.CreatedAt)
+			if season == nil || err != nil {
+				return nil, 0, err
+			}
+			return &season.ID, -original.Amount, nil
+		}
+
+		// Uzavretá sezóna má zamrznutý rebríček, jej XP sa už nemenia
+		var open int64
+		if err := tx.Model(&common.Season{}).
+			Where("id = ? AND snapshot_at IS NULL AND deleted_at IS NULL", *original.SeasonID).
+			Count(&open).Error; err != nil {
+			return nil, 0, err
+		}
+		if open == 0 {
+			return nil, 0, nil
+		}
+		return original.SeasonID, -original.SeasonXP, nil
+	}
+
+	season, err := openSeasonAt(tx, now)
+	if season == nil || err != nil {
+		return nil, 0, err
+	}
+	return &season.ID, amount, nil
+}
+
+// openSeasonAt - sezóna bežiaca v čase at, ktorá ešte nemá snapshot (nil ak žiadna)
+func openSeasonAt(tx *gorm.DB, at time.Time) (*common.Season, error) {
+	var season common.Season
+	err := tx.Where("starts_at <= ? AND ends_at > ? AND snapshot_at IS NULL AND deleted_at IS NULL", at, at).
+		Order("starts_at DESC").
+		First(&season).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// addSeasonXP - pripočíta sezónne XP do dráhy hráča (beží v Grant transakcii; nikdy nie pod 0)
+func addSeasonXP(tx *gorm.DB, seasonID, userID uuid.UUID, amount int, now time.Time) error {
+	initial := amount
+	if initial < 0 {
+		initial = 0
+	}
+
+	entry := common.PlayerSeason{
+		SeasonID:       seasonID,
+		UserID:         userID,
+		SeasonXP:       initial,
+		ClaimedRewards: common.JSONB{},
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "season_id"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"season_xp":  gorm.Expr("GREATEST(player_seasons.season_xp + ?, 0)", amount),
+			"updated_at": now,
+		}),
+	}).Create(&entry).Error
+}
@@ -1,13 +1,77 @@
 package xp
 
+import "github.com/google/uuid"
+
+// XP source types (xp_ledger.source_type)
+const (
+	SourceArtifactCollect = "artifact_collect"
+	SourceGearPickup      = "gear_pickup"
+	SourceZoneDiscovery   = "zone_discovery"
+	SourceArtifactType    = "artifact_type"
+	SourceDistance        = "distance_walked"
+	SourceZoneClear       = "zone_clear"
+	SourceLoginStreak     = "login_streak"
+	SourceSeasonReward    = "season_reward" // lifetime XP zo sezónnej dráhy (nepočíta sa do sezónnych XP)
+	SourceReversal        = "reversal"
+)
+
+// Dlhší segment medzi dvoma update-mi polohy sa nepočíta do prejdenej vzdialenosti
+const MaxWalkSegmentMeters = 500.0
+
+// Rýchlosť pešieho presunu - vzdialenosť sa započíta najviac MaxWalkSpeedMPS * čas,
+// segment rýchlejší ako MaxSegmentSpeedMPS (auto, MHD, GPS skok) sa zahodí celý
+const (
+	MaxWalkSpeedMPS    = 2.5
+	MaxSegmentSpeedMPS = 4.0
+)
+
 // XP calculation result
 type XPResult struct {
-	XPGained     int          `json:"xp_gained"`
-	TotalXP      int          `json:"total_xp"`
-	CurrentLevel int          `json:"current_level"`
-	LevelUp      bool         `json:"level_up"`
-	Breakdown    XPBreakdown  `json:"xp_breakdown"`
-	LevelUpInfo  *LevelUpInfo `json:"level_up_info,omitempty"`
+	XPGained      int            `json:"xp_gained"`
+	TotalXP       int            `json:"total_xp"`
+	CurrentLevel  int            `json:"current_level"`
+	LevelUp       bool           `json:"level_up"`
+	Breakdown     XPBreakdown    `json:"xp_breakdown"`
+	LevelUpInfo   *LevelUpInfo   `json:"level_up_info,omitempty"`
+	LedgerEntryID *uuid.UUID     `json:"ledger_entry_id,omitempty"`
+	Duplicate     bool           `json:"duplicate,omitempty"` // zdroj už XP dostal (idempotencia)
+	Source        string         `json:"source,omitempty"`
+	SeasonXP      int            `json:"season_xp_gained,omitempty"`
+	Multipliers   *XPMultipliers `json:"xp_multipliers,omitempty"` // zber artefaktu
+}
+
+// XPMultipliers - násobky pri zbere artefaktu (event × tier × gear × diminishing × area + zbierky)
+type XPMultipliers struct {
+	Event        float64  `json:"event"`
+	Events       []string `json:"events,omitempty"`
+	Tier         float64  `json:"tier"`
+	Gear         float64  `json:"gear"`
+	Diminishing  float64  `json:"diminishing"`
+	ZoneCollects int      `json:"zone_collects"` // predchádzajúce zbery v zóne v okne vzorca
+	Area         float64  `json:"area"`          // vyčerpanie bunky mapy
+	Collection   float64  `json:"collection"`    // trvalý bonus zo zbierok (pripočítava sa)
+	Total        float64  `json:"total"`
+}
+
+func (m XPMultipliers) stacked() float64 {
+	return m.Event * m.Tier * m.Gear * m.Diminishing * m.Area
+}
+
+// XPGrant - jediný vstup pre pridelenie XP (všetko ide cez Handler.Grant)
+type XPGrant struct {
+	UserID     uuid.UUID
+	SourceType string
+	SourceID   string // spolu s UserID a SourceType unikátne - opakovaný grant sa ignoruje
+	Breakdown  XPBreakdown
+	Multiplier float64 // 0 = 1.0
+	Amount     int     // ak 0, vypočíta sa z Breakdown * Multiplier
+	Reason     string
+	CreatedBy  *uuid.UUID
+	ReversalOf *uuid.UUID
+}
+
+type ReverseXPRequest struct {
+	Reason string `json:"reason" binding:"required"`
 }
 
 type XPBreakdown struct {
@@ -15,11 +79,19 @@ type XPBreakdown struct {
 	RarityBonus int `json:"rarity_bonus"`
 	BiomeBonus  int `json:"biome_bonus"`
 	TierBonus   int `json:"tier_bonus"`
+	LevelBonus  int `json:"level_bonus,omitempty"`  // gear level
+	StreakBonus int `json:"streak_bonus,omitempty"` // login streak
+}
+
+func (b XPBreakdown) Total() int {
+	return b.BaseXP + b.RarityBonus + b.BiomeBonus + b.TierBonus + b.LevelBonus + b.StreakBonus
 }
 
 type LevelUpInfo struct {
-	OldLevel    int      `json:"old_level"`
-	NewLevel    int      `json:"new_level"`
-	Rewards     []string `json:"rewards"`
-	LevelUpTime int64    `json:"level_up_time"`
+	OldLevel    int           `json:"old_level"`
+	NewLevel    int           `json:"new_level"`
+	LevelName   string        `json:"level_name,omitempty"`
+	Rewards     []string      `json:"rewards"`
+	Unlocks     []LevelUnlock `json:"unlocks,omitempty"`
+	LevelUpTime int64         `json:"level_up_time"`
 }
@@ -39,6 +39,85 @@ func AutoMigrate(db *gorm.DB) error {
 	return nil
 }
 
+// ✅ NEW: MigrateFeatureTables - bezpečné nad existujúcou schémou
+// Vytvára len nové tabuľky a dopĺňa nové stĺpce (ADD COLUMN IF NOT EXISTS)
+func MigrateFeatureTables(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		&common.ZoneHazard{},
+		&common.XPLedgerEntry{},
+		&common.AchievementDefinition{},
+		&common.PlayerAchievement{},
+		&common.QuestTemplate{},
+		&common.PlayerQuest{},
+		&common.Season{},
+		&common.SeasonReward{},
+		&common.PlayerSeason{},
+		&common.SeasonRanking{},
+		&common.CollectionSet{},
+		&common.PlayerCollection{},
+		&common.XPFormula{},
+		&common.XPEvent{},
+		&common.PlayerCellYield{},
+		&common.AreaYieldConfig{},
+		&common.ItemDefinition{},
+		&common.PlayerBuff{},
+		&common.ItemUse{},
+		&common.PlayerLoadout{},
+		&common.CraftingRecipe{},
+		&common.RecipeDiscovery{},
+		&common.CraftingJob{},
+		&common.ItemEscrow{},
+		&common.TradeOffer{},
+		&common.TradeItem{},
+		&common.Wallet{},
+		&common.LedgerTransaction{},
+		&common.LedgerEntry{},
+		&common.MarketListing{},
+		&common.MarketBid{},
+		&common.MarketSale{},
+		&common.VendorTransaction{},
+		&common.OverflowItem{},
+		&common.ItemCollect{},
+	); err != nil {
+		return err
+	}
+
+	return addFeatureColumns(db)
+}
+
+func addFeatureColumns(db *gorm.DB) error {
+	statements := []string{
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS health integer DEFAULT 100`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS distance_walked double precision DEFAULT 0`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS login_streak integer DEFAULT 0`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login timestamptz`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS rewarded_level integer DEFAULT 1`,
+		// Existujúci hráči majú odmeny za dosiahnuté levely už za sebou - bez toho by ďalší level-up
+		// udelil všetko od levelu 1. Každý level-up nastaví rewarded_level >= level, takže update je idempotentný.
+		`UPDATE users SET rewarded_level = level WHERE rewarded_level IS NULL OR rewarded_level < level`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS features jsonb DEFAULT '{}'::jsonb`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS cosmetics jsonb DEFAULT '{}'::jsonb`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone varchar(64) DEFAULT 'UTC'`,
+		`ALTER TABLE level_definitions ADD COLUMN IF NOT EXISTS item_rewards jsonb DEFAULT '{}'::jsonb`,
+		`ALTER TABLE inventory_items ADD COLUMN IF NOT EXISTS definition_key varchar(100)`,
+		`CREATE INDEX IF NOT EXISTS idx_inventory_items_definition_key ON inventory_items (definition_key)`,
+		`ALTER TABLE tier_definitions ADD COLUMN IF NOT EXISTS max_weight double precision DEFAULT 0`, // 0 = bez limitu váhy
+		// radiation_walker počíta rôzne zóny, nie každý vstup. Starý progress (počet vstupov) sa nedá previesť,
+		// preto sa vynuluje - odomknuté tiery ostávajú. Oba príkazy sa vykonajú len kým je definícia ešte "count".
+		`UPDATE player_achievements SET progress = 0, seen = '{}'::jsonb WHERE achievement_key = 'radiation_walker'
+			AND EXISTS (SELECT 1 FROM achievement_definitions WHERE key = 'radiation_walker' AND metric = 'count')`,
+		`UPDATE achievement_definitions SET metric = 'distinct', distinct_field = 'zone_id' WHERE key = 'radiation_walker' AND metric = 'count'`,
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func createSpatialIndexes(db *gorm.DB) error {
 	// Index for zones location
 	if err := db.Exec(`